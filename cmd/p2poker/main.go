@@ -3,16 +3,21 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math/rand"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"p2poker/internal/cluster"
 	"p2poker/internal/engine"
 	"p2poker/internal/netx"
 	"p2poker/internal/protocol"
+	"p2poker/internal/table"
 	"p2poker/pkg/types"
 )
 
@@ -20,7 +25,16 @@ func main() {
 	listen := flag.String("listen", ":7777", "tcp listen addr")
 	peer := flag.String("peer", "", "peer addr to dial (optional)")
 	inproc := flag.Bool("inproc", false, "use in-process loopback network (for single-process demos)")
+	seed := flag.Int64("seed", 0, "seed node randomness for reproducible ids/shuffles (0 = crypto random)")
+	useTLS := flag.Bool("tls", false, "encrypt peer connections with TLS (needs -cert and -key)")
+	certFile := flag.String("cert", "", "TLS certificate (PEM)")
+	keyFile := flag.String("key", "", "TLS private key (PEM)")
+	caFile := flag.String("ca", "", "TLS: only trust peers with certificates signed by these CAs (PEM; empty = encrypt without verifying)")
+	compress := flag.Int("compress", 0, "gzip frames of at least this many bytes (0 = off; every peer must run a build that decodes them)")
+	inboxPolicy := flag.String("inbox", "block", "when an inbox is full: block, drop-oldest or drop-newest (drops are counted in dump)")
+	script := flag.String("exec", "", `run these commands ("cmd1; cmd2; ...") and exit, non-zero if any failed`)
 	flag.Parse()
+	netx.CompressThreshold = *compress
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -28,17 +42,37 @@ func main() {
 	var nw netx.Network
 	if *inproc {
 		nw = netx.NewInproc()
+	} else if *useTLS {
+		cfg, err := netx.LoadTLSConfig(*certFile, *keyFile, *caFile)
+		if err != nil {
+			fmt.Println("tls:", err)
+			os.Exit(1)
+		}
+		if *caFile == "" {
+			fmt.Println("tls: no -ca given; peers are not authenticated")
+		}
+		nw = netx.NewTLS(*listen, cfg)
 	} else {
 		nw = netx.NewTCP(*listen)
 	}
 
-	n := cluster.NewNode(*listen, nw)
+	var src rand.Source
+	if *seed != 0 {
+		src = rand.NewSource(*seed)
+	}
+	n := cluster.NewNode(*listen, nw, src)
+	policy, err := netx.ParseInboxPolicy(*inboxPolicy)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	n.SetInboxPolicy(policy)
 	if err := n.Start(ctx); err != nil {
 		panic(err)
 	}
 
 	if *peer != "" {
-		if tcp, ok := n.Network().(*netx.TCP); ok {
+		if tcp, ok := n.Network().(*netx.TCP); ok { // TLS too
 			if err := tcp.AddPeer(*peer); err != nil {
 				fmt.Println("dial error:", err)
 			}
@@ -48,450 +82,1089 @@ func main() {
 	}
 
 	fmt.Printf("node: %s listening on %s", n.ID, *listen)
-	fmt.Println("type 'help' for commands")
-	repl(ctx, n)
+	var ok bool
+	if *script != "" {
+		fmt.Println()
+		ok = runScript(ctx, n, *script)
+	} else {
+		fmt.Println("type 'help' for commands")
+		ok = repl(ctx, n)
+	}
+	if !ok {
+		cancel()
+		os.Exit(1)
+	}
 }
 
-func repl(ctx context.Context, n *cluster.Node) {
+// repl runs commands from stdin until quit or EOF. Piped rather than typed,
+// it shows no prompt; either way it reports whether every command succeeded.
+func repl(ctx context.Context, n *cluster.Node) bool {
 	s := bufio.NewScanner(os.Stdin)
-	prompt := func() { fmt.Print("> ") }
+	interactive := isTerminal(os.Stdin)
+	prompt := func() {
+		if interactive {
+			fmt.Print("> ")
+		}
+	}
 	prompt()
 	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		if line == "" {
-			prompt()
+		if command(ctx, n, s.Text()) {
+			break
+		}
+		prompt()
+	}
+	return !failed
+}
+
+// runScript runs an -exec script: REPL commands separated by semicolons,
+// each echoed before its output. It stops at quit and reports whether every
+// command succeeded.
+func runScript(ctx context.Context, n *cluster.Node, script string) bool {
+	for _, line := range strings.Split(script, ";") {
+		if line = strings.TrimSpace(line); line == "" {
 			continue
 		}
-		args := strings.Fields(line)
-		switch strings.ToLower(args[0]) {
-		case "help":
-			printHelp()
-		case "whoami":
-			fmt.Println("node:", n.ID)
-		case "create":
-			name := "Table"
-			sb, bb, min := int64(5), int64(10), int64(200)
-			if len(args) > 1 {
-				name = args[1]
-			}
-			if len(args) > 2 {
-				sb = mustI64(args[2])
-			}
-			if len(args) > 3 {
-				bb = mustI64(args[3])
-			}
-			if len(args) > 4 {
-				min = mustI64(args[4])
-			}
-			id, err := n.CreateTable(name, sb, bb, min)
-			if err != nil {
-				fmt.Println("error:", err)
+		fmt.Println(">", line)
+		if command(ctx, n, line) {
+			break
+		}
+	}
+	return !failed
+}
+
+// failed records that a command printed an error, for the exit status of
+// scripts and piped input.
+var failed bool
+
+func fail(a ...any) {
+	failed = true
+	fmt.Println(a...)
+}
+
+func failf(format string, a ...any) {
+	failed = true
+	fmt.Printf(format, a...)
+}
+
+// verdictTimeout bounds how long a command waits to hear whether the
+// authority committed or rejected what it proposed.
+const verdictTimeout = 5 * time.Second
+
+// propose sends a and waits for the authority's verdict (see
+// Table.ProposeWait), so a refusal fails the command, and a script's exit
+// status, on a follower as on the authority.
+func propose(t *table.Table, a protocol.Action) error {
+	return t.ProposeWait(a, verdictTimeout)
+}
+
+// watching holds the event stream each watch command opened, by table, so a
+// table is watched at most once and unwatch can end it.
+var watching = map[protocol.TableID]<-chan table.TableEvent{}
+
+// lastTable is the table last created, discovered or attached: "@" in a
+// command stands for it, so scripts need not know generated ids.
+var lastTable protocol.TableID
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// command runs one command line and reports whether it was quit.
+func command(ctx context.Context, n *cluster.Node, line string) (quit bool) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return false
+	}
+	for i, a := range args {
+		if a == "@" {
+			args[i] = string(lastTable)
+		}
+	}
+	switch strings.ToLower(args[0]) {
+	case "help":
+		printHelp()
+	case "whoami":
+		fmt.Println("node:", n.ID)
+	case "create":
+		name := "Table"
+		sb, bb, min := int64(5), int64(10), int64(200)
+		if len(args) > 1 {
+			name = args[1]
+		}
+		if len(args) > 2 {
+			sb = mustI64(args[2])
+		}
+		if len(args) > 3 {
+			bb = mustI64(args[3])
+		}
+		if len(args) > 4 {
+			min = mustI64(args[4])
+		}
+		cfg := types.TableConfig{Name: name, SmallBlind: sb, BigBlind: bb, MinBuyin: min}
+		if len(args) > 5 {
+			cfg.Variant = strings.ToLower(args[5])
+		}
+		id, err := n.CreateTableConfig(cfg)
+		if err != nil {
+			fail("error:", err)
+		} else {
+			lastTable = id
+			fmt.Println("created:", id)
+		}
+	case "tables":
+		// tables [-v]
+		if len(args) > 1 && args[1] == "-v" {
+			list := n.Manager().ListVerbose(n.ID)
+			if len(list) == 0 {
+				fmt.Println("(no tables)")
 			} else {
-				fmt.Println("created:", id)
-			}
-		case "tables":
-			// tables [-v]
-			if len(args) > 1 && args[1] == "-v" {
-				list := n.Manager().ListVerbose(n.ID)
-				if len(list) == 0 {
-					fmt.Println("(no tables)")
-				} else {
-					for _, it := range list {
-						fmt.Printf("- %s epoch=%d authority=%s is_authority=%v", it.ID, it.Epoch, it.Authority, it.IsAuthority)
-					}
+				for _, it := range list {
+					fmt.Printf("- %s epoch=%d authority=%s is_authority=%v", it.ID, it.Epoch, it.Authority, it.IsAuthority)
 				}
+			}
+		} else {
+			ids := n.Manager().ListIDs()
+			if len(ids) == 0 {
+				fmt.Println("(no tables)")
 			} else {
-				ids := n.Manager().ListIDs()
-				if len(ids) == 0 {
-					fmt.Println("(no tables)")
-				} else {
-					for _, id := range ids {
-						fmt.Println("-", id)
-					}
+				for _, id := range ids {
+					fmt.Println("-", id)
 				}
 			}
-		case "discover":
-			// discover <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: discover <tableID>")
-				break
+		}
+	case "discover":
+		// discover <tableID>
+		if len(args) < 2 {
+			fail("usage: discover <tableID>")
+			break
+		}
+		tid := protocol.TableID(args[1])
+		if id, err := n.DiscoverAndAttach(tid); err != nil {
+			fail("discover error:", err)
+		} else {
+			lastTable = id
+			fmt.Println("discovered and attached:", id)
+		}
+	case "observe":
+		// observe <tableID>  (follow the table without a seat; leave stops)
+		if len(args) < 2 {
+			fail("usage: observe <tableID>")
+			break
+		}
+		tid := protocol.TableID(args[1])
+		if err := n.Observe(tid); err != nil {
+			fail("observe error:", err)
+		} else {
+			lastTable = tid
+			fmt.Println("observing:", tid)
+		}
+	case "attach":
+		if len(args) < 7 {
+			fail("usage: attach <tableID> <name> <sb> <bb> <min> <epoch>")
+			break
+		}
+		tid := protocol.TableID(args[1])
+		cfg := types.TableConfig{Name: args[2], SmallBlind: mustI64(args[3]), BigBlind: mustI64(args[4]), MinBuyin: mustI64(args[5])}
+		epoch := protocol.Epoch(mustU64(args[6]))
+		if err := n.JoinTableRemote(tid, epoch, cfg); err != nil {
+			fail("error:", err)
+		} else {
+			lastTable = tid
+			fmt.Println("attached follower to:", tid)
+		}
+	case "joinable":
+		// joinable <tableID>
+		if len(args) < 2 {
+			fail("usage: joinable <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if ok, reason := t.JoinStatus(n.ID); ok {
+				fmt.Println("joinable: yes")
+			} else {
+				fmt.Println("joinable: no —", reason)
 			}
-			tid := protocol.TableID(args[1])
-			if id, err := n.DiscoverAndAttach(tid); err != nil {
-				fmt.Println("discover error:", err)
+		} else {
+			fail("unknown table")
+		}
+	case "join":
+		// join <tableID> [buyin]  (default is the minimum buy-in)
+		if len(args) < 2 {
+			fail("usage: join <tableID> [buyin]")
+			break
+		}
+		id := protocol.TableID(args[1])
+		var buyin int64
+		if len(args) > 2 {
+			buyin = mustI64(args[2])
+		}
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActJoin, PlayerID: string(n.ID), Amount: buyin}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("discovered and attached:", id)
+				fmt.Println("join proposed on", id)
 			}
-		case "attach":
-			if len(args) < 7 {
-				fmt.Println("usage: attach <tableID> <name> <sb> <bb> <min> <epoch>")
+		} else {
+			fail("unknown table locally; try 'discover <id>'")
+		}
+	case "leave":
+		// leave <tableID> [detach]
+		if len(args) < 2 {
+			fail("usage: leave <tableID> [detach]")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			a := protocol.Action{ID: n.NewActionID(), Type: protocol.ActLeave, PlayerID: string(n.ID)}
+			if len(args) > 2 && args[2] == "detach" {
+				// the LEAVE must be out before the table stops
+				if err := propose(t, a); err != nil {
+					fail("leave error:", err)
+				}
+				if err := n.Manager().DestroyTable(id); err != nil {
+					fail("detach error:", err)
+					break
+				}
+				fmt.Println("left and detached from", id)
 				break
 			}
-			tid := protocol.TableID(args[1])
-			cfg := types.TableConfig{Name: args[2], SmallBlind: mustI64(args[3]), BigBlind: mustI64(args[4]), MinBuyin: mustI64(args[5])}
-			epoch := protocol.Epoch(mustU64(args[6]))
-			if err := n.JoinTableRemote(tid, epoch, cfg); err != nil {
-				fmt.Println("error:", err)
+			if err := propose(t, a); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("attached follower to:", tid)
+				fmt.Println("leave proposed on", id)
 			}
-		case "join":
-			if len(args) < 2 {
-				fmt.Println("usage: join <tableID>")
+		} else {
+			fail("unknown table")
+		}
+	case "kick":
+		// kick <tableID> <playerNodeID>
+		if len(args) < 3 {
+			fail("usage: kick <tableID> <playerNodeID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		target := args[2]
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			if ss.Authority != n.ID {
+				fail("you are not the authority; cannot kick")
 				break
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActJoin, PlayerID: string(n.ID)})
-				fmt.Println("join proposed on", id)
+			meta := map[string]any{"target": target}
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActKick, PlayerID: string(n.ID), Meta: meta}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table locally; try 'discover <id>'")
+				fmt.Println("kick proposed:", target, "on", id)
 			}
-		case "leave":
-			// leave <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: leave <tableID>")
+		} else {
+			fail("unknown table")
+		}
+	case "close":
+		// close <tableID>  (authority-only; tears the table down on every node)
+		if len(args) < 2 {
+			fail("usage: close <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			if ss.Authority != n.ID {
+				fail("you are not the authority; cannot close")
 				break
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActLeave, PlayerID: string(n.ID)})
-				fmt.Println("leave proposed on", id)
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActCloseTable, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("close proposed on", id)
 			}
-		case "kick":
-			// kick <tableID> <playerNodeID>
-			if len(args) < 3 {
-				fmt.Println("usage: kick <tableID> <playerNodeID>")
+		} else {
+			fail("unknown table")
+		}
+	case "reset":
+		// reset <tableID> [keep]  (authority-only, between hands; "keep" leaves players seated with their stacks)
+		if len(args) < 2 {
+			fail("usage: reset <tableID> [keep]")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if t.Snapshot().Authority != n.ID {
+				fail("you are not the authority; cannot reset")
 				break
 			}
-			id := protocol.TableID(args[1])
-			target := args[2]
-			if t, ok := n.Manager().Get(id); ok {
-				ss := t.Snapshot()
-				if ss.Authority != n.ID {
-					fmt.Println("you are not the authority; cannot kick")
-					break
-				}
-				meta := map[string]any{"target": target}
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActKick, PlayerID: string(n.ID), Meta: meta})
-				fmt.Println("kick proposed:", target, "on", id)
+			keep := len(args) > 2 && args[2] == "keep"
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActReset, PlayerID: string(n.ID), Meta: map[string]any{"keep_seats": keep}}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("reset proposed on", id)
 			}
-		case "hole":
-			// hole <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: hole <tableID>")
-				break
+		} else {
+			fail("unknown table")
+		}
+	case "forceact":
+		// forceact <tableID> <playerNodeID> <fold|check>  (authority-only)
+		if len(args) < 4 || (args[3] != "fold" && args[3] != "check") {
+			fail("usage: forceact <tableID> <playerNodeID> <fold|check>")
+			break
+		}
+		typ := protocol.ActFold
+		if args[3] == "check" {
+			typ = protocol.ActCheck
+		}
+		if t, ok := n.Manager().Get(protocol.TableID(args[1])); ok {
+			if err := t.ForceAction(args[2], typ); err != nil {
+				fail("forceact error:", err)
+			} else {
+				fmt.Println("forced", args[3], "for", args[2])
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				// access engine
-				s := t.Eng()
-				if hc, ok := s.Holes[string(n.ID)]; ok && len(hc) == 2 {
-					fmt.Printf("your hole cards: %s %s\n", hc[0].String(), hc[1].String())
-				} else {
-					fmt.Println("no hole cards yet (did you start a hand?)")
+		} else {
+			fail("unknown table")
+		}
+	case "move":
+		// move <fromTableID> <toTableID> <playerNodeID>  (authority on both)
+		if len(args) < 4 {
+			fail("usage: move <fromTableID> <toTableID> <playerNodeID>")
+			break
+		}
+		if err := n.MovePlayer(protocol.TableID(args[1]), protocol.TableID(args[2]), args[3]); err != nil {
+			fail("move error:", err)
+		} else {
+			fmt.Println("moved", args[3], "from", args[1], "to", args[2])
+		}
+	case "tournament":
+		// tournament <payout,payout,...> <tableID>...
+		if len(args) < 3 {
+			fail("usage: tournament <payout,payout,...> <tableID>...")
+			break
+		}
+		var payouts []int64
+		for _, p := range strings.Split(args[1], ",") {
+			payouts = append(payouts, mustI64(p))
+		}
+		var ids []protocol.TableID
+		for _, a := range args[2:] {
+			ids = append(ids, protocol.TableID(a))
+		}
+		if _, err := n.StartTournament(payouts, ids...); err != nil {
+			fail("tournament error:", err)
+		} else {
+			fmt.Println("tournament started on", len(ids), "table(s), payouts", payouts)
+		}
+	case "standings":
+		tr := n.Tournament()
+		if tr == nil {
+			fmt.Println("no tournament running")
+			break
+		}
+		for _, s := range tr.Standings() {
+			place := "alive"
+			if s.Place > 0 {
+				place = fmt.Sprintf("#%d", s.Place)
+			}
+			fmt.Printf("%-6s %s", place, s.Player)
+			if s.Payout > 0 {
+				fmt.Printf("  +%d", s.Payout)
+			}
+			fmt.Println()
+		}
+	case "hole":
+		// hole <tableID>
+		if len(args) < 2 {
+			fail("usage: hole <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			// access engine
+			s := t.Eng()
+			if hc, ok := s.Holes[string(n.ID)]; ok && len(hc) > 0 {
+				fmt.Printf("your hole cards: %s\n", engine.FormatCards(hc))
+				opp := 0
+				for pid, st := range s.Seats {
+					if pid != string(n.ID) && st.InHand && !st.Folded {
+						opp++
+					}
+				}
+				if len(hc) == 2 && opp > 0 && (s.Variant == "" || s.Variant == engine.VariantHoldem) {
+					fmt.Printf("equity: ~%.0f%% against %d random hand(s)\n", 100*engine.Equity(hc, s.Board, opp, 2000, nil), opp)
 				}
 			} else {
-				fmt.Println("unknown table")
-			}
-		case "bet":
-			if len(args) < 3 {
-				fmt.Println("usage: bet <tableID> <amount>")
-				break
+				fmt.Println("no hole cards yet (did you start a hand?)")
 			}
-			id := protocol.TableID(args[1])
-			amt := mustI64(args[2])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActBet, PlayerID: string(n.ID), Amount: amt})
+		} else {
+			fail("unknown table")
+		}
+	case "bet":
+		if len(args) < 3 {
+			fail("usage: bet <tableID> <amount>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		amt := mustI64(args[2])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActBet, PlayerID: string(n.ID), Amount: amt}); err != nil {
+				fail("error:", err)
+			} else {
 				fmt.Println("bet proposed:", amt, "on", id)
+			}
+		} else {
+			fail("unknown table")
+		}
+	case "check":
+		// check <tableID>
+		if len(args) < 2 {
+			fail("usage: check <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActCheck, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("check proposed on", id)
 			}
-		case "check":
-			// check <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: check <tableID>")
-				break
+		} else {
+			fail("unknown table")
+		}
+	case "rebuy":
+		// rebuy <tableID> [amount]  (between hands; default is the minimum buy-in, capped at the max)
+		if len(args) < 2 {
+			fail("usage: rebuy <tableID> [amount]")
+			break
+		}
+		id := protocol.TableID(args[1])
+		var amt int64
+		if len(args) > 2 {
+			amt = mustI64(args[2])
+		}
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActRebuy, PlayerID: string(n.ID), Amount: amt}); err != nil {
+				fail("error:", err)
+			} else {
+				fmt.Println("rebuy proposed on", id)
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActCheck, PlayerID: string(n.ID)})
-				fmt.Println("check proposed on", id)
+		} else {
+			fail("unknown table")
+		}
+	case "results":
+		// results <tableID>
+		if len(args) < 2 {
+			fail("usage: results <tableID>")
+			break
+		}
+		if t, ok := n.Manager().Get(protocol.TableID(args[1])); ok {
+			cfg := t.Snapshot().Cfg
+			for _, r := range t.Results() {
+				net := cfg.FormatChips(r.Net)
+				if r.Net >= 0 {
+					net = "+" + net
+				}
+				fmt.Printf(" - %s bought in=%s stack=%s net=%s\n", r.Player, cfg.FormatChips(r.Buyin), cfg.FormatChips(r.Stack), net)
+			}
+		} else {
+			fail("unknown table")
+		}
+	case "autocheck":
+		// autocheck <tableID> [on|off]
+		if len(args) < 2 {
+			fail("usage: autocheck <tableID> [on|off]")
+			break
+		}
+		id := protocol.TableID(args[1])
+		on := len(args) < 3 || strings.ToLower(args[2]) != "off"
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActAutoCheck, PlayerID: string(n.ID), Meta: map[string]any{"on": on}}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Printf("auto-check on=%v proposed on %s\n", on, id)
 			}
-		case "fold":
-			// fold <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: fold <tableID>")
-				break
+		} else {
+			fail("unknown table")
+		}
+	case "sitout", "sitin":
+		// sitout <tableID> / sitin <tableID>  (keep the seat and stack, skip hands)
+		if len(args) < 2 {
+			failf("usage: %s <tableID>\n", args[0])
+			break
+		}
+		id := protocol.TableID(args[1])
+		typ := protocol.ActSitOut
+		if args[0] == "sitin" {
+			typ = protocol.ActSitIn
+		}
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: typ, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
+			} else {
+				fmt.Printf("%s proposed on %s\n", args[0], id)
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActFold, PlayerID: string(n.ID)})
-				fmt.Println("fold proposed on", id)
+		} else {
+			fail("unknown table")
+		}
+	case "show", "muck":
+		// show <tableID> / muck <tableID>  (what to do with this hand at showdown)
+		if len(args) < 2 {
+			failf("usage: %s <tableID>\n", args[0])
+			break
+		}
+		id := protocol.TableID(args[1])
+		typ := protocol.ActShow
+		if args[0] == "muck" {
+			typ = protocol.ActMuck
+		}
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: typ, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Printf("%s set on %s\n", args[0], id)
 			}
-		case "call":
-			// call <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: call <tableID>")
-				break
+		} else {
+			fail("unknown table")
+		}
+	case "fold":
+		// fold <tableID>
+		if len(args) < 2 {
+			fail("usage: fold <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActFold, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
+			} else {
+				fmt.Println("fold proposed on", id)
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActCall, PlayerID: string(n.ID)})
+		} else {
+			fail("unknown table")
+		}
+	case "call":
+		// call <tableID>
+		if len(args) < 2 {
+			fail("usage: call <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActCall, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
+			} else {
 				fmt.Println("call proposed on", id)
+			}
+		} else {
+			fail("unknown table")
+		}
+	case "raise":
+		// raise <tableID> <amount>
+		if len(args) < 3 {
+			fail("usage: raise <tableID> <amount>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		amt := mustI64(args[2])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActRaise, PlayerID: string(n.ID), Amount: amt}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("raise proposed:", amt, "on", id)
 			}
-		case "raise":
-			// raise <tableID> <amount>
-			if len(args) < 3 {
-				fmt.Println("usage: raise <tableID> <amount>")
+		} else {
+			fail("unknown table")
+		}
+	case "watch":
+		// watch <tableID>  (print the table's events as they happen)
+		if len(args) < 2 {
+			fail("usage: watch <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if _, dup := watching[id]; dup {
+				fmt.Println("already watching", id)
 				break
 			}
-			id := protocol.TableID(args[1])
-			amt := mustI64(args[2])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActRaise, PlayerID: string(n.ID), Amount: amt})
-				fmt.Println("raise proposed:", amt, "on", id)
-			} else {
-				fmt.Println("unknown table")
-			}
-		case "state":
-			// state [-v] <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: state [-v] <tableID>")
+			evs := t.Subscribe("")
+			watching[id] = evs
+			go func() {
+				for ev := range evs {
+					fmt.Println(formatEvent(ev))
+				}
+			}()
+			fmt.Println("watching", id)
+		} else {
+			fail("unknown table")
+		}
+	case "unwatch":
+		// unwatch <tableID>  (stop printing what watch started)
+		if len(args) < 2 {
+			fail("usage: unwatch <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		evs, ok := watching[id]
+		if !ok {
+			fail("not watching", id)
+			break
+		}
+		if t, ok := n.Manager().Get(id); ok {
+			t.Unsubscribe(evs)
+		}
+		delete(watching, id)
+		fmt.Println("stopped watching", id)
+	case "state":
+		// state [-v] <tableID>
+		if len(args) < 2 {
+			fail("usage: state [-v] <tableID>")
+			break
+		}
+		verbose := false
+		tidIdx := 1
+		if args[1] == "-v" {
+			verbose = true
+			if len(args) < 3 {
+				fail("usage: state -v <tableID>")
 				break
 			}
-			verbose := false
-			tidIdx := 1
-			if args[1] == "-v" {
-				verbose = true
-				if len(args) < 3 {
-					fmt.Println("usage: state -v <tableID>")
-					break
+			tidIdx = 2
+		}
+		id := protocol.TableID(args[tidIdx])
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			fmt.Printf("table=%s epoch=%d seq=%d auth=%s cfg={SB=%d BB=%d}\n",
+				id, ss.Epoch, ss.Seq, ss.Authority, ss.Cfg.SmallBlind, ss.Cfg.BigBlind)
+			if sched := ss.Cfg.BlindSchedule; len(sched) > 0 {
+				level, left := t.LevelRemaining()
+				lv := sched[level]
+				next := "final level"
+				if left > 0 {
+					next = "next level in " + left.Round(time.Second).String()
 				}
-				tidIdx = 2
+				fmt.Printf("level %d: blinds %s/%s ante %s, %s\n",
+					lv.Level, ss.Cfg.FormatChips(lv.SB), ss.Cfg.FormatChips(lv.BB), ss.Cfg.FormatChips(lv.Ante), next)
 			}
-			id := protocol.TableID(args[tidIdx])
-			if t, ok := n.Manager().Get(id); ok {
-				ss := t.Snapshot()
-				fmt.Printf("table=%s epoch=%d seq=%d auth=%s cfg={SB=%d BB=%d}\n",
-					id, ss.Epoch, ss.Seq, ss.Authority, ss.Cfg.SmallBlind, ss.Cfg.BigBlind)
 
-				// Pull live engine summary for nicer view
-				summary := t.Eng().Summary()
+			// Pull live engine summary for nicer view
+			summary := t.Eng().Summary()
 
-				fmt.Printf("phase=%s pot=%d dealer=%s turn=%s\n",
-					summary.Phase, summary.Pot, summary.Dealer, summary.Turn)
+			fmt.Printf("phase=%s pot=%s dealer=%s turn=%s\n",
+				summary.Phase, ss.Cfg.FormatChips(summary.Pot), summary.Dealer, summary.Turn)
+			if v := t.ViewFor(n.ID); !v.TurnDeadline.IsZero() {
+				fmt.Printf("time to act: %s\n", v.TurnRemaining.Round(time.Second))
+			}
+			if len(ss.Observers) > 0 {
+				fmt.Printf("observers: %s\n", strings.Join(ss.Observers, ", "))
+			}
 
-				if verbose {
-					fmt.Println("seats:")
-					for _, sv := range summary.Seats {
-						marks := ""
-						if sv.Player == summary.Turn {
-							marks += " ←turn"
-						}
-						if sv.Player == summary.Dealer {
-							if marks != "" {
-								marks += ", "
-							}
-							marks += "dealer"
+			if verbose {
+				fmt.Println("seats:")
+				for _, sv := range summary.Seats {
+					marks := ""
+					if sv.Player == summary.Turn {
+						marks += " ←turn"
+					}
+					if sv.Player == summary.Dealer {
+						if marks != "" {
+							marks += ", "
 						}
+						marks += "dealer"
+					}
 
-						flags := ""
-						if sv.Folded {
-							flags += " folded"
-						}
-						if sv.AllIn {
-							flags += " all-in"
-						}
-						if sv.InHand && !sv.Folded {
-							flags += " in-hand"
-						}
-						if flags != "" {
-							flags = " [" + strings.TrimSpace(flags) + "]"
-						}
+					flags := ""
+					if sv.Folded {
+						flags += " folded"
+					}
+					if sv.AllIn {
+						flags += " all-in"
+					}
+					if sv.InHand && !sv.Folded {
+						flags += " in-hand"
+					}
+					if flags != "" {
+						flags = " [" + strings.TrimSpace(flags) + "]"
+					}
 
-						fmt.Printf(" - %s stack=%d committed=%d%s%s\n",
-							sv.Player, sv.Stack, sv.Committed, flags, marks)
+					fmt.Printf(" - %s stack=%s committed=%s invested=%s%s%s\n",
+						sv.Player, ss.Cfg.FormatChips(sv.Stack), ss.Cfg.FormatChips(sv.Committed), ss.Cfg.FormatChips(sv.TotalCommitted), flags, marks)
+				}
+				s := t.Eng()
+				if hc := s.Holes[string(n.ID)]; len(hc) == 2 && (s.Variant == "" || s.Variant == engine.VariantHoldem) {
+					outs := engine.OutsByCategory(hc, s.Board)
+					for cat := engine.CatRoyalFlush; cat > engine.CatHighCard; cat-- {
+						if cs := outs[cat]; len(cs) > 0 {
+							fmt.Printf("outs to %s: %d (%s)\n", cat, len(cs), engine.FormatCards(cs))
+						}
 					}
-				} else {
-					fmt.Println("(use 'state -v <tableID>' for stacks/flags)")
 				}
 			} else {
-				fmt.Println("unknown table")
-			}
-		case "start":
-			if len(args) < 2 {
-				fmt.Println("usage: start <tableID>")
-				break
+				fmt.Println("(use 'state -v <tableID>' for stacks/flags)")
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActStartHand, PlayerID: string(n.ID)})
-				fmt.Println("hand start proposed on", id)
+		} else {
+			fail("unknown table")
+		}
+	case "start":
+		if len(args) < 2 {
+			fail("usage: start <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActStartHand, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("hand start proposed on", id)
 			}
-		case "board":
-			// board <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: board <tableID>")
+		} else {
+			fail("unknown table")
+		}
+	case "board":
+		// board <tableID>
+		if len(args) < 2 {
+			fail("usage: board <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			s := t.Eng()
+			if s.Variant == engine.VariantStud {
+				fmt.Println("Board: (stud) — no community cards; showing upcards")
+				for _, pid := range s.Order {
+					fmt.Printf(" - %s: %s\n", pid, engine.FormatCards(s.Upcards[pid]))
+				}
 				break
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				s := t.Eng()
-				b := s.Board
-				var flop, turn, river string
-				if len(b) >= 3 {
-					flop = fmt.Sprintf("%s %s %s", b[0].String(), b[1].String(), b[2].String())
-				}
-				if len(b) >= 4 {
-					turn = b[3].String()
-				}
-				if len(b) >= 5 {
-					river = b[4].String()
-				}
+			b := s.Board
+			var flop, turn, river string
+			if len(b) >= 3 {
+				flop = fmt.Sprintf("%s %s %s", b[0].String(), b[1].String(), b[2].String())
+			}
+			if len(b) >= 4 {
+				turn = b[3].String()
+			}
+			if len(b) >= 5 {
+				river = b[4].String()
+			}
 
-				switch s.Phase {
-				case engine.PhasePreflop:
-					fmt.Println("Board: (preflop) — no community cards yet")
-				case engine.PhaseFlop:
-					fmt.Printf("Board: [flop] %s\n", flop)
-				case engine.PhaseTurn:
-					fmt.Printf("Board: [flop] %s  |  [turn] %s\n", flop, turn)
-				case engine.PhaseRiver, engine.PhaseShowdown:
-					fmt.Printf("Board: [flop] %s  |  [turn] %s  |  [river] %s\n", flop, turn, river)
-				default:
-					// fallback if somehow out of band
-					if len(b) == 0 {
-						fmt.Println("Board: (empty)")
-					} else if len(b) <= 3 {
-						fmt.Printf("Board: %s\n", flop)
-					} else if len(b) == 4 {
-						fmt.Printf("Board: %s  |  %s\n", flop, turn)
-					} else {
-						fmt.Printf("Board: %s  |  %s  |  %s\n", flop, turn, river)
-					}
+			switch s.Phase {
+			case engine.PhasePreflop:
+				fmt.Println("Board: (preflop) — no community cards yet")
+			case engine.PhaseFlop:
+				fmt.Printf("Board: [flop] %s\n", flop)
+			case engine.PhaseTurn:
+				fmt.Printf("Board: [flop] %s  |  [turn] %s\n", flop, turn)
+			case engine.PhaseRiver, engine.PhaseShowdown:
+				fmt.Printf("Board: [flop] %s  |  [turn] %s  |  [river] %s\n", flop, turn, river)
+			default:
+				// fallback if somehow out of band
+				if len(b) == 0 {
+					fmt.Println("Board: (empty)")
+				} else if len(b) <= 3 {
+					fmt.Printf("Board: %s\n", flop)
+				} else if len(b) == 4 {
+					fmt.Printf("Board: %s  |  %s\n", flop, turn)
+				} else {
+					fmt.Printf("Board: %s  |  %s  |  %s\n", flop, turn, river)
 				}
-			} else {
-				fmt.Println("unknown table")
 			}
-		case "advance":
-			if len(args) < 2 {
-				fmt.Println("usage: advance <tableID>")
+		} else {
+			fail("unknown table")
+		}
+	case "simulate":
+		// simulate <tableID> <hands>  (authority; random legal bot play)
+		if len(args) < 3 {
+			fail("usage: simulate <tableID> <hands>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			res, err := t.SimulateHands(int(mustI64(args[2])), time.Now().UnixNano())
+			if err != nil {
+				fail("simulate error:", err)
 				break
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActAdvance, PlayerID: string(n.ID)})
-				fmt.Println("advance proposed on", id)
-			} else {
-				fmt.Println("unknown table")
+			fmt.Printf("simulated %d hands\n", res.Hands)
+			for pid, stack := range res.Stacks {
+				fmt.Printf(" - %s stack=%d\n", pid, stack)
 			}
-		case "showdown":
-			// showdown <tableID>  (authority-only shortcut, debugging)
-			if len(args) < 2 {
-				fmt.Println("usage: showdown <tableID>")
-				break
-			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				ss := t.Snapshot()
-				if ss.Authority != n.ID {
-					fmt.Println("you are not the authority; cannot showdown")
-					break
-				}
-				t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActShowdown, PlayerID: string(n.ID)})
-				fmt.Println("showdown proposed on", id)
+			if res.Drift != nil {
+				fmt.Println("chips NOT conserved:", res.Drift)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("chips conserved")
 			}
-
-		case "snapshot":
-			if len(args) < 2 {
-				fmt.Println("usage: snapshot <tableID>")
-				break
+		} else {
+			fail("unknown table")
+		}
+	case "rabbit":
+		// rabbit <tableID>  (authority; needs AllowRabbitHunt)
+		if len(args) < 2 {
+			fail("usage: rabbit <tableID>")
+			break
+		}
+		if t, ok := n.Manager().Get(protocol.TableID(args[1])); ok {
+			if cs := t.RabbitHunt(); cs != nil {
+				fmt.Println("would have come:", engine.FormatCards(cs))
+			} else {
+				fmt.Println("nothing to reveal (hand running, board complete, not authority, or rabbit hunt disabled)")
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				ss := t.Snapshot()
-				fmt.Printf("table %s epoch=%d seq=%d authority=%s cfg={%s SB=%d BB=%d}", id, ss.Epoch, ss.Seq, ss.Authority, ss.Cfg.Name, ss.Cfg.SmallBlind, ss.Cfg.BigBlind)
+		} else {
+			fail("unknown table")
+		}
+	case "advance":
+		if len(args) < 2 {
+			fail("usage: advance <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActAdvance, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("advance proposed on", id)
 			}
-		case "epoch":
-			// epoch <tableID>
-			if len(args) < 2 {
-				fmt.Println("usage: epoch <tableID>")
+		} else {
+			fail("unknown table")
+		}
+	case "showdown":
+		// showdown <tableID>  (authority-only shortcut, debugging)
+		if len(args) < 2 {
+			fail("usage: showdown <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			if ss.Authority != n.ID {
+				fail("you are not the authority; cannot showdown")
 				break
 			}
-			id := protocol.TableID(args[1])
-			if t, ok := n.Manager().Get(id); ok {
-				ss := t.Snapshot()
-				isAuth := ss.Authority == n.ID
-				fmt.Printf("epoch=%d authority=%s is_authority=%v", ss.Epoch, ss.Authority, isAuth)
+			if err := propose(t, protocol.Action{ID: n.NewActionID(), Type: protocol.ActShowdown, PlayerID: string(n.ID)}); err != nil {
+				fail("error:", err)
 			} else {
-				fmt.Println("unknown table")
+				fmt.Println("showdown proposed on", id)
 			}
-		case "addpeer":
-			if len(args) < 2 {
-				fmt.Println("usage: addpeer <addr>")
+		} else {
+			fail("unknown table")
+		}
+
+	case "snapshot":
+		if len(args) < 2 {
+			fail("usage: snapshot <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			fmt.Printf("table %s epoch=%d seq=%d authority=%s cfg={%s SB=%d BB=%d}", id, ss.Epoch, ss.Seq, ss.Authority, ss.Cfg.Name, ss.Cfg.SmallBlind, ss.Cfg.BigBlind)
+		} else {
+			fail("unknown table")
+		}
+	case "epoch":
+		// epoch <tableID>
+		if len(args) < 2 {
+			fail("usage: epoch <tableID>")
+			break
+		}
+		id := protocol.TableID(args[1])
+		if t, ok := n.Manager().Get(id); ok {
+			ss := t.Snapshot()
+			isAuth := ss.Authority == n.ID
+			fmt.Printf("epoch=%d authority=%s is_authority=%v", ss.Epoch, ss.Authority, isAuth)
+		} else {
+			fail("unknown table")
+		}
+	case "dump":
+		// dump [file]  (diagnostics JSON)
+		path := "p2poker-dump.json"
+		if len(args) > 1 {
+			path = args[1]
+		}
+		b, err := n.Diagnostics()
+		if err != nil {
+			fail("dump error:", err)
+			break
+		}
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			fail("dump error:", err)
+		} else {
+			fmt.Println("diagnostics written to", path)
+		}
+	case "log":
+		// log <tableID> [file]  (export this node's applied history for difflog)
+		if len(args) < 2 {
+			fail("usage: log <tableID> [file]")
+			break
+		}
+		t, ok := n.Manager().Get(protocol.TableID(args[1]))
+		if !ok {
+			fail("unknown table")
+			break
+		}
+		path := fmt.Sprintf("p2poker-log-%s.json", n.ID)
+		if len(args) > 2 {
+			path = args[2]
+		}
+		b, err := json.MarshalIndent(t.Log(), "", "  ")
+		if err == nil {
+			err = os.WriteFile(path, b, 0o644)
+		}
+		if err != nil {
+			fail("log error:", err)
+		} else {
+			fmt.Println("log written to", path)
+		}
+	case "difflog":
+		// difflog <fileA> <fileB>  (compare two exported logs)
+		if len(args) < 3 {
+			fail("usage: difflog <fileA> <fileB>")
+			break
+		}
+		var exs [2]table.LogExport
+		var err error
+		for i, path := range args[1:3] {
+			var b []byte
+			if b, err = os.ReadFile(path); err == nil {
+				err = json.Unmarshal(b, &exs[i])
+			}
+			if err != nil {
 				break
 			}
-			if tcp, ok := n.Network().(*netx.TCP); ok {
-				if err := tcp.AddPeer(args[1]); err != nil {
-					fmt.Println("dial error:", err)
-				} else {
-					fmt.Println("peer added")
-				}
+		}
+		if err != nil {
+			fail("difflog error:", err)
+			break
+		}
+		seq, same := table.DiffExports(exs[0], exs[1])
+		if same {
+			fmt.Println("logs agree on every common seq")
+			break
+		}
+		fmt.Println("first divergence at seq", seq)
+		for _, ex := range exs {
+			if i := seq - ex.Base - 1; i < uint64(len(ex.Actions)) {
+				a := ex.Actions[i]
+				fmt.Printf("  %s: %s %s amount=%d id=%s\n", ex.Node, a.Type, a.PlayerID, a.Amount, a.ID)
+			}
+		}
+	case "addpeer":
+		if len(args) < 2 {
+			fail("usage: addpeer <addr>")
+			break
+		}
+		if tcp, ok := n.Network().(*netx.TCP); ok {
+			if err := tcp.AddPeer(args[1]); err != nil {
+				fail("dial error:", err)
 			} else {
-				fmt.Println("addpeer only supported in TCP mode")
+				fmt.Println("peer added")
 			}
-		case "quit", "exit":
-			fmt.Println("bye")
-			return
-		default:
-			fmt.Println("unknown command; type 'help'")
+		} else {
+			fmt.Println("addpeer only supported in TCP mode")
 		}
-		prompt()
+	case "sleep":
+		// sleep <duration>  (scripts: give the network time to catch up)
+		if len(args) < 2 {
+			fail("usage: sleep <duration>")
+			break
+		}
+		d, err := time.ParseDuration(args[1])
+		if err != nil {
+			fail("error:", err)
+			break
+		}
+		time.Sleep(d)
+	case "quit", "exit":
+		fmt.Println("bye")
+		return true
+	default:
+		fail("unknown command; type 'help'")
 	}
+	return false
 }
 
 func printHelp() {
 	fmt.Println(`commands:
   whoami
-  create <name> [sb bb min]
+  create <name> [sb bb min [variant]]
 	tables
   discover <tableID>
+  observe <tableID>
   attach <tableID> <name> <sb> <bb> <min> <epoch>
-  join <tableID>
-	leave <tableID>
+  join <tableID> [buyin]
+  joinable <tableID>
+	leave <tableID> [detach]
 	kick <tableID> <playerNodeID>
+	close <tableID>
+	reset <tableID> [keep]
+	forceact <tableID> <playerNodeID> <fold|check>
+	move <fromTableID> <toTableID> <playerNodeID>
+  tournament <payout,payout,...> <tableID>...
+  standings
 	hole <tableID>
   bet <tableID> <amount>
 	check <tableID>
+	autocheck <tableID> [on|off]
+  sitout <tableID>
+  sitin <tableID>
+  rebuy <tableID> [amount]
+  results <tableID>
   fold <tableID>
+  show <tableID>
+  muck <tableID>
 	call <tableID>
   raise <tableID> <amount>
   state <tableID>
+  watch <tableID>
+  unwatch <tableID>
   start <tableID>
 	board <tableID>
+	rabbit <tableID>
   advance <tableID>
 	showdown <tableID>
+  simulate <tableID> <hands>
   snapshot <tableID>
   epoch <tableID>
   addpeer <addr>
-  quit`)
+  dump [file]
+  log <tableID> [file]
+  difflog <fileA> <fileB>
+  sleep <duration>
+  quit
+(@ stands for the table last created, discovered, observed or attached)`)
+}
+
+// formatEvent renders a table event as one line for watch.
+func formatEvent(ev table.TableEvent) string {
+	head := fmt.Sprintf("[%s #%d] %s", ev.Table, ev.Seq, ev.Kind)
+	switch ev.Kind {
+	case table.EvHandStarted, table.EvPhaseAdvanced, table.EvTurnChanged:
+		return fmt.Sprintf("%s phase=%s pot=%d dealer=%s turn=%s", head, ev.Phase, ev.Pot, ev.Dealer, ev.Turn)
+	case table.EvPlayerActed:
+		return fmt.Sprintf("%s %s %s %d (pot %d)", head, ev.Player, ev.Action, ev.Amount, ev.Pot)
+	case table.EvBoardChanged:
+		return fmt.Sprintf("%s +%s board=%s", head, engine.FormatCards(ev.Cards), engine.FormatCards(ev.Board))
+	case table.EvRabbitHunt:
+		return fmt.Sprintf("%s %s", head, engine.FormatCards(ev.Cards))
+	case table.EvShowdown:
+		if ev.Showdown == nil {
+			return head
+		}
+		var b strings.Builder
+		b.WriteString(head)
+		for _, w := range ev.Showdown.Winners {
+			fmt.Fprintf(&b, " %s+%d", w.Player, w.Won)
+		}
+		shown := make([]string, 0, len(ev.Showdown.Shown))
+		for pid := range ev.Showdown.Shown {
+			shown = append(shown, pid)
+		}
+		sort.Strings(shown)
+		for _, pid := range shown {
+			fmt.Fprintf(&b, " %s:[%s]", pid, engine.FormatCards(ev.Showdown.Shown[pid]))
+		}
+		return b.String()
+	case table.EvSeedRevealed:
+		return fmt.Sprintf("%s %d", head, ev.Seed)
+	}
+	return fmt.Sprintf("%s %s", head, ev.Player) // PLAYER_BUSTED, AUTHORITY_CHANGED
 }
 
 func mustI64(s string) int64  { v, _ := strconv.ParseInt(s, 10, 64); return v }
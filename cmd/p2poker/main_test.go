@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"p2poker/internal/cluster"
+	"p2poker/internal/engine"
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+)
+
+// startNode runs a cluster node on a free loopback port.
+func startNode(t *testing.T, ctx context.Context) (*cluster.Node, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	n := cluster.NewNode(addr, netx.NewTCP(addr), nil)
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return n, addr
+}
+
+func TestScriptFailsWhenTheAuthorityRejectsAFollowersAction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auth, addr := startNode(t, ctx)
+	follower, _ := startNode(t, ctx)
+	failed = false
+	t.Cleanup(func() { failed = false })
+
+	if !runScript(ctx, auth, "create e2e 1 2 100; join @") {
+		t.Fatal("authority's script failed")
+	}
+	id := string(lastTable)
+	if !runScript(ctx, follower, "addpeer "+addr+"; sleep 200ms; discover "+id+"; sleep 200ms") {
+		t.Fatal("follower's discover failed")
+	}
+	// no hand is running: the authority refuses the bet
+	if runScript(ctx, follower, "bet "+id+" 50") {
+		t.Fatal("a bet the authority rejected left the script succeeding")
+	}
+}
+
+func TestScriptCreatesATableAndStartsAHand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n, addr := startNode(t, ctx)
+	peer, _ := startNode(t, ctx)
+	failed = false
+	t.Cleanup(func() { failed = false })
+
+	if !runScript(ctx, n, "create t 1 2 100; join @") {
+		t.Fatal("create and join failed")
+	}
+	id := string(lastTable)
+	if !runScript(ctx, peer, "addpeer "+addr+"; sleep 200ms; discover "+id+"; sleep 200ms; join @") {
+		t.Fatal("the peer's join failed")
+	}
+	if !runScript(ctx, n, "start @") {
+		t.Fatal("start failed with two players seated")
+	}
+
+	tb, ok := n.Manager().Get(protocol.TableID(id))
+	if !ok {
+		t.Fatal("table gone")
+	}
+	var ss engine.EngineSnapshot
+	if err := json.Unmarshal(tb.Export().Snapshot.EngineJSON, &ss); err != nil {
+		t.Fatal(err)
+	}
+	if !ss.HandActive {
+		t.Fatal("start succeeded but no hand is running")
+	}
+}
+
+func TestWatchOpensOneStreamThatUnwatchCloses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n, _ := startNode(t, ctx)
+	failed = false
+	t.Cleanup(func() { failed = false })
+
+	if !runScript(ctx, n, "create w; watch @; watch @") {
+		t.Fatal("watch failed")
+	}
+	id := lastTable
+	evs, ok := watching[id]
+	if !ok {
+		t.Fatal("watch kept no stream")
+	}
+	if !runScript(ctx, n, "unwatch @") {
+		t.Fatal("unwatch failed")
+	}
+	for closed := false; !closed; {
+		select {
+		case _, open := <-evs:
+			closed = !open
+		case <-time.After(time.Second):
+			t.Fatal("unwatch left the stream open")
+		}
+	}
+	if runScript(ctx, n, "unwatch @") {
+		t.Fatal("unwatch of a table not watched succeeded")
+	}
+}
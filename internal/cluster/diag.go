@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"encoding/json"
+
+	"p2poker/internal/table"
+)
+
+// peerLister is implemented by transports that know their peers (TCP).
+type peerLister interface {
+	PeerAddrs() []string
+}
+
+// Diagnostics is a full JSON dump of this node: identity, every local table
+// (seq/epoch/authority, summary, recent log) and the transport's peers.
+func (n *Node) Diagnostics() ([]byte, error) {
+	dump := struct {
+		Node   string       `json:"node"`
+		Addr   string       `json:"addr"`
+		Tables []table.Diag `json:"tables"`
+		Peers  []string     `json:"peers"`
+		Outbox [2]int       `json:"outbox"`  // queued, capacity
+		Drops  [2]uint64    `json:"dropped"` // by the inbox policy: transport, tables
+	}{Node: string(n.ID), Addr: n.Addr, Tables: []table.Diag{}, Peers: []string{}}
+	dump.Outbox = [2]int{n.net.OutboxLen(), n.net.OutboxCap()}
+	dump.Drops[0], dump.Drops[1] = n.InboxDropped()
+
+	for _, id := range n.mgr.ListIDs() {
+		t, ok := n.mgr.Get(id)
+		if !ok {
+			continue
+		}
+		if d, ok := t.Diagnostics(); ok {
+			dump.Tables = append(dump.Tables, d)
+		}
+	}
+	if pl, ok := n.net.(peerLister); ok {
+		dump.Peers = pl.PeerAddrs()
+	}
+	return json.MarshalIndent(dump, "", "  ")
+}
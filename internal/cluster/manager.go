@@ -1,10 +1,15 @@
 package cluster
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
+	"io"
+	"os"
 	"sort"
 	"sync"
 
+	"p2poker/internal/netx"
 	"p2poker/internal/protocol"
 	"p2poker/internal/table"
 	"p2poker/pkg/types"
@@ -13,6 +18,7 @@ import (
 type TableManager struct {
 	self   protocol.NodeID
 	clock  *protocol.Lamport
+	ids    *protocol.IDGen
 	router *Router
 	netOut chan<- protocol.NetMessage
 
@@ -20,8 +26,8 @@ type TableManager struct {
 	tables map[protocol.TableID]*table.Table
 }
 
-func NewTableManager(self protocol.NodeID, clock *protocol.Lamport, router *Router, netOut chan<- protocol.NetMessage) *TableManager {
-	return &TableManager{self: self, clock: clock, router: router, netOut: netOut, tables: make(map[protocol.TableID]*table.Table)}
+func NewTableManager(self protocol.NodeID, clock *protocol.Lamport, ids *protocol.IDGen, router *Router, netOut chan<- protocol.NetMessage) *TableManager {
+	return &TableManager{self: self, clock: clock, ids: ids, router: router, netOut: netOut, tables: make(map[protocol.TableID]*table.Table)}
 }
 
 func (m *TableManager) CreateLocalAuthorityTable(id protocol.TableID, cfg types.TableConfig) (*table.Table, error) {
@@ -31,7 +37,8 @@ func (m *TableManager) CreateLocalAuthorityTable(id protocol.TableID, cfg types.
 		return nil, errors.New("table exists")
 	}
 	in := make(chan protocol.NetMessage, 256)
-	t := table.New(id, m.self, cfg, true /*authority*/, 0 /*epoch*/, m.clock, in, m.netOut)
+	t := table.New(id, m.self, cfg, true /*authority*/, 0 /*epoch*/, m.clock, m.ids, in, m.netOut)
+	t.OnClose(func() { _ = m.DestroyTable(id) })
 	m.tables[id] = t
 	m.router.Register(id, in)
 	go t.Run()
@@ -45,13 +52,92 @@ func (m *TableManager) AttachFollowerTable(id protocol.TableID, cfg types.TableC
 		return nil, errors.New("table exists")
 	}
 	in := make(chan protocol.NetMessage, 256)
-	t := table.New(id, m.self, cfg, false /*authority*/, epoch, m.clock, in, m.netOut)
+	t := table.New(id, m.self, cfg, false /*authority*/, epoch, m.clock, m.ids, in, m.netOut)
+	t.OnClose(func() { _ = m.DestroyTable(id) })
 	m.tables[id] = t
 	m.router.Register(id, in)
 	go t.Run()
 	return t, nil
 }
 
+// ImportTable recreates a table from an Export (see Node.Import) and starts
+// it, as authority or follower as it was exported.
+func (m *TableManager) ImportTable(ex table.Export) (*table.Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.tables[ex.ID]; exists {
+		return nil, errors.New("table exists")
+	}
+	in := make(chan protocol.NetMessage, 256)
+	t := table.New(ex.ID, m.self, ex.Snapshot.Cfg, ex.Authority, ex.Snapshot.Epoch, m.clock, m.ids, in, m.netOut)
+	if err := t.Restore(ex); err != nil {
+		return nil, err
+	}
+	t.OnClose(func() { _ = m.DestroyTable(ex.ID) })
+	m.tables[ex.ID] = t
+	m.router.Register(ex.ID, in)
+	go t.Run()
+	return t, nil
+}
+
+// ReplayTable rebuilds an authority table from the action log at path (see
+// table.Replay) and starts it; the table keeps appending to the same log.
+func (m *TableManager) ReplayTable(path string) (*table.Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head, err := netx.Decode(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+	if head.Type != protocol.MsgSnapshot || head.State == nil {
+		return nil, fmt.Errorf("replay %s: log does not start with a snapshot", path)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.tables[head.Table]; exists {
+		return nil, errors.New("table exists")
+	}
+	cfg := head.State.Cfg
+	cfg.LogPath = path
+	in := make(chan protocol.NetMessage, 256)
+	t := table.New(head.Table, m.self, cfg, true, head.State.Epoch, m.clock, m.ids, in, m.netOut)
+	if err := t.Replay(f); err != nil {
+		return nil, err
+	}
+	for _, a := range t.Log().Actions {
+		m.ids.Observe(a.ID)
+	}
+	t.OnClose(func() { _ = m.DestroyTable(head.Table) })
+	m.tables[head.Table] = t
+	m.router.Register(head.Table, in)
+	go t.Run()
+	return t, nil
+}
+
+// DestroyTable stops a table's event loop, unregisters it from the router and
+// forgets it locally. Other nodes are unaffected (see CLOSE_TABLE for that).
+func (m *TableManager) DestroyTable(id protocol.TableID) error {
+	m.mu.Lock()
+	t, ok := m.tables[id]
+	if ok {
+		delete(m.tables, id)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return errors.New("unknown table")
+	}
+	m.router.Unregister(id)
+	t.Stop()
+	return nil
+}
+
 func (m *TableManager) Get(id protocol.TableID) (*table.Table, bool) {
 	m.mu.RLock()
 	t, ok := m.tables[id]
@@ -0,0 +1,49 @@
+package cluster
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"p2poker/internal/protocol"
+	"p2poker/pkg/types"
+)
+
+func TestDestroyedTablesLeaveNoGoroutinesBehind(t *testing.T) {
+	r := NewRouter()
+	out := make(chan protocol.NetMessage, 1024)
+	m := NewTableManager("me", &protocol.Lamport{}, protocol.NewIDGen(nil), r, out)
+	base := runtime.NumGoroutine()
+
+	var ids []protocol.TableID
+	for i := 0; i < 10; i++ {
+		id := protocol.TableID(fmt.Sprintf("t%d", i))
+		tb, err := m.CreateLocalAuthorityTable(id, types.TableConfig{SmallBlind: 1, BigBlind: 2, MinBuyin: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tb.ProposeSync(protocol.Action{ID: "join-" + string(id), Type: protocol.ActJoin, PlayerID: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if n := runtime.NumGoroutine(); n < base+len(ids) {
+		t.Fatalf("%d goroutines with %d tables running, from %d", n, len(ids), base)
+	}
+
+	for _, id := range ids {
+		if err := m.DestroyTable(id); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m.Get(id); ok {
+			t.Fatalf("%s still managed", id)
+		}
+		if r.Route(protocol.NetMessage{Table: id}) {
+			t.Fatalf("%s still routed", id)
+		}
+	}
+	if err := m.DestroyTable(ids[0]); err == nil {
+		t.Fatal("destroyed a table twice")
+	}
+	eventually(t, "table goroutines outlived their tables", func() bool { return runtime.NumGoroutine() <= base })
+}
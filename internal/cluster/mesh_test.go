@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+)
+
+// startNode runs a node on a free loopback port.
+func startNode(t *testing.T, ctx context.Context) (*Node, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	n := NewNode(addr, netx.NewTCP(addr), nil)
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return n, addr
+}
+
+// link connects n to the node at addr and waits for the handshake.
+func link(t *testing.T, n *Node, addr string) {
+	t.Helper()
+	tcp := n.Network().(*netx.TCP)
+	if err := tcp.AddPeer(addr); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "peers never connected", func() bool {
+		for _, p := range tcp.Peers() {
+			if p.Connected {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// eventually polls cond for a couple of seconds, failing with msg if it
+// never holds.
+func eventually(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReplicationFeedSeesTablesTheNodeIsNotAt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, addr := startNode(t, ctx)
+	recorder, _ := startNode(t, ctx)
+	feed := recorder.ReplicationFeed()
+	link(t, recorder, addr)
+
+	id, err := host.CreateTable("feed", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, _ := host.Manager().Get(id)
+	if err := tb.ProposeSync(protocol.Action{ID: host.NewActionID(), Type: protocol.ActJoin, PlayerID: string(host.ID)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for joined := false; !joined; {
+		select {
+		case msg := <-feed:
+			if msg.Table != id || msg.Type != protocol.MsgCommit {
+				t.Fatalf("feed carried %s for %s", msg.Type, msg.Table)
+			}
+			joined = msg.Action.Type == protocol.ActJoin
+		case <-time.After(2 * time.Second):
+			t.Fatal("the JOIN never reached the feed")
+		}
+	}
+	if ids := recorder.Manager().ListIDs(); len(ids) != 0 {
+		t.Fatalf("the recorder attached to %v", ids)
+	}
+}
+
+// hostedTable creates a table on host that follower has discovered.
+func hostedTable(t *testing.T, host, follower *Node, hostAddr string) protocol.TableID {
+	t.Helper()
+	link(t, follower, hostAddr)
+	id, err := host.CreateTable("t", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := follower.DiscoverAndAttach(id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestClosingATableRemovesItOnEveryNode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, addr := startNode(t, ctx)
+	follower, _ := startNode(t, ctx)
+	id := hostedTable(t, host, follower, addr)
+	if _, ok := follower.Manager().Get(id); !ok {
+		t.Fatal("the follower did not attach")
+	}
+
+	tb, _ := host.Manager().Get(id)
+	if err := tb.ProposeSync(protocol.Action{ID: host.NewActionID(), Type: protocol.ActCloseTable, PlayerID: string(host.ID)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []*Node{host, follower} {
+		eventually(t, string(n.ID)+" still lists the closed table", func() bool {
+			_, ok := n.Manager().Get(id)
+			return !ok && len(n.Manager().ListIDs()) == 0
+		})
+	}
+}
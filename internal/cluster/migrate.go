@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+	"p2poker/internal/table"
+)
+
+// nodeExport is the serialized form of a whole node (see Export).
+type nodeExport struct {
+	Node    protocol.NodeID `json:"node"`
+	Lamport uint64          `json:"lamport"`
+	IDs     uint64          `json:"ids"` // action id counter (see IDGen.Resume)
+	Tables  []table.Export  `json:"tables"`
+}
+
+// Export serializes every local table — config, seq, epoch, full engine state
+// and log — so the node can be stopped and resumed elsewhere with Import. The
+// result contains every hole card at the node's tables; keep it private.
+func (n *Node) Export() ([]byte, error) {
+	ex := nodeExport{Node: n.ID, Lamport: n.clock.Now(), IDs: n.ids.Counter()}
+	for _, id := range n.mgr.ListIDs() {
+		if t, ok := n.mgr.Get(id); ok {
+			ex.Tables = append(ex.Tables, t.Export())
+		}
+	}
+	return json.MarshalIndent(ex, "", "  ")
+}
+
+// Import resumes an exported node on this one: it takes over the exported
+// NodeID and re-creates each table, resuming authority where the exported
+// node held it. Call it on a fresh node, before Start.
+func (n *Node) Import(data []byte) error {
+	var ex nodeExport
+	if err := json.Unmarshal(data, &ex); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+	if len(n.mgr.ListIDs()) > 0 {
+		return errors.New("import: node already has tables")
+	}
+	n.ID = ex.Node
+	n.mgr.self = ex.Node
+	if tcp, ok := n.net.(*netx.TCP); ok {
+		tcp.SetNodeID(ex.Node) // connections made from here on announce the imported identity
+	}
+	n.ids.Resume(ex.Node, ex.IDs)
+	n.clock.TickRemote(ex.Lamport)
+	for _, tex := range ex.Tables {
+		if _, err := n.mgr.ImportTable(tex); err != nil {
+			return fmt.Errorf("import table %s: %w", tex.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+	"p2poker/internal/table"
+)
+
+func TestExportedNodeResumesOnAFreshOne(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src, _ := startNode(t, ctx)
+	propose := func(n *Node, id protocol.TableID, typ protocol.ActionType, player string) {
+		t.Helper()
+		tb, _ := n.Manager().Get(id)
+		if err := tb.ProposeSync(protocol.Action{ID: n.NewActionID(), Type: typ, PlayerID: player}); err != nil {
+			t.Fatalf("%s %s: %v", typ, player, err)
+		}
+	}
+
+	// one table mid-hand, one with a lone player waiting
+	playing, err := src.CreateTable("playing", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiting, err := src.CreateTable("waiting", 5, 10, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	propose(src, playing, protocol.ActJoin, "a")
+	propose(src, playing, protocol.ActJoin, "b")
+	propose(src, playing, protocol.ActStartHand, string(src.ID))
+	propose(src, waiting, protocol.ActJoin, "c")
+	for _, id := range []protocol.TableID{playing, waiting} {
+		tb, _ := src.Manager().Get(id)
+		eventually(t, "CREATE_TABLE never applied", func() bool {
+			for _, a := range tb.Log().Actions {
+				if a.Type == protocol.ActCreateTable {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	data, err := src.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := NewNode("127.0.0.1:0", netx.NewInproc(), nil)
+	if err := dst.Import(data); err != nil {
+		t.Fatal(err)
+	}
+	if dst.ID != src.ID {
+		t.Fatalf("imported as %s, want %s", dst.ID, src.ID)
+	}
+	if err := dst.Import(data); err == nil {
+		t.Fatal("imported twice into the same node")
+	}
+
+	for _, id := range []protocol.TableID{playing, waiting} {
+		from, _ := src.Manager().Get(id)
+		to, ok := dst.Manager().Get(id)
+		if !ok {
+			t.Fatalf("table %s was not imported", id)
+		}
+		a, b := from.Snapshot(), to.Snapshot()
+		if a.Seq != b.Seq || a.Epoch != b.Epoch || b.Authority != src.ID || !to.IsAuthority() ||
+			!bytes.Equal(a.EngineJSON, b.EngineJSON) {
+			t.Fatalf("table %s: seq %d/%d epoch %d/%d authority %s; engine state equal %v",
+				id, a.Seq, b.Seq, a.Epoch, b.Epoch, b.Authority, bytes.Equal(a.EngineJSON, b.EngineJSON))
+		}
+		if seq, same := table.DiffLogs(from.Log().Actions, to.Log().Actions); !same || len(to.Log().Actions) != len(from.Log().Actions) {
+			t.Fatalf("table %s: logs differ from seq %d", id, seq)
+		}
+	}
+	// and play goes on where it left off
+	propose(dst, waiting, protocol.ActJoin, "d")
+}
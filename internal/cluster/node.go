@@ -3,6 +3,9 @@ package cluster
 import (
 	"context"
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -11,6 +14,17 @@ import (
 	"p2poker/pkg/types"
 )
 
+// Discovery limits: protect the dispatcher and the mesh from query floods.
+const (
+	maxPendingDiscoveries = 8
+	minDiscoveryInterval  = time.Second // per table
+)
+
+var (
+	ErrTooManyDiscoveries = errors.New("too many discoveries in progress")
+	ErrDiscoveryThrottled = errors.New("discovery for this table requested too recently")
+)
+
 type Node struct {
 	ID     protocol.NodeID
 	Addr   string
@@ -18,21 +32,52 @@ type Node struct {
 	router *Router
 	mgr    *TableManager
 	clock  *protocol.Lamport
+	ids    *protocol.IDGen
 
 	// discovery: waiters for snapshots of tables not yet attached locally
 	pendMu    sync.Mutex
-	pendingSS map[protocol.TableID]chan protocol.TableSnapshot
+	pendingSS map[protocol.TableID]chan protocol.NetMessage
+	lastQuery map[protocol.TableID]time.Time
+
+	// replication: optional copy of every inbound commit/snapshot (see ReplicationFeed)
+	feedMu sync.Mutex
+	feed   chan protocol.NetMessage
+
+	tourMu  sync.Mutex
+	tourney *Tournament
 }
 
-func NewNode(addr string, network netx.Network) *Node {
-	id := protocol.NewNodeID()
+// NewNode builds a node on top of network. src drives every id the node
+// generates (node, tables, actions — and therefore shuffle seeds); pass nil for
+// crypto-strength randomness, or a seeded source for reproducible clusters.
+// A seeded node's id also folds in addr, so nodes sharing a seed on different
+// addresses still get ids (and action ids) of their own.
+func NewNode(addr string, network netx.Network, src rand.Source) *Node {
+	ids := protocol.NewIDGen(src)
+	id := ids.NodeID()
+	if src != nil {
+		id = nodeIDAt(addr, id)
+	}
+	ids.SetOrigin(id)
 	r := NewRouter()
 	clk := &protocol.Lamport{}
-	mgr := NewTableManager(id, clk, r, network.Outbox())
-	return &Node{ID: id, Addr: addr, net: network, router: r, mgr: mgr, clock: clk, pendingSS: make(map[protocol.TableID]chan protocol.TableSnapshot)}
+	mgr := NewTableManager(id, clk, ids, r, network.Outbox())
+	return &Node{ID: id, Addr: addr, net: network, router: r, mgr: mgr, clock: clk, ids: ids,
+		pendingSS: make(map[protocol.TableID]chan protocol.NetMessage), lastQuery: make(map[protocol.TableID]time.Time)}
+}
+
+// nodeIDAt derives a node id from a seeded draw and the node's listen address.
+func nodeIDAt(addr string, drawn protocol.NodeID) protocol.NodeID {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(addr + "/" + string(drawn)))
+	return protocol.NodeID(fmt.Sprintf("n-%d", int64(h.Sum64()>>1)))
 }
 
 func (n *Node) Start(ctx context.Context) error {
+	if tcp, ok := n.net.(*netx.TCP); ok {
+		tcp.SetNodeID(n.ID)
+		tcp.OnPeer(n.peerChanged)
+	}
 	if err := n.net.Start(ctx); err != nil {
 		return err
 	}
@@ -46,10 +91,30 @@ func (n *Node) dispatcher(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case msg := <-n.net.Inbox():
+			if msg.To != "" && msg.To != n.ID {
+				continue // unicast to another node that reached us by broadcast
+			}
 			// Route to table if present; otherwise, see if someone is waiting on discovery
 			if !n.router.Route(msg) {
 				n.maybeDeliverDiscovery(msg)
 			}
+			n.maybeReplicate(msg)
+		}
+	}
+}
+
+// peerChanged sits a player out at every table this node runs when their
+// connection drops, and back in when they return.
+func (n *Node) peerChanged(node protocol.NodeID, up bool) {
+	for _, id := range n.mgr.ListIDs() {
+		t, ok := n.mgr.Get(id)
+		if !ok {
+			continue
+		}
+		if up {
+			t.PlayerReconnected(string(node))
+		} else {
+			t.PlayerDisconnected(string(node))
 		}
 	}
 }
@@ -63,33 +128,98 @@ func (n *Node) maybeDeliverDiscovery(msg protocol.NetMessage) {
 	n.pendMu.Unlock()
 	if ok {
 		select {
-		case ch <- *msg.State:
+		case ch <- msg:
 		default:
 		}
 	}
 }
 
+// ReplicationFeed returns a channel carrying every commit and snapshot this node
+// receives, for every table, whether or not the table is attached locally.
+// It is meant for external recorders (archives, analytics) that never sit down.
+// The feed is created on first call; a slow consumer loses messages rather than
+// stalling the dispatcher.
+func (n *Node) ReplicationFeed() <-chan protocol.NetMessage {
+	n.feedMu.Lock()
+	defer n.feedMu.Unlock()
+	if n.feed == nil {
+		n.feed = make(chan protocol.NetMessage, 1024)
+	}
+	return n.feed
+}
+
+func (n *Node) maybeReplicate(msg protocol.NetMessage) {
+	if msg.Type != protocol.MsgCommit && msg.Type != protocol.MsgSnapshot {
+		return
+	}
+	n.feedMu.Lock()
+	ch := n.feed
+	n.feedMu.Unlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- msg:
+	default:
+	}
+}
+
 // CreateTable creates and immediately broadcasts a CREATE_TABLE.
 func (n *Node) CreateTable(name string, sb, bb, minBuy int64) (protocol.TableID, error) {
-	id := protocol.NewTableID()
-	cfg := types.TableConfig{Name: name, SmallBlind: sb, BigBlind: bb, MinBuyin: minBuy}
+	return n.CreateTableConfig(types.TableConfig{Name: name, SmallBlind: sb, BigBlind: bb, MinBuyin: minBuy})
+}
+
+// CreateTableConfig is CreateTable with a full config (variant etc.).
+func (n *Node) CreateTableConfig(cfg types.TableConfig) (protocol.TableID, error) {
+	id := n.ids.TableID()
 	t, err := n.mgr.CreateLocalAuthorityTable(id, cfg)
 	if err != nil {
 		return "", err
 	}
-	t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActCreateTable, PlayerID: string(n.ID)})
+	t.ProposeLocal(protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActCreateTable, PlayerID: string(n.ID)})
 	return id, nil
 }
 
 // DiscoverAndAttach asks the network for a snapshot of tableID, then attaches as follower using that snapshot.
 func (n *Node) DiscoverAndAttach(tableID protocol.TableID) (protocol.TableID, error) {
+	return n.discover(tableID, protocol.ActJoin)
+}
+
+// Observe follows tableID as a spectator: the node replicates the table and
+// can render it, but takes no seat and is never dealt in. A table this node
+// already follows is observed in place; otherwise it is discovered first.
+func (n *Node) Observe(tableID protocol.TableID) error {
+	if t, ok := n.mgr.Get(tableID); ok {
+		return t.ProposeSync(protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActObserve, PlayerID: string(n.ID)})
+	}
+	_, err := n.discover(tableID, protocol.ActObserve)
+	return err
+}
+
+// discover fetches tableID's snapshot from its peers, attaches a follower and
+// proposes typ (JOIN or OBSERVE) for this node.
+func (n *Node) discover(tableID protocol.TableID, typ protocol.ActionType) (protocol.TableID, error) {
 	// create waiter
 	n.pendMu.Lock()
 	if _, exists := n.pendingSS[tableID]; exists {
 		n.pendMu.Unlock()
 		return "", errors.New("discovery already in progress")
 	}
-	ch := make(chan protocol.TableSnapshot, 1)
+	if len(n.pendingSS) >= maxPendingDiscoveries {
+		n.pendMu.Unlock()
+		return "", ErrTooManyDiscoveries
+	}
+	if last, ok := n.lastQuery[tableID]; ok && time.Since(last) < minDiscoveryInterval {
+		n.pendMu.Unlock()
+		return "", ErrDiscoveryThrottled
+	}
+	for id, at := range n.lastQuery {
+		if time.Since(at) >= minDiscoveryInterval {
+			delete(n.lastQuery, id) // keep the throttle map bounded
+		}
+	}
+	n.lastQuery[tableID] = time.Now()
+	ch := make(chan protocol.NetMessage, 1)
 	n.pendingSS[tableID] = ch
 	n.pendMu.Unlock()
 
@@ -98,18 +228,21 @@ func (n *Node) DiscoverAndAttach(tableID protocol.TableID) (protocol.TableID, er
 
 	// wait with timeout
 	select {
-	case ss := <-ch:
+	case msg := <-ch:
 		// clean up
 		n.pendMu.Lock()
 		delete(n.pendingSS, tableID)
 		n.pendMu.Unlock()
-		// attach follower using snapshot's cfg/epoch
+		// attach follower using snapshot's cfg/epoch, and start it from the
+		// snapshot rather than from nothing
+		ss := msg.State
 		if _, err := n.mgr.AttachFollowerTable(tableID, ss.Cfg, ss.Epoch); err != nil {
 			return "", err
 		}
-		// propose join
+		n.router.Route(msg)
+		// propose join (or observe)
 		if t, ok := n.mgr.Get(tableID); ok {
-			t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActJoin, PlayerID: string(n.ID)})
+			t.ProposeLocal(protocol.Action{ID: n.ids.ActionID(), Type: typ, PlayerID: string(n.ID)})
 		}
 		return tableID, nil
 	case <-time.After(3 * time.Second):
@@ -127,9 +260,31 @@ func (n *Node) JoinTableRemote(tableID protocol.TableID, epoch protocol.Epoch, c
 		return err
 	}
 	n.net.Outbox() <- protocol.NetMessage{Table: tableID, From: n.ID, Type: protocol.MsgStateQuery, Epoch: epoch, Lamport: n.clock.TickLocal()}
-	t.ProposeLocal(protocol.Action{ID: protocol.RandActionID(), Type: protocol.ActJoin, PlayerID: string(n.ID)})
+	t.ProposeLocal(protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActJoin, PlayerID: string(n.ID)})
 	return nil
 }
 
+// NewActionID returns a fresh action id drawn from the node's id source.
+func (n *Node) NewActionID() string { return n.ids.ActionID() }
+
 func (n *Node) Network() netx.Network  { return n.net }
 func (n *Node) Manager() *TableManager { return n.mgr }
+
+// SetInboxPolicy applies p to the transport's inbox (TCP only) and to every
+// table's inbox, so a stalled reader or table sheds load instead of freezing
+// the node. Set it before Start.
+func (n *Node) SetInboxPolicy(p netx.InboxPolicy) {
+	if tcp, ok := n.net.(*netx.TCP); ok {
+		tcp.SetInboxPolicy(p)
+	}
+	n.router.SetPolicy(p)
+}
+
+// InboxDropped counts the messages the inbox policy has discarded: arriving
+// at the transport, and on their way to a table.
+func (n *Node) InboxDropped() (transport, tables uint64) {
+	if tcp, ok := n.net.(*netx.TCP); ok {
+		transport = tcp.InboxDropped()
+	}
+	return transport, n.router.Dropped()
+}
@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+	"p2poker/internal/table"
+)
+
+func TestSeededNodesOnDifferentAddressesGetDifferentIDs(t *testing.T) {
+	a := NewNode(":7777", netx.NewInproc(), rand.NewSource(5))
+	b := NewNode(":7778", netx.NewInproc(), rand.NewSource(5))
+	if a.ID == b.ID {
+		t.Fatalf("both nodes are %s", a.ID)
+	}
+	if x, y := a.ids.ActionID(), b.ids.ActionID(); x == y {
+		t.Fatalf("action ids collide: %s", x)
+	}
+	again := NewNode(":7777", netx.NewInproc(), rand.NewSource(5))
+	if again.ID != a.ID {
+		t.Fatalf("same seed and address gave %s, then %s", a.ID, again.ID)
+	}
+	if x, y := a.ids.TableID(), again.ids.TableID(); x != y {
+		t.Fatalf("table ids not reproducible: %s vs %s", x, y)
+	}
+}
+
+func TestNodeIDsFollowTheNodesSeed(t *testing.T) {
+	draw := func(seed int64) []string {
+		n := NewNode(":7777", netx.NewInproc(), rand.NewSource(seed))
+		ids := []string{string(n.ID), string(n.ids.TableID())}
+		for range 4 {
+			ids = append(ids, n.NewActionID())
+		}
+		return ids
+	}
+	a, again, other := draw(1), draw(1), draw(2)
+	if !slices.Equal(a, again) {
+		t.Fatalf("same seed, different ids: %v vs %v", a, again)
+	}
+	for i := range a {
+		if a[i] == other[i] {
+			t.Fatalf("seeds 1 and 2 agree on id %d: %s", i, a[i])
+		}
+	}
+	if x, y := NewNode(":7777", netx.NewInproc(), nil), NewNode(":7777", netx.NewInproc(), nil); x.ID == y.ID {
+		t.Fatalf("unseeded nodes share id %s", x.ID)
+	}
+}
+
+func TestDiagnosticsDumpsTablesAndPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n, _ := startNode(t, ctx)
+	_, peer := startNode(t, ctx)
+	link(t, n, peer)
+	id, err := n.CreateTable("diag", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := n.Diagnostics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump map[string]json.RawMessage
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"node", "addr", "tables", "peers", "outbox", "dropped"} {
+		if _, ok := dump[key]; !ok {
+			t.Errorf("dump has no %q:\n%s", key, data)
+		}
+	}
+	var tables []table.Diag
+	var peers []string
+	if err := json.Unmarshal(dump["tables"], &tables); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(dump["peers"], &peers); err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].ID != id || !tables[0].IsAuthority || tables[0].Authority != n.ID {
+		t.Errorf("tables %+v, want %s run by this node", tables, id)
+	}
+	if !slices.Equal(peers, []string{peer}) {
+		t.Errorf("peers %v, want %s", peers, peer)
+	}
+}
+
+func TestDiscoveriesAreCappedAndThrottled(t *testing.T) {
+	n := NewNode(":7777", netx.NewInproc(), nil) // no peers: every discovery waits
+	pending := func() int {
+		n.pendMu.Lock()
+		defer n.pendMu.Unlock()
+		return len(n.pendingSS)
+	}
+	for i := 0; i < maxPendingDiscoveries; i++ {
+		go n.DiscoverAndAttach(protocol.TableID(fmt.Sprintf("t%d", i)))
+	}
+	eventually(t, "discoveries never started", func() bool { return pending() == maxPendingDiscoveries })
+
+	for i := 0; i < 3; i++ {
+		if _, err := n.DiscoverAndAttach(protocol.TableID(fmt.Sprintf("extra%d", i))); !errors.Is(err, ErrTooManyDiscoveries) {
+			t.Fatalf("discovery %d over the cap: %v", maxPendingDiscoveries+i+1, err)
+		}
+	}
+	if got := pending(); got != maxPendingDiscoveries {
+		t.Fatalf("%d discoveries pending, want the cap of %d", got, maxPendingDiscoveries)
+	}
+
+	m := NewNode(":7778", netx.NewInproc(), nil)
+	m.lastQuery["t"] = time.Now() // as if a discovery of t had just been asked for
+	if _, err := m.DiscoverAndAttach("t"); !errors.Is(err, ErrDiscoveryThrottled) {
+		t.Fatalf("a second query for t within %v: %v", minDiscoveryInterval, err)
+	}
+}
+
+func TestADroppedConnectionSitsThePlayerOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, addr := startNode(t, ctx)
+	id, err := host.CreateTable("t", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, _ := host.Manager().Get(id)
+
+	// the player's own node, known to the host only by its handshake
+	dial := func() *netx.TCP {
+		c := netx.NewTCP("127.0.0.1:0")
+		c.SetNodeID("player")
+		if err := c.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.AddPeer(addr); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	// sitting reports the player's last SIT_OUT or SIT_IN, if any
+	sitting := func() protocol.ActionType {
+		var last protocol.ActionType
+		for _, a := range tb.Log().Actions {
+			if a.PlayerID == "player" && (a.Type == protocol.ActSitOut || a.Type == protocol.ActSitIn) {
+				last = a.Type
+			}
+		}
+		return last
+	}
+
+	c := dial()
+	if err := tb.ProposeSync(protocol.Action{ID: host.NewActionID(), Type: protocol.ActJoin, PlayerID: "player"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the host never saw the player connect", func() bool { return len(host.Network().(*netx.TCP).Peers()) == 1 })
+	if got := sitting(); got != "" {
+		t.Fatalf("a connected player got %s", got)
+	}
+
+	_ = c.Close()
+	eventually(t, "the dropped player was not sat out", func() bool { return sitting() == protocol.ActSitOut })
+
+	c = dial()
+	defer c.Close()
+	eventually(t, "the returning player was not sat back in", func() bool { return sitting() == protocol.ActSitIn })
+}
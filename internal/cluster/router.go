@@ -2,21 +2,38 @@ package cluster
 
 import (
 	"sync"
+	"sync/atomic"
 
+	"p2poker/internal/netx"
 	"p2poker/internal/protocol"
 )
 
 // Router delivers NetMessages to the appropriate table goroutine by TableID.
+// A table whose inbox is full is handled by the router's policy (see
+// SetPolicy), so under a drop policy one slow table cannot stall the others.
 type Router struct {
 	mu      sync.RWMutex
-	byTable map[protocol.TableID]chan<- protocol.NetMessage
+	byTable map[protocol.TableID]chan protocol.NetMessage
+	policy  netx.InboxPolicy
+	dropped atomic.Uint64
 }
 
 func NewRouter() *Router {
-	return &Router{byTable: make(map[protocol.TableID]chan<- protocol.NetMessage)}
+	return &Router{byTable: make(map[protocol.TableID]chan protocol.NetMessage)}
 }
 
-func (r *Router) Register(id protocol.TableID, inbox chan<- protocol.NetMessage) {
+// SetPolicy sets what Route does when a table's inbox is full; the default
+// blocks until the table catches up.
+func (r *Router) SetPolicy(p netx.InboxPolicy) {
+	r.mu.Lock()
+	r.policy = p
+	r.mu.Unlock()
+}
+
+// Dropped counts the messages the policy has discarded, across all tables.
+func (r *Router) Dropped() uint64 { return r.dropped.Load() }
+
+func (r *Router) Register(id protocol.TableID, inbox chan protocol.NetMessage) {
 	r.mu.Lock()
 	r.byTable[id] = inbox
 	r.mu.Unlock()
@@ -31,9 +48,10 @@ func (r *Router) Unregister(id protocol.TableID) {
 func (r *Router) Route(msg protocol.NetMessage) bool {
 	r.mu.RLock()
 	ch, ok := r.byTable[msg.Table]
+	policy := r.policy
 	r.mu.RUnlock()
 	if ok {
-		ch <- msg
+		netx.Deliver(ch, msg, policy, &r.dropped)
 	}
 	return ok
 }
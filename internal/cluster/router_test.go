@@ -0,0 +1,28 @@
+package cluster
+
+import (
+	"testing"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+)
+
+func TestASlowTableDoesNotStallTheRouter(t *testing.T) {
+	r := NewRouter()
+	r.SetPolicy(netx.InboxDropNewest)
+	slow := make(chan protocol.NetMessage, 2)
+	fast := make(chan protocol.NetMessage, 64)
+	r.Register("slow", slow)
+	r.Register("fast", fast)
+
+	for i := 0; i < 10; i++ {
+		r.Route(protocol.NetMessage{Table: "slow", Type: protocol.MsgCommit})
+		r.Route(protocol.NetMessage{Table: "fast", Type: protocol.MsgCommit})
+	}
+	if len(fast) != 10 || len(slow) != 2 || r.Dropped() != 8 {
+		t.Fatalf("fast %d, slow %d, dropped %d; want 10, 2, 8", len(fast), len(slow), r.Dropped())
+	}
+	if r.Route(protocol.NetMessage{Table: "gone"}) {
+		t.Fatal("routed to an unknown table")
+	}
+}
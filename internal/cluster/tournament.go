@@ -0,0 +1,197 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"p2poker/internal/protocol"
+	"p2poker/internal/table"
+)
+
+// MovePlayer reseats player from one table to another, carrying their stack.
+// This node must be the authority on both tables (tournament coordinator).
+// It commits a LEAVE on the source, then a JOIN with the player's stack as the
+// buy-in on the destination. The player must not be live in a hand at the
+// source. If the destination does not seat them, they are seated back at the
+// source with their stack and the error is returned.
+func (n *Node) MovePlayer(from, to protocol.TableID, player string) error {
+	src, ok := n.mgr.Get(from)
+	if !ok {
+		return fmt.Errorf("unknown table %s", from)
+	}
+	dst, ok := n.mgr.Get(to)
+	if !ok {
+		return fmt.Errorf("unknown table %s", to)
+	}
+	if !src.IsAuthority() || !dst.IsAuthority() {
+		return errors.New("not the authority on both tables")
+	}
+	seat, live, ok := src.SeatOf(player)
+	if !ok {
+		return fmt.Errorf("%s is not seated at %s", player, from)
+	}
+	if live {
+		return fmt.Errorf("%s is live in a hand at %s", player, from)
+	}
+	if err := dst.CheckJoin(protocol.NodeID(player)); err != nil {
+		return fmt.Errorf("%s cannot join %s: %w", player, to, err)
+	}
+
+	// the LEAVE carries the stack: it is refused if a hand has since dealt
+	// the player in or their stack has changed (see table.moveErr)
+	leave := protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActLeave, PlayerID: player, Amount: seat.Stack,
+		Meta: map[string]any{"moved_to": string(to)}}
+	if err := src.ProposeSync(leave); err != nil {
+		return fmt.Errorf("leaving %s: %w", from, err)
+	}
+	err := n.seatMoved(dst, from, player, seat.Stack)
+	if err == nil {
+		return nil
+	}
+	// a full table puts the player on its waiting list, to be seated later at
+	// the minimum buy-in: take them off it
+	_ = dst.ProposeSync(protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActLeave, PlayerID: player})
+	if back := n.seatMoved(src, to, player, seat.Stack); back != nil {
+		return fmt.Errorf("joining %s: %w; and reseating at %s: %v", to, err, from, back)
+	}
+	return fmt.Errorf("joining %s: %w", to, err)
+}
+
+// seatMoved commits the JOIN of a player moved from another table with their
+// stack, and checks they were seated rather than put on the waiting list.
+func (n *Node) seatMoved(t *table.Table, from protocol.TableID, player string, stack int64) error {
+	join := protocol.Action{ID: n.ids.ActionID(), Type: protocol.ActJoin, PlayerID: player, Amount: stack,
+		Meta: map[string]any{"moved_from": string(from)}}
+	if err := t.ProposeSync(join); err != nil {
+		return err
+	}
+	if _, _, seated := t.SeatOf(player); !seated {
+		return fmt.Errorf("%s was not seated (table full?)", player)
+	}
+	return nil
+}
+
+// Standing is one player's tournament result. Place is 0 while still alive.
+type Standing struct {
+	Player string
+	Place  int
+	Payout int64
+}
+
+// Tournament records bust-out order across tables to produce finishing places,
+// and awards a payout structure (Payouts[0] to 1st, ...) once one player remains.
+type Tournament struct {
+	mu       sync.Mutex
+	payouts  []int64
+	entrants []string
+	busted   []string // in bust order: first busted finishes last
+	done     bool
+}
+
+func NewTournament(payouts []int64) *Tournament {
+	return &Tournament{payouts: append([]int64{}, payouts...)}
+}
+
+// Register adds an entrant (idempotent).
+func (tr *Tournament) Register(player string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if !slices.Contains(tr.entrants, player) {
+		tr.entrants = append(tr.entrants, player)
+	}
+}
+
+// Watch registers everyone seated at t and records their busts as t applies
+// each hand: from the stacks the hand left at zero, not from the lossy event
+// stream. Anyone already out of chips is recorded at once.
+func (tr *Tournament) Watch(t *table.Table) {
+	seated, broke := t.OnBust(func(players []string) {
+		for _, p := range players {
+			tr.RecordBust(p)
+		}
+	})
+	for _, pid := range seated {
+		tr.Register(pid)
+	}
+	for _, pid := range broke {
+		tr.RecordBust(pid)
+	}
+}
+
+// RecordBust notes that player is out. Busts must be recorded in the order
+// they happened; duplicates are ignored.
+func (tr *Tournament) RecordBust(player string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.done {
+		return
+	}
+	if slices.Contains(tr.busted, player) {
+		return
+	}
+	tr.busted = append(tr.busted, player)
+	if len(tr.entrants)-len(tr.busted) <= 1 {
+		tr.done = true
+	}
+}
+
+// Finished reports whether the field has collapsed to one player.
+func (tr *Tournament) Finished() bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.done
+}
+
+// Standings lists players still alive (Place 0) followed by finishers from
+// best to worst. Once finished, the survivor is 1st and payouts are assigned.
+func (tr *Tournament) Standings() []Standing {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	out := make([]Standing, 0, len(tr.entrants))
+	for _, p := range tr.entrants {
+		if !slices.Contains(tr.busted, p) {
+			place := 0
+			if tr.done {
+				place = 1
+			}
+			out = append(out, Standing{Player: p, Place: place})
+		}
+	}
+	for i := len(tr.busted) - 1; i >= 0; i-- {
+		out = append(out, Standing{Player: tr.busted[i], Place: len(tr.entrants) - i})
+	}
+	if tr.done {
+		for i := range out {
+			if pl := out[i].Place; pl >= 1 && pl <= len(tr.payouts) {
+				out[i].Payout = tr.payouts[pl-1]
+			}
+		}
+	}
+	return out
+}
+
+// StartTournament begins tracking standings across the given local tables,
+// replacing any previous tournament on this node.
+func (n *Node) StartTournament(payouts []int64, tables ...protocol.TableID) (*Tournament, error) {
+	tr := NewTournament(payouts)
+	for _, id := range tables {
+		t, ok := n.mgr.Get(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown table %s", id)
+		}
+		tr.Watch(t)
+	}
+	n.tourMu.Lock()
+	n.tourney = tr
+	n.tourMu.Unlock()
+	return tr, nil
+}
+
+// Tournament returns the tournament started on this node, or nil.
+func (n *Node) Tournament() *Tournament {
+	n.tourMu.Lock()
+	defer n.tourMu.Unlock()
+	return n.tourney
+}
@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+	"p2poker/internal/table"
+	"p2poker/pkg/types"
+)
+
+func TestStandingsFollowBustOrderAndPayOut(t *testing.T) {
+	tr := NewTournament([]int64{50, 30, 20})
+	for _, p := range []string{"a", "b", "c", "d"} {
+		tr.Register(p)
+	}
+	tr.Register("a") // idempotent
+
+	tr.RecordBust("d")
+	tr.RecordBust("d") // duplicate
+	tr.RecordBust("c")
+	if tr.Finished() {
+		t.Fatal("finished with two players left")
+	}
+	want := []Standing{{Player: "a"}, {Player: "b"}, {Player: "c", Place: 3}, {Player: "d", Place: 4}}
+	if got := tr.Standings(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mid-tournament standings %+v, want %+v", got, want)
+	}
+
+	tr.RecordBust("b")
+	if !tr.Finished() {
+		t.Fatal("not finished with one player left")
+	}
+	tr.RecordBust("a") // after the end: ignored
+	want = []Standing{
+		{Player: "a", Place: 1, Payout: 50},
+		{Player: "b", Place: 2, Payout: 30},
+		{Player: "c", Place: 3, Payout: 20},
+		{Player: "d", Place: 4},
+	}
+	if got := tr.Standings(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("final standings %+v, want %+v", got, want)
+	}
+}
+
+// shoveTable runs a table whose every player moves all in, until one has
+// every chip; it returns the players in the order they busted.
+func shoveTable(t *testing.T, tb *table.Table, ids *protocol.IDGen) []string {
+	t.Helper()
+	eng := tb.Eng() // read only between synchronous proposals
+	propose := func(typ protocol.ActionType, player string, amount int64) {
+		t.Helper()
+		if err := tb.ProposeSync(protocol.Action{ID: ids.ActionID(), Type: typ, PlayerID: player, Amount: amount}); err != nil {
+			t.Fatalf("%s %s: %v", typ, player, err)
+		}
+	}
+	var busted []string
+	for hand := 0; hand < 500; hand++ {
+		before := map[string]int64{}
+		var alive int
+		for _, pid := range eng.Order {
+			if s := eng.Seats[pid].Stack; s > 0 {
+				before[pid] = s
+				alive++
+			}
+		}
+		if alive < 2 {
+			return busted
+		}
+		propose(protocol.ActStartHand, "op", 0)
+		for eng.HandActive {
+			p := eng.CurrentPlayer()
+			st := eng.Seats[p]
+			propose(protocol.ActRaise, p, st.Committed+st.Stack) // a call when it cannot raise
+		}
+		var out []string
+		for _, pid := range eng.Order {
+			if before[pid] > 0 && eng.Seats[pid].Stack == 0 {
+				out = append(out, pid)
+			}
+		}
+		sort.SliceStable(out, func(i, j int) bool { return before[out[i]] < before[out[j]] })
+		busted = append(busted, out...)
+	}
+	t.Fatal("no winner after 500 hands")
+	return nil
+}
+
+func TestWatchRecordsBustsFromTheTablesStacks(t *testing.T) {
+	ids := protocol.NewIDGen(rand.NewSource(3))
+	in := make(chan protocol.NetMessage)
+	out := make(chan protocol.NetMessage, 64)
+	go func() {
+		for range out {
+		}
+	}()
+	cfg := types.TableConfig{Name: "mtt", SmallBlind: 1, BigBlind: 2, MinBuyin: 10, MaxBuyin: 100}
+	tb := table.New("t1", "op", cfg, true, 0, &protocol.Lamport{}, ids, in, out)
+	go tb.Run()
+	defer tb.Stop()
+
+	players := []string{"a", "b", "c", "d", "e"}
+	for i, p := range players {
+		err := tb.ProposeSync(protocol.Action{ID: ids.ActionID(), Type: protocol.ActJoin, PlayerID: p, Amount: int64(10 + 10*i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr := NewTournament([]int64{60, 40})
+	tr.Watch(tb)
+	_ = tb.Subscribe(table.EvPlayerBusted) // never read: the tournament must not depend on it
+
+	busted := shoveTable(t, tb, ids)
+	if len(busted) != len(players)-1 || !tr.Finished() {
+		t.Fatalf("busted %v; tournament finished=%v", busted, tr.Finished())
+	}
+	got := tr.Standings()
+	if got[0].Place != 1 || got[0].Payout != 60 {
+		t.Fatalf("winner %+v", got[0])
+	}
+	for i, s := range got[1:] {
+		want := busted[len(busted)-1-i]
+		if s.Player != want || s.Place != i+2 {
+			t.Fatalf("place %d: %+v, want %s", i+2, s, want)
+		}
+	}
+	if got[1].Payout != 40 {
+		t.Fatalf("runner-up paid %d", got[1].Payout)
+	}
+}
+
+func TestMovePlayerCarriesTheirStack(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n, _ := startNode(t, ctx)
+	from, err := n.CreateTable("a", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := n.CreateTable("b", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _ := n.Manager().Get(from)
+	dst, _ := n.Manager().Get(to)
+	propose := func(typ protocol.ActionType, player string) {
+		t.Helper()
+		if err := src.ProposeSync(protocol.Action{ID: n.NewActionID(), Type: typ, PlayerID: player}); err != nil {
+			t.Fatalf("%s %s: %v", typ, player, err)
+		}
+	}
+	propose(protocol.ActJoin, "p")
+	propose(protocol.ActJoin, "q")
+	propose(protocol.ActStartHand, string(n.ID))
+	folder := src.Eng().CurrentPlayer() // read only between synchronous proposals
+	propose(protocol.ActFold, folder)
+	seat, _, _ := src.SeatOf(folder)
+	stack := seat.Stack
+	if stack == 100 {
+		t.Fatalf("%s still has the buy-in after folding", folder)
+	}
+
+	if err := n.MovePlayer(from, to, folder); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, folder+" never arrived at "+string(to), func() bool {
+		rs := dst.Results()
+		return len(rs) == 1 && rs[0].Player == folder && rs[0].Stack == stack
+	})
+	eventually(t, folder+" is still seated at "+string(from), func() bool {
+		rs := src.Results()
+		return len(rs) == 1 && rs[0].Player != folder
+	})
+	if err := n.MovePlayer(from, to, folder); err == nil {
+		t.Fatal("moved a player who had already gone")
+	}
+}
+
+func TestMovePlayerToAFullTableKeepsTheirSeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n, _ := startNode(t, ctx)
+	from, err := n.CreateTable("a", 1, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := n.CreateTableConfig(types.TableConfig{Name: "b", SmallBlind: 1, BigBlind: 2, MinBuyin: 100, MaxSeats: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _ := n.Manager().Get(from)
+	dst, _ := n.Manager().Get(to)
+	join := func(tb *table.Table, player string, amount int64) {
+		t.Helper()
+		if err := tb.ProposeSync(protocol.Action{ID: n.NewActionID(), Type: protocol.ActJoin, PlayerID: player, Amount: amount}); err != nil {
+			t.Fatalf("%s joining %s: %v", player, tb.ID(), err)
+		}
+	}
+	join(src, "p", 150)
+	join(dst, "x", 0)
+	join(dst, "y", 0)
+
+	stayed := func(what string) {
+		t.Helper()
+		if seat, _, ok := src.SeatOf("p"); !ok || seat.Stack != 150 {
+			t.Fatalf("%s: p at %s as %+v (seated %v), want a stack of 150", what, from, seat, ok)
+		}
+		if _, _, ok := dst.SeatOf("p"); ok {
+			t.Fatalf("%s: p seated at the full %s", what, to)
+		}
+		if err := dst.CheckJoin("p"); err == nil || strings.Contains(err.Error(), "waiting list") {
+			t.Fatalf("%s: p at %s: %v, want the table full and p not waiting", what, to, err)
+		}
+	}
+	if err := n.MovePlayer(from, to, "p"); err == nil || !errors.Is(err, engine.ErrTableFull) {
+		t.Fatalf("move to a full table: %v, want ErrTableFull", err)
+	}
+	stayed("refused up front")
+
+	// the JOIN itself refused, after the LEAVE is committed: p goes back
+	if err := dst.ProposeSync(protocol.Action{ID: n.NewActionID(), Type: protocol.ActLeave, PlayerID: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	dst.Intercept(func(_ *engine.State, a protocol.Action) (protocol.Action, error) {
+		if a.Type == protocol.ActJoin {
+			return a, errors.New("no moves today")
+		}
+		return a, nil
+	})
+	if err := n.MovePlayer(from, to, "p"); err == nil || !strings.Contains(err.Error(), "no moves today") {
+		t.Fatalf("move refused by the destination: %v", err)
+	}
+	if seat, _, ok := src.SeatOf("p"); !ok || seat.Stack != 150 {
+		t.Fatalf("after the refused JOIN: p at %s as %+v (seated %v), want back with 150", from, seat, ok)
+	}
+	if _, _, ok := dst.SeatOf("p"); ok {
+		t.Fatalf("p seated at %s despite the refusal", to)
+	}
+}
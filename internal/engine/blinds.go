@@ -0,0 +1,100 @@
+package engine
+
+// Hold'em blinds follow the dead-button rule, tracked across hands by seat
+// number in State.Last:
+//
+//   - the big blind moves to the next player dealt in after last hand's big
+//     blind, so nobody skips it when seats empty out
+//   - the small blind is the seat last hand's big blind sat in; if that
+//     player has gone (or is not dealt in) the small blind is dead and
+//     nobody posts it
+//   - the button is the seat last hand's small blind sat in; if that seat is
+//     empty the button is dead, DealerIdx points at the nearest player
+//     before it so action still starts at the seat after the button, and no
+//     one may button-straddle
+//
+// Nobody posts twice in a row this way, and nobody skips a blind. The first
+// hand, and every heads-up hand, move the button one player along instead
+// (heads-up the button posts the small blind).
+
+// Positions records where a hold'em hand's button and blinds were, by seat
+// number. The seats may have been empty (a dead button or small blind).
+type Positions struct {
+	Button int
+	SB     int
+	BB     int
+}
+
+// placeBlinds moves the button for a new hold'em hand (resetSeats must have
+// run) and returns the Order indexes of the blinds; sb is -1 when the small
+// blind is dead. It records the hand's positions in Last.
+func (s *State) placeBlinds() (sb, bb int) {
+	s.DeadButton = false
+	if s.Last == nil || s.dealtIn() == 2 {
+		s.advanceButton()
+		sb = s.nextDealtIn(s.DealerIdx)
+		if s.dealtIn() == 2 {
+			// heads-up: the button is the small blind, so it acts first
+			// preflop (after the big blind) and last on every later street
+			sb = s.DealerIdx
+		}
+		bb = s.nextDealtIn(sb)
+		s.Last = &Positions{Button: s.seatOf(s.DealerIdx), SB: s.seatOf(sb), BB: s.seatOf(bb)}
+		return sb, bb
+	}
+
+	last := *s.Last
+	bb = s.dealtInAfter(last.BB)
+	sb = s.dealtInAt(last.BB)
+	if button := s.dealtInAt(last.SB); button >= 0 {
+		s.DealerIdx = button
+	} else {
+		s.DeadButton = true
+		s.DealerIdx = s.dealtInBefore(last.SB)
+	}
+	s.Last = &Positions{Button: last.SB, SB: last.BB, BB: s.seatOf(bb)}
+	return sb, bb
+}
+
+// seatOf is the seat number of Order[i].
+func (s *State) seatOf(i int) int {
+	return s.Seats[s.Order[i]].Index
+}
+
+// dealtInAt is the Order index of the player dealt in at seat number seat,
+// or -1 if there is none.
+func (s *State) dealtInAt(seat int) int {
+	for i, pid := range s.Order {
+		if st := s.Seats[pid]; st.Index == seat && st.InHand {
+			return i
+		}
+	}
+	return -1
+}
+
+// dealtInAfter is the Order index of the first player dealt in at a seat
+// number above seat, wrapping round the table.
+func (s *State) dealtInAfter(seat int) int {
+	for i, pid := range s.Order {
+		if st := s.Seats[pid]; st.Index > seat && st.InHand {
+			return i
+		}
+	}
+	return s.nextDealtIn(len(s.Order) - 1)
+}
+
+// dealtInBefore is the Order index of the last player dealt in at a seat
+// number below seat, wrapping round the table.
+func (s *State) dealtInBefore(seat int) int {
+	for i := len(s.Order) - 1; i >= 0; i-- {
+		if st := s.Seats[s.Order[i]]; st.Index < seat && st.InHand {
+			return i
+		}
+	}
+	for i := len(s.Order) - 1; i >= 0; i-- {
+		if s.Seats[s.Order[i]].InHand {
+			return i
+		}
+	}
+	return 0
+}
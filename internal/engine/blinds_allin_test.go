@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"testing"
+)
+
+// shortBlinds deals a 5/10 hand to a, b and c with 100 each, except that the
+// small and big blinds start with sb and bb (0 = 100). It returns the button,
+// small blind and big blind.
+func shortBlinds(t *testing.T, sb, bb int64) (s *State, button, small, big PlayerID) {
+	t.Helper()
+	// a dry run of the same deal finds who is in which seat
+	probe := dealt(t, blinds(5, 10), 100, 100, 100)
+	button, small, big = probe.Order[probe.DealerIdx], posted(t, probe, 5), posted(t, probe, 10)
+
+	s = seated(t, blinds(5, 10), 100, 100, 100)
+	if sb > 0 {
+		s.Seats[small].Stack = sb
+	}
+	if bb > 0 {
+		s.Seats[big].Stack = bb
+	}
+	deal(t, s, 1)
+	return s, button, small, big
+}
+
+// acts plays a call (or a check when nothing is owed) for each player in
+// order, failing unless each of them is the one to act.
+func acts(t *testing.T, s *State, players ...PlayerID) {
+	t.Helper()
+	for _, p := range players {
+		if cur := s.CurrentPlayer(); cur != p || s.RoundClosed() {
+			t.Fatalf("%s to act (round closed: %v), want %s", cur, s.RoundClosed(), p)
+		}
+		var err error
+		if s.Seats[p].Committed < s.CurrentBet {
+			err = s.Call(p)
+		} else {
+			err = s.Check(p)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !s.RoundClosed() {
+		t.Fatalf("round still open; %s to act", s.CurrentPlayer())
+	}
+}
+
+func TestSmallBlindAllInOnThePostIsSkipped(t *testing.T) {
+	s, button, small, big := shortBlinds(t, 3, 0)
+	if !s.Seats[small].AllIn || s.Pot != 13 || s.CurrentBet != 10 {
+		t.Fatalf("small blind all in %v, pot %d, bar %d; want all in, 13, 10", s.Seats[small].AllIn, s.Pot, s.CurrentBet)
+	}
+	// the button calls, the all-in small blind is passed over and the big
+	// blind still has the option
+	acts(t, s, button, big)
+	s.AdvancePhase()
+	if cur := s.CurrentPlayer(); cur != big {
+		t.Fatalf("%s first to act on the flop, want the big blind %s", cur, big)
+	}
+}
+
+func TestShortBigBlindStillSetsTheFullBar(t *testing.T) {
+	s, button, small, big := shortBlinds(t, 0, 4)
+	if !s.Seats[big].AllIn || s.Pot != 9 || s.CurrentBet != 10 {
+		t.Fatalf("big blind all in %v, pot %d, bar %d; want all in, 9, 10", s.Seats[big].AllIn, s.Pot, s.CurrentBet)
+	}
+	acts(t, s, button, small) // the small blind completes; the big blind has no option
+	s.AdvancePhase()
+	if cur := s.CurrentPlayer(); cur != small {
+		t.Fatalf("%s first to act on the flop, want the small blind %s", cur, small)
+	}
+}
+
+func TestBothBlindsAllInLeaveTheButtonAlone(t *testing.T) {
+	s, button, _, _ := shortBlinds(t, 3, 4)
+	acts(t, s, button)
+	if s.Seats[button].Stack != 90 {
+		t.Fatalf("button has %d after calling, want 90", s.Seats[button].Stack)
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"testing"
+)
+
+// deadButtonSpot plays a five-handed first hand out by folds and returns the
+// table with who held the button and blinds in it.
+func deadButtonSpot(t *testing.T) (s *State, button, sb, bb PlayerID) {
+	t.Helper()
+	s = dealt(t, nil, 100, 100, 100, 100, 100)
+	at := func(seat int) PlayerID {
+		for _, p := range s.Order {
+			if s.Seats[p].Index == seat {
+				return p
+			}
+		}
+		return ""
+	}
+	button, sb, bb = at(s.Last.Button), at(s.Last.SB), at(s.Last.BB)
+	foldOut(t, s)
+	return s, button, sb, bb
+}
+
+// blindsPosted deals the next hand and returns who put in what.
+func blindsPosted(t *testing.T, s *State) map[PlayerID]int64 {
+	t.Helper()
+	deal(t, s, 2)
+	in := map[PlayerID]int64{}
+	for _, p := range s.Order {
+		if c := s.Seats[p].Committed; c > 0 {
+			in[p] = c
+		}
+	}
+	return in
+}
+
+func TestSmallBlindLeavingKillsTheButton(t *testing.T) {
+	s, button, sb, bb := deadButtonSpot(t)
+	next := after(s, bb, 1)
+	s.Leave(sb)
+
+	in := blindsPosted(t, s)
+	// last hand's big blind posts the small blind, so nobody skips theirs;
+	// the button would have gone to sb's empty seat, so it is dead
+	if len(in) != 2 || in[bb] != 1 || in[next] != 2 {
+		t.Fatalf("posted %v; want %s the small blind and %s the big", in, bb, next)
+	}
+	if !s.DeadButton || s.Order[s.DealerIdx] != button {
+		t.Fatalf("dead button %v on %s; want a dead button, with %s the last seat before it", s.DeadButton, s.Order[s.DealerIdx], button)
+	}
+}
+
+func TestBigBlindLeavingKillsTheSmallBlind(t *testing.T) {
+	s, _, sb, bb := deadButtonSpot(t)
+	next := after(s, bb, 1)
+	s.Leave(bb)
+
+	in := blindsPosted(t, s)
+	// the seat the small blind falls to is empty: only the big blind posts,
+	// and it moves on to the next player rather than skipping anyone
+	if len(in) != 1 || in[next] != 2 {
+		t.Fatalf("posted %v; want a dead small blind and %s the big", in, next)
+	}
+	if s.DeadButton || s.Order[s.DealerIdx] != sb {
+		t.Fatalf("button on %s (dead %v), want it live on %s", s.Order[s.DealerIdx], s.DeadButton, sb)
+	}
+}
+
+func TestButtonLeavingMovesTheBlindsOnAsUsual(t *testing.T) {
+	s, button, sb, bb := deadButtonSpot(t)
+	next := after(s, bb, 1)
+	s.Leave(button)
+
+	in := blindsPosted(t, s)
+	if len(in) != 2 || in[bb] != 1 || in[next] != 2 {
+		t.Fatalf("posted %v; want %s the small blind and %s the big", in, bb, next)
+	}
+	if s.DeadButton || s.Order[s.DealerIdx] != sb {
+		t.Fatalf("button on %s (dead %v), want it live on %s", s.Order[s.DealerIdx], s.DeadButton, sb)
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// Bot is a deterministic, seedable opponent for simulations and tests. Its
+// decision is a pure function of the state it can see (public state plus its
+// own hole cards) and Seed: there is no hidden random stream, so the same spot
+// always yields the same move on every node and in every run.
+type Bot struct {
+	Seed int64
+}
+
+// Decide returns p's move and the amount the corresponding action carries
+// (bet size, or raise-to total; 0 otherwise). ok is false when p cannot act.
+//
+// Strategy: weak hands fold to bets some of the time, made hands bet and
+// raise more often, sizes stay near the minimum.
+func (b Bot) Decide(s *State, p PlayerID) (move Move, amount int64, ok bool) {
+	opts := s.LegalActions(p)
+	if len(opts) == 0 {
+		return "", 0, false
+	}
+	r := rand.New(rand.NewSource(b.spotSeed(s, p)))
+	strength := s.botStrength(p) // 0 = nothing, higher = better made hand
+	roll := r.Intn(100)
+
+	pick := func(m Move) (LegalAction, bool) {
+		for _, la := range opts {
+			if la.Move == m {
+				return la, true
+			}
+		}
+		return LegalAction{}, false
+	}
+	size := func(la LegalAction) int64 {
+		return la.Min + r.Int63n((la.Max-la.Min)/4+1)
+	}
+
+	if _, facing := pick(MoveCall); facing {
+		if la, ok := pick(MoveRaise); ok && roll < 10+15*strength {
+			return MoveRaise, size(la), true
+		}
+		if strength == 0 && roll >= 60 {
+			return MoveFold, 0, true
+		}
+		return MoveCall, 0, true
+	}
+	if la, ok := pick(MoveBet); ok && roll < 20+20*strength {
+		return MoveBet, size(la), true
+	}
+	return MoveCheck, 0, true
+}
+
+// spotSeed hashes the decision point together with the bot's seed.
+func (b Bot) spotSeed(s *State, p PlayerID) int64 {
+	st := s.Seats[p]
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s|%d|%d|%d|%d|%d|%d|%s|%s",
+		b.Seed, p, s.DealerIdx, s.Phase, s.Pot, s.CurrentBet, st.Committed, st.Stack,
+		FormatCards(s.Board), FormatCards(s.Holes[p]))
+	return int64(h.Sum64())
+}
+
+// botStrength is a coarse hand rating: the made category once five cards are
+// known, otherwise a pocket pair (or any pair showing in stud) counts as one.
+func (s *State) botStrength(p PlayerID) int {
+	holes := s.Holes[p]
+	if len(s.Board)+len(holes) >= 5 {
+		hv, _ := EvaluatorFor(s.Variant).Best(s.Board, holes)
+		return int(hv.Cat)
+	}
+	seen := map[Rank]bool{}
+	for _, c := range holes {
+		if seen[c.Rank] {
+			return 1
+		}
+		seen[c.Rank] = true
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -22,22 +23,28 @@ func (c Card) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON decodes "As", "th", "2C", etc. into a Card.
 // Accepts uppercase/lowercase for both rank and suit.
-// Ten must be 'T'/'t' (not '10').
+// Ten may be written 'T'/'t' or "10" (as in "10h", common in imported hand
+// histories); MarshalJSON always writes 'T'.
 func (c *Card) UnmarshalJSON(b []byte) error {
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
 		return err
 	}
 	s = strings.TrimSpace(s)
-	if len(s) != 2 {
-		return fmt.Errorf("invalid card literal %q (want 2 chars like As, Td)", s)
-	}
-	rCh := s[0]
-	sCh := s[1]
-
-	r, ok := charToRank(rCh)
-	if !ok {
-		return fmt.Errorf("invalid rank char %q", rCh)
+	var r Rank
+	var sCh byte
+	switch {
+	case len(s) == 3 && s[:2] == "10":
+		r, sCh = RankTen, s[2]
+	case len(s) == 2:
+		rCh := s[0]
+		var ok bool
+		if r, ok = charToRank(rCh); !ok {
+			return fmt.Errorf("invalid rank char %q", rCh)
+		}
+		sCh = s[1]
+	default:
+		return fmt.Errorf("invalid card literal %q (want a rank and suit like As, Td, 10d)", s)
 	}
 	u := byte(sCh)
 	if u >= 'A' && u <= 'Z' {
@@ -61,6 +68,33 @@ func (c *Card) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// DecodeCards decodes a card list carried in an action payload (typically an
+// Action.Meta value) using the JSON card codec above. The value may be a
+// []string, a []any of strings (after a JSON round-trip) or a []Card (in-proc).
+// Invalid literals and duplicate cards are rejected so a malformed payload from
+// a peer never reaches the engine.
+func DecodeCards(v any) ([]Card, error) {
+	if v == nil {
+		return nil, errors.New("missing card list")
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode card list: %w", err)
+	}
+	var cards []Card
+	if err := json.Unmarshal(raw, &cards); err != nil {
+		return nil, fmt.Errorf("decode card list: %w", err)
+	}
+	seen := make(map[Card]struct{}, len(cards))
+	for _, c := range cards {
+		if _, dup := seen[c]; dup {
+			return nil, fmt.Errorf("duplicate card %s", c.String())
+		}
+		seen[c] = struct{}{}
+	}
+	return cards, nil
+}
+
 // Helpers
 
 func rankToChar(r Rank) (byte, bool) {
@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeCardsFromAnActionPayload(t *testing.T) {
+	want := []Card{{Rank: RankAce, Suit: SuitSpades}, {Rank: RankTen, Suit: SuitHearts}}
+	// the shapes a Meta value takes: in process, and after a JSON round-trip
+	var wire any
+	if err := json.Unmarshal([]byte(`["As","Th"]`), &wire); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []any{[]string{"As", "Th"}, wire, want} {
+		got, err := DecodeCards(v)
+		if err != nil || len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("DecodeCards(%#v) = %v, %v; want %v", v, got, err, want)
+		}
+	}
+}
+
+func TestDecodeCardsRejectsMalformedPayloads(t *testing.T) {
+	for _, v := range []any{
+		nil,
+		"As",                       // not a list
+		[]string{"As", "Zz"},       // no such card
+		[]string{"As", "A"},        // truncated
+		[]any{"As", 7},             // not a string
+		[]string{"As", "Kd", "As"}, // dealt twice
+	} {
+		if got, err := DecodeCards(v); err == nil {
+			t.Errorf("DecodeCards(%#v) = %v, want an error", v, got)
+		}
+	}
+}
+
+func TestTenParsesAsTOr10(t *testing.T) {
+	for in, want := range map[string]Card{
+		`"10h"`: {Rank: RankTen, Suit: SuitHearts},
+		`"10S"`: {Rank: RankTen, Suit: SuitSpades},
+		`"Th"`:  {Rank: RankTen, Suit: SuitHearts},
+		`"tc"`:  {Rank: RankTen, Suit: SuitClubs},
+	} {
+		var c Card
+		if err := json.Unmarshal([]byte(in), &c); err != nil || c != want {
+			t.Errorf("%s decoded as %v, %v; want %v", in, c, err, want)
+		}
+	}
+	for _, in := range []string{`"1h"`, `"100h"`, `"10"`, `"10x"`} {
+		var c Card
+		if err := json.Unmarshal([]byte(in), &c); err == nil {
+			t.Errorf("%s decoded as %v, want an error", in, c)
+		}
+	}
+	if b, err := json.Marshal(Card{Rank: RankTen, Suit: SuitDiamonds}); err != nil || string(b) != `"Td"` {
+		t.Errorf("ten of diamonds encoded as %s, %v; want \"Td\"", b, err)
+	}
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestTotalCommittedSpansTheHandAndSurvivesASnapshot(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	if err := s.Call(s.CurrentPlayer()); err != nil { // 2 each
+		t.Fatal(err)
+	}
+	if err := s.Check(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	s.AdvancePhase()
+	bettor := s.CurrentPlayer()
+	if err := s.Bet(bettor, 6); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Call(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	s.AdvancePhase()
+	for _, p := range s.Order {
+		if st := s.Seats[p]; st.Committed != 0 || st.TotalCommitted != 8 {
+			t.Fatalf("%s on the turn: committed %d this street, %d this hand; want 0, 8", p, st.Committed, st.TotalCommitted)
+		}
+	}
+
+	raw, err := json.Marshal(s.FullSnapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ss EngineSnapshot
+	if err := json.Unmarshal(raw, &ss); err != nil {
+		t.Fatal(err)
+	}
+	var r State
+	r.RestoreFromSnapshot(ss)
+	for _, v := range r.Summary().Seats {
+		if v.TotalCommitted != 8 {
+			t.Fatalf("restored %s shows %d invested this hand, want 8", v.Player, v.TotalCommitted)
+		}
+	}
+
+	// only a new hand starts the count again
+	for s.Phase != PhaseShowdown {
+		s.AdvancePhase()
+	}
+	s.ResolveShowdown()
+	if err := s.StartHand(rand.New(rand.NewSource(2))); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range s.Order {
+		if st := s.Seats[p]; st.TotalCommitted != st.Committed {
+			t.Fatalf("%s carried %d over from the last hand", p, st.TotalCommitted-st.Committed)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestRoundRobinDealsOneCardPerPassFromTheSmallBlind(t *testing.T) {
+	for _, roundRobin := range []bool{false, true} {
+		s := NewState(1, 2)
+		s.RoundRobin = roundRobin
+		for _, p := range []PlayerID{"a", "b", "c", "d"} {
+			if err := s.SitStack(p, 100); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.StartHand(rand.New(rand.NewSource(9))); err != nil {
+			t.Fatal(err)
+		}
+		deck := NewDeckFor(s.Variant, rand.New(rand.NewSource(9))) // the shuffle StartHand used
+
+		sb := posted(t, &s, 1)
+		for i := 0; i < len(s.Order); i++ {
+			p := after(&s, sb, i)
+			want := []Card{deck[2*i], deck[2*i+1]} // two at a time
+			if roundRobin {
+				want = []Card{deck[i], deck[len(s.Order)+i]} // one per pass
+			}
+			if !slices.Equal(s.Holes[p], want) {
+				t.Errorf("round robin %v: %s, %d from the small blind, dealt %v; want %v", roundRobin, p, i, s.Holes[p], want)
+			}
+		}
+		if !slices.Equal(s.Deck, deck[2*len(s.Order):]) {
+			t.Errorf("round robin %v: the rest of the deck is not what was left undealt", roundRobin)
+		}
+	}
+}
+
+func TestBurnsThatWouldRunTheDeckDryAreRefusedUpFront(t *testing.T) {
+	for _, tc := range []struct {
+		variant string
+		players int // the most that fit without burns, one more than with them
+	}{
+		{VariantShort, 15}, // 30 holes + 5 board = 36; burns need 3 more
+		{VariantStud, 7},   // 49 of 52; burns need 5 more
+	} {
+		if MaxPlayers(tc.variant, false) != tc.players || MaxPlayers(tc.variant, true) != tc.players-1 {
+			t.Fatalf("%s: seats %d, %d with burns; want %d, %d", tc.variant,
+				MaxPlayers(tc.variant, false), MaxPlayers(tc.variant, true), tc.players, tc.players-1)
+		}
+		for _, burns := range []bool{false, true} {
+			s := NewState(1, 2)
+			s.Variant, s.BurnCards = tc.variant, burns
+			for i := 0; i < tc.players; i++ {
+				if err := s.SitStack(PlayerID(rune('a'+i)), 100); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := s.StartHand(rand.New(rand.NewSource(1)))
+			if burns && (err == nil || s.HandActive) {
+				t.Fatalf("%s with burns dealt to %d players", tc.variant, tc.players)
+			}
+			if burns {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s without burns: %v", tc.variant, err)
+			}
+			for s.Phase != PhaseShowdown {
+				checkAround(t, &s)
+				s.AdvancePhase()
+			}
+			if tc.variant != VariantStud && len(s.Board) != 5 {
+				t.Fatalf("%s ran out of cards: board %v", tc.variant, s.Board)
+			}
+			for p, cs := range s.Holes {
+				if tc.variant == VariantStud && len(cs) != 7 {
+					t.Fatalf("%s ran out of cards: %s holds %v", tc.variant, p, cs)
+				}
+			}
+		}
+	}
+}
@@ -1,11 +1,64 @@
 package engine
 
-import "math/rand"
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+)
 
-func NewDeck(r *rand.Rand) []Card {
+// deckSize is the number of cards in a full deck.
+const deckSize = 52
+
+// DeckSize is the number of cards the variant plays with.
+func DeckSize(variant string) int {
+	if variant == VariantShort {
+		return 36
+	}
+	return deckSize
+}
+
+// DeckFromSeed is the deck StartHand shuffles from seed: with a hand's revealed
+// seed anyone can check the cards that were dealt.
+func DeckFromSeed(variant string, seed int64) []Card {
+	return NewDeckFor(variant, rand.New(rand.NewSource(seed)))
+}
+
+// SeedCommitment is a player's published commitment to a shuffle seed: the
+// hex SHA-256 of its 8 big-endian bytes. Revealing the seed later proves it
+// was picked before anyone else's was known.
+func SeedCommitment(seed int64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(seed))
+	sum := sha256.Sum256(b[:])
+	return hex.EncodeToString(sum[:])
+}
+
+// CombineSeeds XORs the players' revealed seeds into the one a hand is
+// shuffled from: as long as one player picked theirs at random, nobody —
+// the authority included — can steer the deck.
+func CombineSeeds(seeds ...int64) int64 {
+	var out int64
+	for _, s := range seeds {
+		out ^= s
+	}
+	return out
+}
+
+func NewDeck(r *rand.Rand) []Card { return newDeck(RankTwo, r) }
+
+// NewDeckFor shuffles the variant's deck: short deck starts at the sixes.
+func NewDeckFor(variant string, r *rand.Rand) []Card {
+	if variant == VariantShort {
+		return newDeck(RankSix, r)
+	}
+	return NewDeck(r)
+}
+
+func newDeck(lowest Rank, r *rand.Rand) []Card {
 	deck := make([]Card, 0, 52)
 	for s := SuitClubs; s <= SuitSpades; s++ {
-		for rnk := RankTwo; rnk <= RankAce; rnk++ {
+		for rnk := lowest; rnk <= RankAce; rnk++ {
 			deck = append(deck, Card{Rank: rnk, Suit: s})
 		}
 	}
@@ -16,3 +69,67 @@ func NewDeck(r *rand.Rand) []Card {
 	}
 	return deck
 }
+
+// remainingDeck returns the unshuffled 52-card deck minus the given cards.
+func remainingDeck(used ...[]Card) []Card {
+	out := make(map[Card]struct{})
+	for _, cs := range used {
+		for _, c := range cs {
+			out[c] = struct{}{}
+		}
+	}
+	deck := make([]Card, 0, 52-len(out))
+	for s := SuitClubs; s <= SuitSpades; s++ {
+		for rnk := RankTwo; rnk <= RankAce; rnk++ {
+			if c := (Card{Rank: rnk, Suit: s}); !isIn(out, c) {
+				deck = append(deck, c)
+			}
+		}
+	}
+	return deck
+}
+
+func isIn(set map[Card]struct{}, c Card) bool {
+	_, ok := set[c]
+	return ok
+}
+
+// CardsNeeded is how many cards one hand can consume with players dealt in:
+// hold'em deals 2 each plus a 5-card board, stud up to 7 each; with burns,
+// one card is burned before each board street (3 in hold'em) or each dealing
+// round (5 in stud).
+func CardsNeeded(variant string, players int, burns bool) int {
+	perPlayer, board, burnCount := holeCards(variant), 5, 3
+	if variant == VariantStud {
+		perPlayer, board, burnCount = 7, 0, 5
+	}
+	need := perPlayer*players + board
+	if burns {
+		need += burnCount
+	}
+	return need
+}
+
+// holeCards is how many hole cards a board game deals each player.
+func holeCards(variant string) int {
+	if variant == VariantOmaha {
+		return 4
+	}
+	return 2
+}
+
+// MaxPlayers is the largest table one deck can serve for the variant.
+func MaxPlayers(variant string, burns bool) int {
+	n := 0
+	for CardsNeeded(variant, n+1, burns) <= DeckSize(variant) {
+		n++
+	}
+	return n
+}
+
+// burn discards the top card when the table burns before dealing.
+func (s *State) burn() {
+	if s.BurnCards && len(s.Deck) > 0 {
+		s.Deck = s.Deck[1:]
+	}
+}
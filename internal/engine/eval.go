@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 )
 
@@ -18,6 +19,7 @@ const (
 	CatFullHouse
 	CatQuads
 	CatStraightFlush
+	CatRoyalFlush // an ace-high straight flush
 )
 
 func (c Category) String() string {
@@ -40,6 +42,8 @@ func (c Category) String() string {
 		return "Four of a Kind"
 	case CatStraightFlush:
 		return "Straight Flush"
+	case CatRoyalFlush:
+		return "Royal Flush"
 	default:
 		return fmt.Sprintf("cat(%d)", int(c))
 	}
@@ -53,9 +57,13 @@ type HandValue struct {
 }
 
 // Less reports whether hv < other (i.e., worse hand).
-func (hv HandValue) Less(other HandValue) bool {
-	if hv.Cat != other.Cat {
-		return hv.Cat < other.Cat
+func (hv HandValue) Less(other HandValue) bool { return hv.LessIn(VariantHoldem, other) }
+
+// LessIn is Less under the variant's category order: short deck ranks a flush
+// above a full house; every other variant uses the Category order.
+func (hv HandValue) LessIn(variant string, other HandValue) bool {
+	if a, b := categoryOrder(variant, hv.Cat), categoryOrder(variant, other.Cat); a != b {
+		return a < b
 	}
 	for i := 0; i < 5; i++ {
 		if hv.Ranks[i] != other.Ranks[i] {
@@ -65,6 +73,18 @@ func (hv HandValue) Less(other HandValue) bool {
 	return false
 }
 
+func categoryOrder(variant string, c Category) int {
+	if variant == VariantShort {
+		switch c {
+		case CatFlush:
+			return int(CatFullHouse)
+		case CatFullHouse:
+			return int(CatFlush)
+		}
+	}
+	return int(c)
+}
+
 // Equal reports hv == other.
 func (hv HandValue) Equal(other HandValue) bool {
 	if hv.Cat != other.Cat {
@@ -81,6 +101,111 @@ func (hv HandValue) Equal(other HandValue) bool {
 // BestHand7 evaluates the best 5-card hand from 7 cards (board 5 + hole 2).
 // Returns a comparable HandValue and the 5 cards that make it (useful later for UI/showdown).
 func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
+	return bestHand(board, holes, false)
+}
+
+// BestHandShortDeck is BestHand7 for a deck without 2s–5s, where the ace
+// plays low in A-6-7-8-9 (a 9-high straight). Compare results with LessIn.
+func BestHandShortDeck(board []Card, holes []Card) (HandValue, [5]Card) {
+	return bestHand(board, holes, true)
+}
+
+// BestHand5 scores exactly five cards as they stand, for comparing made hands
+// directly (tools, bots). BestHand7 scores the five it picks the same way.
+func BestHand5(cards [5]Card) HandValue {
+	return score5(cards[:], false)
+}
+
+func bestHand(board []Card, holes []Card, short bool) (HandValue, [5]Card) {
+	five := pickFive(board, holes, short)
+	n := min(len(board)+len(holes), 5) // fewer before the board is out
+	return score5(five[:n], short), five
+}
+
+// score5 is the category and tiebreak ranks of the hand made by cards, five
+// of them once the board is out (fewer can only pair up or play high).
+func score5(cards []Card, short bool) HandValue {
+	var rankCount [15]int
+	var present uint16
+	flush := len(cards) == 5
+	for _, c := range cards {
+		rankCount[c.Rank]++
+		present |= 1 << int(c.Rank)
+		if c.Suit != cards[0].Suit {
+			flush = false
+		}
+	}
+	top := straightTop(present, short)
+	if top != 0 && flush {
+		if top == RankAce {
+			return handValue(CatRoyalFlush, top)
+		}
+		return handValue(CatStraightFlush, top)
+	}
+
+	// ranks grouped by how many of each, high first within a group
+	var byCount [5][]Rank
+	for r := RankAce; r >= RankTwo; r-- {
+		if n := rankCount[r]; n > 0 {
+			byCount[n] = append(byCount[n], r)
+		}
+	}
+	quads, trips, pairs, singles := byCount[4], byCount[3], byCount[2], byCount[1]
+	switch {
+	case len(quads) == 1:
+		return handValue(CatQuads, append(quads, singles...)...)
+	case len(trips) == 1 && len(pairs) == 1:
+		return handValue(CatFullHouse, trips[0], pairs[0])
+	case flush:
+		return handValue(CatFlush, singles...)
+	case top != 0:
+		return handValue(CatStraight, top)
+	case len(trips) == 1:
+		return handValue(CatTrips, append(trips, singles...)...)
+	case len(pairs) == 2:
+		return handValue(CatTwoPair, append(pairs, singles...)...)
+	case len(pairs) == 1:
+		return handValue(CatOnePair, append(pairs, singles...)...)
+	default:
+		return handValue(CatHighCard, singles...)
+	}
+}
+
+// handValue builds a HandValue from its ranks, highest tiebreak first.
+func handValue(cat Category, ranks ...Rank) HandValue {
+	hv := HandValue{Cat: cat}
+	copy(hv.Ranks[:], ranks)
+	return hv
+}
+
+// straightTop is the top rank of the best straight in a rank bitset (bit r
+// for rank r), or 0. The ace also plays low: in the wheel A-2-3-4-5 (top 5),
+// or in short deck A-6-7-8-9 (top 9), but only when no higher run exists.
+func straightTop(bits uint16, short bool) Rank {
+	run := 0
+	for r := 14; r >= 2; r-- {
+		if (bits>>r)&1 == 1 {
+			run++
+			if run == 5 {
+				return Rank(r + 4) // top rank of the run
+			}
+		} else {
+			run = 0
+		}
+	}
+	wheelMask, wheelTop := uint16((1<<14)|(1<<5)|(1<<4)|(1<<3)|(1<<2)), Rank(5)
+	if short {
+		wheelMask, wheelTop = uint16((1<<14)|(1<<9)|(1<<8)|(1<<7)|(1<<6)), Rank(9)
+	}
+	if bits&wheelMask == wheelMask {
+		return wheelTop
+	}
+	return 0
+}
+
+// pickFive picks the five cards of the best hand in board and holes, in the
+// order they play; score5 then scores them.
+func pickFive(board []Card, holes []Card, short bool) [5]Card {
 	// Collect the 7 cards.
 	all := make([]Card, 0, 7)
 	all = append(all, board...)
@@ -101,37 +226,6 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		present |= 1 << r
 	}
 
-	// Helper to build HandValue with sorted kickers desc
-	fill := func(cat Category, ks ...Rank) HandValue {
-		var r [5]Rank
-		for i := range ks {
-			r[i] = ks[i]
-		}
-		return HandValue{Cat: cat, Ranks: r}
-	}
-
-	// Straight-high (top rank) in a rank bitset; includes wheel A-5 straight (returns 5 as top)
-	straightTop := func(bits uint16) Rank {
-		// Wheel: A(14) + 5..2 present -> treat as 5-high straight
-		wheelMask := uint16((1 << 14) | (1 << 5) | (1 << 4) | (1 << 3) | (1 << 2))
-		if bits&wheelMask == wheelMask {
-			return Rank(5)
-		}
-		// Regular: look for 5 consecutive ranks
-		run := 0
-		for r := 14; r >= 2; r-- {
-			if (bits>>r)&1 == 1 {
-				run++
-				if run == 5 {
-					return Rank(r + 4) // top rank of the run
-				}
-			} else {
-				run = 0
-			}
-		}
-		return 0
-	}
-
 	// Build a descending list of ranks by multiplicity (quads, trips, pairs) + kickers
 	type group struct {
 		rank Rank
@@ -160,23 +254,19 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		}
 	}
 	if flushSuit >= 0 {
-		// Straight flush?
-		// Build rank bits for cards of that suit
+		// Straight flush? Build rank bits for cards of that suit
 		var suitBits uint16
 		for _, c := range bySuit[flushSuit] {
 			suitBits |= 1 << int(c.Rank)
 		}
-		if top := straightTop(suitBits); top != 0 {
-			// Return straight flush; we don't need exact 5 cards yet, but let's also pick them
-			best, five := pickStraight(ofSuit(bySuit[flushSuit]), top)
-			_ = best
-			return fill(CatStraightFlush, top), five
+		if top := straightTop(suitBits, short); top != 0 {
+			return pickStraight(bySuit[flushSuit], top, short)
 		}
 		// Regular flush: take top 5 ranks of that suit
 		sort.Slice(bySuit[flushSuit], func(i, j int) bool { return bySuit[flushSuit][i].Rank > bySuit[flushSuit][j].Rank })
 		var five [5]Card
 		copy(five[:], bySuit[flushSuit][:5])
-		return fill(CatFlush, five[0].Rank, five[1].Rank, five[2].Rank, five[3].Rank, five[4].Rank), five
+		return five
 	}
 
 	// Four of a Kind
@@ -184,8 +274,8 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		quad := groups[0].rank
 		kicker := highestExcept(rankCount, quad)
 		five := collectOfRank(all, quad, 4)
-		five[4] = kickerCard(all, kicker)
-		return fill(CatQuads, quad, kicker), five
+		five[4] = kickerCard(all, kicker, five[:4])
+		return five
 	}
 
 	// Full House (3+2; handle multiple trips/pairs)
@@ -203,15 +293,13 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 			five := collectOfRank(all, trip1, 3)
 			two := collectOfRank(all, pairOrTrip, 2)
 			five[3], five[4] = two[0], two[1]
-			return fill(CatFullHouse, trip1, pairOrTrip), five
+			return five
 		}
 	}
 
 	// Straight
-	if top := straightTop(present); top != 0 {
-		best, five := pickStraight(all, top)
-		_ = best
-		return fill(CatStraight, top), five
+	if top := straightTop(present, short); top != 0 {
+		return pickStraight(all, top, short)
 	}
 
 	// Trips
@@ -220,9 +308,9 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		// pick two highest kickers excluding trip
 		k1, k2 := topKickers(rankCount, trip, 2)
 		five := collectOfRank(all, trip, 3)
-		five[3] = kickerCard(all, k1)
-		five[4] = kickerCard(all, k2)
-		return fill(CatTrips, trip, k1, k2), five
+		five[3] = kickerCard(all, k1, five[:3])
+		five[4] = kickerCard(all, k2, five[:4])
+		return five
 	}
 
 	// Two Pair
@@ -233,8 +321,8 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		five := collectOfRank(all, high, 2)
 		p2 := collectOfRank(all, low, 2)
 		five[2], five[3] = p2[0], p2[1]
-		five[4] = kickerCard(all, k)
-		return fill(CatTwoPair, high, low, k), five
+		five[4] = kickerCard(all, k, five[:4])
+		return five
 	}
 
 	// One Pair
@@ -242,21 +330,19 @@ func BestHand7(board []Card, holes []Card) (HandValue, [5]Card) {
 		pair := groups[0].rank
 		k1, k2, k3 := topKickers3(rankCount, pair)
 		five := collectOfRank(all, pair, 2)
-		five[2] = kickerCard(all, k1)
-		five[3] = kickerCard(all, k2)
-		five[4] = kickerCard(all, k3)
-		return fill(CatOnePair, pair, k1, k2, k3), five
+		five[2] = kickerCard(all, k1, five[:2])
+		five[3] = kickerCard(all, k2, five[:3])
+		five[4] = kickerCard(all, k3, five[:4])
+		return five
 	}
 
 	// High Card
 	hi := topNRanks(rankCount, 5, nil)
 	var five [5]Card
-	i := 0
-	for _, r := range hi {
-		five[i] = kickerCard(all, r)
-		i++
+	for i, r := range hi {
+		five[i] = kickerCard(all, r, five[:i])
 	}
-	return fill(CatHighCard, hi[0], hi[1], hi[2], hi[3], hi[4]), five
+	return five
 }
 
 // ===== helpers (kept local to eval.go) =====
@@ -356,46 +442,45 @@ func collectOfRank(all []Card, r Rank, want int) [5]Card {
 	return out
 }
 
-func kickerCard(all []Card, r Rank) Card {
-	// return the highest card with rank r
-	var best Card
-	found := false
+// kickerCard returns the first card of rank r in all that is not already in
+// used (the cards the hand has taken so far), so no card is counted twice.
+// BestHand7 lists board cards before hole cards, so when the board plays, the
+// board card is reported; suits never decide which card plays.
+func kickerCard(all []Card, r Rank, used []Card) Card {
 	for _, c := range all {
-		if c.Rank == r {
-			if !found || c.Suit > best.Suit { // break ties deterministically by suit
-				best = c
-				found = true
-			}
+		if c.Rank == r && !slices.Contains(used, c) {
+			return c
 		}
 	}
-	return best
+	return Card{}
 }
 
-func ofSuit(cards []Card) []Card { return cards }
-
 // pickStraight returns the exact 5 cards forming a straight with given top rank.
-// Works for wheel (top==5 → A-5).
-func pickStraight(all []Card, top Rank) (HandValue, [5]Card) {
+// Works for the wheel (top==5 → A-5; short deck top==9 → A-6-7-8-9).
+func pickStraight(all []Card, top Rank, short bool) [5]Card {
 	var need [5]Rank
-	if top == 5 {
+	switch {
+	case top == 5:
 		need = [5]Rank{5, 4, 3, 2, 14} // 5-4-3-2-A
-	} else {
+	case short && top == 9:
+		need = [5]Rank{9, 8, 7, 6, 14} // 9-8-7-6-A
+	default:
 		need = [5]Rank{top, top - 1, top - 2, top - 3, top - 4}
 	}
 	var five [5]Card
 	used := make(map[int]bool) // avoid reusing exact same card if duplicates (shouldn't happen)
 	idx := 0
 	for _, want := range need {
-		// pick highest suit for determinism
+		// first match wins: board cards precede hole cards, so a straight on the
+		// board is reported as the board
 		bestIdx := -1
 		for i, c := range all {
 			if used[i] {
 				continue
 			}
 			if c.Rank == want {
-				if bestIdx == -1 || c.Suit > all[bestIdx].Suit {
-					bestIdx = i
-				}
+				bestIdx = i
+				break
 			}
 		}
 		if bestIdx >= 0 {
@@ -404,127 +489,365 @@ func pickStraight(all []Card, top Rank) (HandValue, [5]Card) {
 			idx++
 		}
 	}
-	return HandValue{Cat: CatStraight, Ranks: [5]Rank{top}}, five
+	return five
 }
 
 type ShowdownWinner struct {
 	Player PlayerID
 	Value  HandValue
-	Cards  [5]Card
+	Cards  []Card // the hand as chosen by the variant's evaluator
+	Won    int64  // total taken down across every pot this player won
+	Low    []Card // hi-lo: the qualifying low, when it won at least one low half
+}
+
+// PotResult describes how one pot was awarded, in enough detail for a UI to
+// animate chips from the pot to its winners.
+type PotResult struct {
+	Amount       int64
+	Contributors []PlayerID // put chips into this pot this hand
+	Eligible     []PlayerID // could win it (in hand at showdown, covered this pot)
+	Winners      []PlayerID
+	Share        int64 // each winner's even share; odd chips go one each from the dealer's left
+
+	// Hi-lo only: the low half's winners and share. Empty when no low
+	// qualified, in which case the high hand scoops.
+	LowWinners []PlayerID `json:",omitempty"`
+	LowShare   int64      `json:",omitempty"`
 }
 
 type ShowdownSummary struct {
-	Winners     []ShowdownWinner
-	PayoutPer   int64
+	Winners     []ShowdownWinner // everyone who won at least one pot, in seat order
+	PayoutPer   int64            // main pot share per winner (see Pots for side pots)
 	Remainder   int64
-	TotalPayout int64 // = PayoutPer*len(Winners) + Remainder
+	TotalPayout int64 // chips awarded across all pots
+	Pots        []PotResult
+
+	// HighWinners and LowWinners are everyone who took a high or (hi-lo) a
+	// low half of any pot, in seat order. LowWinners is empty when no low
+	// qualified, so the high hands scooped.
+	HighWinners []PlayerID `json:",omitempty"`
+	LowWinners  []PlayerID `json:",omitempty"`
+
+	// UncalledReturn is the part of the last aggressor's bet nobody matched,
+	// handed back to UncalledTo before any pot was awarded.
+	UncalledReturn int64    `json:",omitempty"`
+	UncalledTo     PlayerID `json:",omitempty"`
+
+	// Revealed is everyone who reached showdown: true if they showed, false
+	// if they mucked (see reveal.go). Shown holds the hole cards of those who
+	// showed; mucked cards are never in it, though they were still evaluated.
+	Revealed map[PlayerID]bool   `json:",omitempty"`
+	Shown    map[PlayerID][]Card `json:",omitempty"`
 }
 
-// ResolveShowdown evaluates in-hand players, splits the pot evenly among winners,
-// distributes any remainder deterministically (seat order from dealer+1), and ends the hand.
-// It mutates stacks, clears Pot, sets HandActive=false, and leaves Phase as-is (typically PhaseShowdown).
+// ResolveShowdown first returns any uncalled bet, then evaluates in-hand
+// players and awards the main pot and each side pot independently: a pot goes
+// to the best hand among the players who covered it, so a short all-in can win
+// the main pot while a deeper stack takes a side pot. Each pot is split evenly
+// among its winners, odd chips going one at a time in seat order from
+// dealer+1. In hi-lo each pot is halved between
+// the best high and the best eight-or-better low (the odd chip to the high);
+// with no qualifying low the high scoops. It mutates stacks, clears Pot, sets
+// HandActive=false, and leaves Phase as-is (typically PhaseShowdown).
 func (s *State) ResolveShowdown() ShowdownSummary {
+	uncalledTo, uncalled := s.returnUncalled()
+	ev := EvaluatorFor(s.Variant)
 	// Collect eligible players (still in hand)
 	type eval struct {
-		pid   PlayerID
 		val   HandValue
-		cards [5]Card
+		cards []Card
+		low   LowValue
+		lowC  []Card
+		hasLo bool
 	}
-	var evals []eval
+	evals := map[PlayerID]eval{}
 	for _, pid := range s.Order {
 		st, ok := s.Seats[pid]
-		_ = ok
-		if !ok {
-			continue
-		}
-		if !st.InHand || st.Folded {
+		if !ok || !st.InHand || st.Folded {
 			continue
 		}
-		hc := s.Holes[pid]
-		if len(hc) != 2 {
-			// If a player somehow lacks holes (mid-hand discover), treat as high-card only.
-			// (Alternatively, skip; but this keeps the hand progressing.)
-			hv, five := BestHand7(s.Board, hc)
-			evals = append(evals, eval{pid: pid, val: hv, cards: five})
-			continue
+		// A player lacking holes (mid-hand discover) plays the board.
+		hv, cards := ev.Best(s.Board, s.Holes[pid])
+		e := eval{val: hv, cards: cards}
+		if s.HiLo {
+			if s.Variant == VariantOmaha {
+				e.low, e.lowC, e.hasLo = BestLow8Omaha(s.Board, s.Holes[pid])
+			} else {
+				e.low, e.lowC, e.hasLo = BestLow8(s.Board, s.Holes[pid])
+			}
 		}
-		hv, five := BestHand7(s.Board, hc)
-		evals = append(evals, eval{pid: pid, val: hv, cards: five})
+		evals[pid] = e
 	}
+
+	pots := s.buildPots(func(pid PlayerID) bool { _, ok := evals[pid]; return ok })
 	if len(evals) == 0 {
 		// No one to award: just end the hand.
-		per, rem := int64(0), s.Pot
+		rem := s.Pot
 		s.Pot = 0
 		s.HandActive = false
-		return ShowdownSummary{Winners: nil, PayoutPer: per, Remainder: rem, TotalPayout: per + rem}
-	}
-
-	// Find best value
-	best := evals[0].val
-	for _, e := range evals[1:] {
-		if best.Less(e.val) {
-			best = e.val
+		return ShowdownSummary{Remainder: rem, TotalPayout: rem, Pots: pots, UncalledReturn: uncalled, UncalledTo: uncalledTo}
+	}
+
+	won := map[PlayerID]int64{}
+	var total int64
+	for i := range pots {
+		p := &pots[i]
+		// best hand among this pot's eligible players (ties split)
+		var best HandValue
+		for j, pid := range p.Eligible {
+			if v := evals[pid].val; j == 0 || ev.Less(best, v) {
+				best = v
+			}
 		}
-	}
-	// Collect all winners (ties)
-	var winners []ShowdownWinner
-	for _, e := range evals {
-		if !best.Less(e.val) && !e.val.Less(best) {
-			winners = append(winners, ShowdownWinner{Player: e.pid, Value: e.val, Cards: e.cards})
+		for _, pid := range p.Eligible {
+			if v := evals[pid].val; !ev.Less(best, v) && !ev.Less(v, best) {
+				p.Winners = append(p.Winners, pid)
+			}
+		}
+		high := p.Amount
+		if s.HiLo {
+			var best LowValue
+			for _, pid := range p.Eligible {
+				if e := evals[pid]; e.hasLo && (len(p.LowWinners) == 0 || e.low.Better(best)) {
+					best, p.LowWinners = e.low, []PlayerID{pid}
+				} else if e.hasLo && e.low == best {
+					p.LowWinners = append(p.LowWinners, pid)
+				}
+			}
+			if len(p.LowWinners) > 0 {
+				low := p.Amount / 2
+				high -= low
+				p.LowShare = s.award(low, p.LowWinners, won)
+			}
 		}
+		p.Share = s.award(high, p.Winners, won)
+		total += p.Amount
 	}
 
-	// Payout split
-	nw := int64(len(winners))
-	per := int64(0)
-	rem := int64(0)
-	if s.Pot > 0 && nw > 0 {
-		per = s.Pot / nw
-		rem = s.Pot % nw
-	}
-
-	// Pay each winner 'per'
-	for _, w := range winners {
-		if st, ok := s.Seats[w.Player]; ok {
-			st.Stack += per
-		}
-	}
-	// Deterministic remainder: distribute +1 to winners in seat order starting left of dealer
-	if rem > 0 {
-		// Build winner index set for quick membership
-		winSet := map[PlayerID]int{}
-		for i, w := range winners {
-			winSet[w.Player] = i
-		}
-		start := (s.DealerIdx + 1) % len(s.Order)
-		for i := 0; i < len(s.Order) && rem > 0; i++ {
-			pid := s.Order[(start+i)%len(s.Order)]
-			if idx, ok := winSet[pid]; ok {
-				if st, ok2 := s.Seats[winners[idx].Player]; ok2 {
-					st.Stack += 1
-					rem--
-				}
+	var winners []ShowdownWinner
+	var highs, lows []PlayerID
+	for _, pid := range s.Order { // seat order for stable logs
+		for _, p := range pots {
+			if containsPlayer(p.Winners, pid) {
+				highs = append(highs, pid)
+				break
 			}
 		}
+		for _, p := range pots {
+			if containsPlayer(p.LowWinners, pid) {
+				lows = append(lows, pid)
+				break
+			}
+		}
+		if amt, ok := won[pid]; ok {
+			s.Seats[pid].Stack += amt
+			w := ShowdownWinner{Player: pid, Value: evals[pid].val, Cards: evals[pid].cards, Won: amt}
+			if containsPlayer(lows, pid) {
+				w.Low = evals[pid].lowC
+			}
+			winners = append(winners, w)
+		}
+	}
+
+	vals := make(map[PlayerID]HandValue, len(evals))
+	for pid, e := range evals {
+		vals[pid] = e.val
 	}
+	revealed, shown := s.reveals(ev, vals, won)
 
 	// End hand
-	total := per*int64(len(winners)) + (s.Pot - per*int64(len(winners))) // we’ll zero pot next
 	s.Pot = 0
 	s.HandActive = false
 
-	// Sort winners by seat order for stable logs
-	sort.SliceStable(winners, func(i, j int) bool {
-		pi, pj := posInOrder(s.Order, winners[i].Player), posInOrder(s.Order, winners[j].Player)
-		return pi < pj
-	})
-
 	return ShowdownSummary{
 		Winners:     winners,
-		PayoutPer:   per,
+		PayoutPer:   pots[0].Share,
 		Remainder:   0, // already distributed
 		TotalPayout: total,
+		Pots:        pots,
+		HighWinners: highs,
+		LowWinners:  lows,
+
+		UncalledReturn: uncalled,
+		UncalledTo:     uncalledTo,
+
+		Revealed: revealed,
+		Shown:    shown,
+	}
+}
+
+// AwardUncontested ends a hand everyone else folded: any uncalled bet goes
+// back, then winner takes the whole pot unseen — no further cards are dealt and
+// no hand is evaluated. Phase is left where the hand stopped.
+func (s *State) AwardUncontested(winner PlayerID) ShowdownSummary {
+	uncalledTo, uncalled := s.returnUncalled()
+	amt := s.Pot
+	var contributors []PlayerID
+	for _, pid := range s.Order {
+		if s.Seats[pid].TotalCommitted > 0 {
+			contributors = append(contributors, pid)
+		}
 	}
+	st := s.Seats[winner]
+	st.Stack += amt
+	s.Pot = 0
+	s.HandActive = false
+	sum := ShowdownSummary{
+		Winners:     []ShowdownWinner{{Player: winner, Won: amt}},
+		PayoutPer:   amt,
+		TotalPayout: amt,
+		Pots:        []PotResult{{Amount: amt, Contributors: contributors, Eligible: []PlayerID{winner}, Winners: []PlayerID{winner}, Share: amt}},
+
+		UncalledReturn: uncalled,
+		UncalledTo:     uncalledTo,
+
+		Revealed: map[PlayerID]bool{winner: st.Show},
+	}
+	if st.Show {
+		sum.Shown = map[PlayerID][]Card{winner: append([]Card{}, s.Holes[winner]...)}
+	}
+	return sum
+}
+
+// returnUncalled refunds the excess of the biggest contribution this hand over
+// the next biggest (folded players included): chips nobody called were never
+// contested, so they go straight back rather than into a pot.
+func (s *State) returnUncalled() (PlayerID, int64) {
+	var top PlayerID
+	var first, second int64
+	for _, pid := range s.Order {
+		c := s.Seats[pid].TotalCommitted
+		switch {
+		case c > first:
+			top, first, second = pid, c, first
+		case c > second:
+			second = c
+		}
+	}
+	excess := first - second
+	if top == "" || excess <= 0 || excess > s.Pot {
+		return "", 0
+	}
+	st := s.Seats[top]
+	st.Stack += excess
+	st.TotalCommitted -= excess
+	st.Committed -= min64(st.Committed, excess)
+	s.Pot -= excess
+	return top, excess
+}
+
+// award splits amount evenly among winners into won and returns the share;
+// odd chips go one at a time in seat order starting left of the dealer.
+func (s *State) award(amount int64, winners []PlayerID, won map[PlayerID]int64) int64 {
+	nw := int64(len(winners))
+	share := amount / nw
+	for _, pid := range winners {
+		won[pid] += share
+	}
+	rem := amount % nw
+	start := (s.DealerIdx + 1) % len(s.Order)
+	for k := 0; k < len(s.Order) && rem > 0; k++ {
+		pid := s.Order[(start+k)%len(s.Order)]
+		if containsPlayer(winners, pid) {
+			won[pid]++
+			rem--
+		}
+	}
+	return share
+}
+
+// SidePot is one pot of the hand and the players who may still win it.
+type SidePot struct {
+	Amount   int64
+	Eligible []PlayerID
+}
+
+// ComputeSidePots splits the pot into the main pot followed by side pots, by
+// what each player committed across the whole hand (Seat.TotalCommitted). A
+// player all-in for less is eligible only for the pots they covered; folded
+// players are never eligible. ResolveShowdown awards these same pots.
+func (s *State) ComputeSidePots() []SidePot {
+	pots := s.buildPots(func(pid PlayerID) bool {
+		st, ok := s.Seats[pid]
+		return ok && st.InHand && !st.Folded
+	})
+	out := make([]SidePot, len(pots))
+	for i, p := range pots {
+		out[i] = SidePot{Amount: p.Amount, Eligible: p.Eligible}
+	}
+	return out
+}
+
+// buildPots splits the pot into a main pot and side pots by what each live
+// player put in this hand. Every level a live player committed to closes a pot
+// that only players who reached it may win. Dead money (from players who left
+// mid-hand) goes to the main pot; folded chips above the deepest live player go
+// to the last pot.
+func (s *State) buildPots(live func(PlayerID) bool) []PotResult {
+	var levels []int64
+	var committed int64
+	for _, pid := range s.Order {
+		st := s.Seats[pid]
+		committed += st.TotalCommitted
+		if live(pid) && st.TotalCommitted > 0 && !containsLevel(levels, st.TotalCommitted) {
+			levels = append(levels, st.TotalCommitted)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	if len(levels) == 0 {
+		// no per-hand contributions known (e.g. restored from an old snapshot)
+		pot := PotResult{Amount: s.Pot}
+		for _, pid := range s.Order {
+			if live(pid) {
+				pot.Eligible = append(pot.Eligible, pid)
+			}
+		}
+		return []PotResult{pot}
+	}
+
+	var pots []PotResult
+	prev := int64(0)
+	for _, lvl := range levels {
+		pot := PotResult{}
+		for _, pid := range s.Order {
+			c := s.Seats[pid].TotalCommitted
+			if c > prev {
+				pot.Amount += min64(c, lvl) - prev
+				pot.Contributors = append(pot.Contributors, pid)
+			}
+			if live(pid) && c >= lvl {
+				pot.Eligible = append(pot.Eligible, pid)
+			}
+		}
+		pots = append(pots, pot)
+		prev = lvl
+	}
+	for _, pid := range s.Order {
+		if c := s.Seats[pid].TotalCommitted; c > prev {
+			pots[len(pots)-1].Amount += c - prev
+		}
+	}
+	pots[0].Amount += s.Pot - committed
+	return pots
+}
+
+func containsPlayer(ps []PlayerID, p PlayerID) bool {
+	for _, x := range ps {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLevel(ls []int64, l int64) bool {
+	for _, x := range ls {
+		if x == l {
+			return true
+		}
+	}
+	return false
 }
 
 func posInOrder(order []PlayerID, pid PlayerID) int {
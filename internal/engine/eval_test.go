@@ -0,0 +1,255 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cards parses a space-separated list like "As Kd 10h".
+func cards(t testing.TB, s string) []Card {
+	t.Helper()
+	cs, err := DecodeCards(strings.Fields(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func TestWheelOnlyWhenNoHigherStraight(t *testing.T) {
+	for _, tc := range []struct {
+		board, holes string
+		top          Rank
+	}{
+		{"Ah 2c 3d 4s 9h", "5c Kd", RankFive}, // the wheel
+		{"Ah 2c 3d 4s 5h", "6c Kd", RankSix},  // A-2-3-4-5-6: six high, the ace does not play
+		{"Ah 2c 3d 4s 5h", "6c 7d", RankSeven},
+		{"Ah Kc Qd Js 10h", "2c 3d", RankAce},
+	} {
+		hv, _ := BestHand7(cards(t, tc.board), cards(t, tc.holes))
+		if hv.Cat != CatStraight || hv.Ranks[0] != tc.top {
+			t.Errorf("%s + %s = %v %v, want a %v-high straight", tc.board, tc.holes, hv.Cat, hv.Ranks, tc.top)
+		}
+	}
+}
+
+func TestShortDeckStraightsAndFlushOverFullHouse(t *testing.T) {
+	hv, _ := BestHandShortDeck(cards(t, "Ah 6c 7d 8s Kh"), cards(t, "9c Qd"))
+	if hv.Cat != CatStraight || hv.Ranks[0] != RankNine {
+		t.Fatalf("A-6-7-8-9 = %v %v, want a 9-high straight", hv.Cat, hv.Ranks)
+	}
+	hv, _ = BestHandShortDeck(cards(t, "Ah 6c 7d 8s 9h"), cards(t, "10c Qd"))
+	if hv.Cat != CatStraight || hv.Ranks[0] != RankTen {
+		t.Fatalf("A-6-7-8-9-10 = %v %v, want a 10-high straight", hv.Cat, hv.Ranks)
+	}
+
+	flush, _ := BestHandShortDeck(cards(t, "6h 8h Jh Kc Kd"), cards(t, "Ah 9h"))
+	boat, _ := BestHandShortDeck(cards(t, "6h 8h Jh Kc Kd"), cards(t, "Ks 6s"))
+	if flush.Cat != CatFlush || boat.Cat != CatFullHouse {
+		t.Fatalf("got %v and %v", flush.Cat, boat.Cat)
+	}
+	if !boat.LessIn(VariantShort, flush) || flush.LessIn(VariantShort, boat) {
+		t.Fatal("short deck: a flush should beat a full house")
+	}
+	if !flush.Less(boat) {
+		t.Fatal("hold'em: a full house should still beat a flush")
+	}
+}
+
+func TestBestHand5ScoresEachCategory(t *testing.T) {
+	for _, tc := range []struct {
+		hand  string
+		cat   Category
+		ranks []Rank
+	}{
+		{"Ah Kh Qh Jh 10h", CatRoyalFlush, []Rank{RankAce}},
+		{"5d 4d 3d 2d Ad", CatStraightFlush, []Rank{RankFive}},
+		{"9c 9d 9h 9s 2c", CatQuads, []Rank{RankNine, RankTwo}},
+		{"3c 3d 3h Ks Kc", CatFullHouse, []Rank{RankThree, RankKing}},
+		{"Ac 10c 7c 4c 2c", CatFlush, []Rank{RankAce, RankTen, RankSeven, RankFour, RankTwo}},
+		{"6c 5d 4h 3s 2c", CatStraight, []Rank{RankSix}},
+		{"Qc Qd Qh 7s 2c", CatTrips, []Rank{RankQueen, RankSeven, RankTwo}},
+		{"Jc Jd 4h 4s Ac", CatTwoPair, []Rank{RankJack, RankFour, RankAce}},
+		{"8c 8d Ah 5s 3c", CatOnePair, []Rank{RankEight, RankAce, RankFive, RankThree}},
+		{"Kc 9d 7h 5s 3c", CatHighCard, []Rank{RankKing, RankNine, RankSeven, RankFive, RankThree}},
+	} {
+		var five [5]Card
+		copy(five[:], cards(t, tc.hand))
+		want := HandValue{Cat: tc.cat}
+		copy(want.Ranks[:], tc.ranks)
+		if got := BestHand5(five); !got.Equal(want) {
+			t.Errorf("BestHand5(%s) = %v %v, want %v %v", tc.hand, got.Cat, got.Ranks, want.Cat, want.Ranks)
+		}
+		if got, _ := BestHand7(five[:], nil); !got.Equal(want) {
+			t.Errorf("BestHand7(%s) = %v %v, want %v %v", tc.hand, got.Cat, got.Ranks, want.Cat, want.Ranks)
+		}
+	}
+}
+
+func TestAceHighStraightFlushIsARoyal(t *testing.T) {
+	board := cards(t, "Qh Jh 10h 4c 2d")
+	royal, _ := BestHand7(board, cards(t, "Ah Kh"))
+	kingHigh, _ := BestHand7(board, cards(t, "Kh 9h"))
+	if royal.Cat != CatRoyalFlush || royal.Cat.String() != "Royal Flush" {
+		t.Fatalf("A-high straight flush = %v (%d)", royal.Cat, royal.Cat)
+	}
+	if kingHigh.Cat != CatStraightFlush || kingHigh.Ranks[0] != RankKing || kingHigh.Cat.String() != "Straight Flush" {
+		t.Fatalf("K-high straight flush = %v %v", kingHigh.Cat, kingHigh.Ranks)
+	}
+	quads, _ := BestHand7(cards(t, "Ac Ad As 4c 2d"), cards(t, "Ah Kh"))
+	if !kingHigh.Less(royal) || royal.Less(kingHigh) || !quads.Less(kingHigh) {
+		t.Fatal("want four of a kind < K-high straight flush < royal flush")
+	}
+	if FastEval7(slices.Concat(board, cards(t, "Ah Kh"))) <= FastEval7(slices.Concat(board, cards(t, "Kh 9h"))) {
+		t.Fatal("FastEval7 ranks the royal no higher than a K-high straight flush")
+	}
+}
+
+func TestBestHand7ScoresTheFiveItPicks(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 5000; i++ {
+		deck := NewDeck(r)
+		hv, five := BestHand7(deck[:5], deck[5:7])
+		if got := BestHand5(five); !got.Equal(hv) {
+			t.Fatalf("%v + %v: BestHand7 = %v %v but its five %v score %v %v",
+				deck[:5], deck[5:7], hv.Cat, hv.Ranks, five, got.Cat, got.Ranks)
+		}
+	}
+}
+
+func TestPartialHandsPairUpOrPlayHigh(t *testing.T) {
+	hv, _ := BestHand7(nil, cards(t, "Ah Ad"))
+	if hv.Cat != CatOnePair || hv.Ranks != [5]Rank{RankAce} {
+		t.Fatalf("pocket aces preflop = %v %v", hv.Cat, hv.Ranks)
+	}
+	hv, _ = BestHand7(nil, cards(t, "Ah Kh"))
+	if hv.Cat != CatHighCard || hv.Ranks != [5]Rank{RankAce, RankKing} {
+		t.Fatalf("AK preflop = %v %v", hv.Cat, hv.Ranks)
+	}
+}
+
+// bestHand7Reference is the slow, obviously-correct oracle for BestHand7: it
+// scores every five-card combination of the seven cards (C(7,5) = 21) with
+// BestHand5 and keeps the best.
+func bestHand7Reference(board, holes []Card) HandValue {
+	all := make([]Card, 0, 7)
+	all = append(all, board...)
+	all = append(all, holes...)
+	var best HandValue
+	found := false
+	n := len(all)
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			for c := b + 1; c < n; c++ {
+				for d := c + 1; d < n; d++ {
+					for e := d + 1; e < n; e++ {
+						v := BestHand5([5]Card{all[a], all[b], all[c], all[d], all[e]})
+						if !found || best.Less(v) {
+							best, found = v, true
+						}
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// checkAgainstReference fails t unless BestHand7 matches the oracle, Cat and
+// Ranks, on the seven cards, and the five it picks are five of the seven,
+// none used twice, that score what it reported.
+func checkAgainstReference(t *testing.T, seven []Card) {
+	t.Helper()
+	got, five := BestHand7(seven[:5], seven[5:])
+	want := bestHand7Reference(seven[:5], seven[5:])
+	if got.Cat != want.Cat || got.Ranks != want.Ranks {
+		t.Fatalf("%s: BestHand7 = %v %v, reference %v %v", FormatCards(seven), got.Cat, got.Ranks, want.Cat, want.Ranks)
+	}
+	for i, c := range five {
+		if !slices.Contains(seven, c) || slices.Contains(five[:i], c) {
+			t.Fatalf("%s: BestHand7 picked %s, with %s not one of the seven or used twice", FormatCards(seven), FormatCards(five[:]), c)
+		}
+	}
+	if v := BestHand5(five); !v.Equal(got) {
+		t.Fatalf("%s: BestHand7 = %v %v but its five %s score %v %v", FormatCards(seven), got.Cat, got.Ranks, FormatCards(five[:]), v.Cat, v.Ranks)
+	}
+}
+
+func TestKickersNeverReuseAMadeHandCard(t *testing.T) {
+	for _, seven := range []string{
+		"Ah Kh 9h 9c 2d 9s 4h", // trips beside a four-flush: the kickers are the A and K
+		"9h 9c 9d 9s Kh Ah Qh", // quads, with the ace the one kicker
+		"Kh Kd 7h 7c 2h Ah 3h", // a flush beats two pair, and plays the flush suit only
+		"Qs Qd 5c 5h Qc 5s Ad", // two full houses on offer: queens full of fives
+	} {
+		checkAgainstReference(t, cards(t, seven))
+	}
+}
+
+func TestBestHand7MatchesTheReference(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 100000
+	if testing.Short() {
+		n = 5000
+	}
+	for i := 0; i < n; i++ {
+		checkAgainstReference(t, NewDeck(r)[:7])
+	}
+}
+
+// FuzzBestHand7 deals seven cards from a deck shuffled by the fuzzer's seed.
+func FuzzBestHand7(f *testing.F) {
+	for _, seed := range []int64{0, 1, 42, -7} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkAgainstReference(t, DeckFromSeed(VariantHoldem, seed)[:7])
+	})
+}
+
+func TestEveryonePlaysTheBoard(t *testing.T) {
+	// boards are written high card first, the order hands are reported in
+	for _, tc := range []struct {
+		name, board string
+		holes       [3]string
+	}{
+		{"straight", "9c 8s 7h 6d 5c", [3]string{"2d 2h", "Kd Qs", "3s 3h"}},
+		{"flush", "Kh Jh 9h 5h 2h", [3]string{"As Ad", "Qc Qd", "Ts 9s"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewState(1, 2)
+			players := []PlayerID{"a", "b", "c"}
+			for _, p := range players {
+				if err := s.SitStack(p, 100); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.StartHand(rand.New(rand.NewSource(1))); err != nil {
+				t.Fatal(err)
+			}
+			for s.Phase != PhaseShowdown {
+				checkAround(t, &s)
+				s.AdvancePhase()
+			}
+			board := cards(t, tc.board)
+			s.Board = board
+			for i, p := range players {
+				s.Holes[p] = cards(t, tc.holes[i])
+			}
+
+			sum := s.ResolveShowdown()
+			if len(sum.Winners) != 3 || sum.PayoutPer != 2 || sum.Remainder != 0 {
+				t.Fatalf("winners %+v, %d each (%d over); want all three to split 6", sum.Winners, sum.PayoutPer, sum.Remainder)
+			}
+			for _, w := range sum.Winners {
+				if !slices.Equal(w.Cards, board) {
+					t.Errorf("%s won with %v, want the board %v", w.Player, w.Cards, board)
+				}
+				if s.Seats[w.Player].Stack != 100 {
+					t.Errorf("%s left with %d, want their 100 back", w.Player, s.Seats[w.Player].Stack)
+				}
+			}
+		})
+	}
+}
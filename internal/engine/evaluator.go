@@ -0,0 +1,65 @@
+package engine
+
+import "sync"
+
+// Evaluator ranks hands for a variant. Best picks a player's best hand from
+// the board and their own cards; Less orders two values (a loses to b), and
+// values that are not Less either way tie.
+type Evaluator interface {
+	Best(board, holes []Card) (HandValue, []Card)
+	Less(a, b HandValue) bool
+}
+
+// HighEvaluator is standard high-hand poker (BestHand7), the default.
+type HighEvaluator struct{}
+
+func (HighEvaluator) Best(board, holes []Card) (HandValue, []Card) {
+	hv, five := BestHand7(board, holes)
+	return hv, five[:]
+}
+
+func (HighEvaluator) Less(a, b HandValue) bool { return a.Less(b) }
+
+// OmahaEvaluator plays exactly two hole cards with three from the board.
+type OmahaEvaluator struct{}
+
+func (OmahaEvaluator) Best(board, holes []Card) (HandValue, []Card) {
+	hv, five := BestHandOmaha(board, holes)
+	return hv, five[:]
+}
+
+func (OmahaEvaluator) Less(a, b HandValue) bool { return a.Less(b) }
+
+// ShortDeckEvaluator ranks short-deck hands: A-6-7-8-9 is the lowest
+// straight and a flush beats a full house.
+type ShortDeckEvaluator struct{}
+
+func (ShortDeckEvaluator) Best(board, holes []Card) (HandValue, []Card) {
+	hv, five := BestHandShortDeck(board, holes)
+	return hv, five[:]
+}
+
+func (ShortDeckEvaluator) Less(a, b HandValue) bool { return a.LessIn(VariantShort, b) }
+
+var (
+	evalMu     sync.RWMutex
+	evaluators = map[string]Evaluator{VariantOmaha: OmahaEvaluator{}, VariantShort: ShortDeckEvaluator{}}
+)
+
+// RegisterEvaluator makes ev the showdown evaluator for variant (the value of
+// TableConfig.Variant), so custom games need no change to the engine.
+func RegisterEvaluator(variant string, ev Evaluator) {
+	evalMu.Lock()
+	defer evalMu.Unlock()
+	evaluators[variant] = ev
+}
+
+// EvaluatorFor returns the evaluator registered for variant, or HighEvaluator.
+func EvaluatorFor(variant string) Evaluator {
+	evalMu.RLock()
+	defer evalMu.RUnlock()
+	if ev, ok := evaluators[variant]; ok {
+		return ev
+	}
+	return HighEvaluator{}
+}
@@ -0,0 +1,33 @@
+package engine
+
+import "testing"
+
+// worstWins turns high-hand ranking upside down: the weakest hand takes the pot.
+type worstWins struct{ HighEvaluator }
+
+func (worstWins) Less(a, b HandValue) bool { return b.Less(a) }
+
+func TestResolveShowdownUsesTheRegisteredEvaluator(t *testing.T) {
+	RegisterEvaluator("worst-wins", worstWins{})
+
+	for _, tc := range []struct {
+		variant string
+		winner  PlayerID
+	}{
+		{"", "a"},           // aces beat sevens
+		{"worst-wins", "b"}, // the custom evaluator is asked instead
+	} {
+		s := headsUp(t, 100, 100)
+		s.Variant = tc.variant
+		if err := s.Call(s.CurrentPlayer()); err != nil {
+			t.Fatal(err)
+		}
+		s.Board = cards(t, "Kd Qc 9s 4h 2c")
+		s.Holes = map[PlayerID][]Card{"a": cards(t, "As Ah"), "b": cards(t, "7d 7c")}
+
+		sum := s.ResolveShowdown()
+		if len(sum.Winners) != 1 || sum.Winners[0].Player != tc.winner || sum.Winners[0].Won != 4 {
+			t.Fatalf("variant %q: winners %+v, want %s taking 4", tc.variant, sum.Winners, tc.winner)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkFastEval fails t unless FastEval7 orders x and y as BestHand7 does and
+// puts each in its category.
+func checkFastEval(t *testing.T, x, y []Card) {
+	t.Helper()
+	hx, _ := BestHand7(x, nil)
+	hy, _ := BestHand7(y, nil)
+	fx, fy := FastEval7(x), FastEval7(y)
+	if Category(fx>>20) != hx.Cat || Category(fy>>20) != hy.Cat {
+		t.Fatalf("%s / %s: categories %d / %d, want %v / %v", FormatCards(x), FormatCards(y), fx>>20, fy>>20, hx.Cat, hy.Cat)
+	}
+	if (fx < fy) != hx.Less(hy) || (fy < fx) != hy.Less(hx) {
+		t.Fatalf("%s (%d) vs %s (%d): FastEval7 disagrees with HandValue.Less (%v %v vs %v %v)",
+			FormatCards(x), fx, FormatCards(y), fy, hx.Cat, hx.Ranks, hy.Cat, hy.Ranks)
+	}
+}
+
+func TestFastEval7OrdersHandsAsBestHand7(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 200000
+	if testing.Short() {
+		n = 10000
+	}
+	for i := 0; i < n; i++ {
+		d := NewDeck(r)
+		k := 5 + i%3 // five, six and seven cards
+		checkFastEval(t, d[:k], d[k:2*k])
+	}
+	// hands that differ only in their kickers, or only in suits
+	for _, tc := range [][2]string{
+		{"Ah Ad Kc 7s 5h 3d 2c", "As Ac Kd 7h 4s 3c 2d"},
+		{"Ah 2d 3c 4s 5h Kd Kc", "6h 2d 3c 4s 5h 9d 9c"},
+		{"Th Jh Qh Kh Ah 2c 3d", "Ts Js Qs Ks As 4c 5d"},
+	} {
+		checkFastEval(t, cards(t, tc[0]), cards(t, tc[1]))
+	}
+}
+
+func FuzzFastEval7(f *testing.F) {
+	for _, seed := range []int64{0, 1, 42, -7} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		d := DeckFromSeed(VariantHoldem, seed)
+		checkFastEval(t, d[:7], d[7:14])
+	})
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes a HandValue for logs and APIs as
+// {"category":"Full House","description":"Aces full of Kings","ranks":["A","A","A","K","K"]}:
+// ranks spells out the five cards' ranks in the order they play.
+func (hv HandValue) MarshalJSON() ([]byte, error) {
+	played := hv.played()
+	ranks := make([]string, 0, len(played))
+	for _, r := range played {
+		ch, ok := rankToChar(r)
+		if !ok {
+			return nil, fmt.Errorf("invalid rank: %d", r)
+		}
+		ranks = append(ranks, string(ch))
+	}
+	return json.Marshal(struct {
+		Category    string   `json:"category"`
+		Description string   `json:"description"`
+		Ranks       []string `json:"ranks"`
+	}{hv.Cat.String(), hv.Describe(), ranks})
+}
+
+// Describe names the hand in prose: "Aces full of Kings", "King-high flush",
+// "Wheel straight".
+func (hv HandValue) Describe() string {
+	r := hv.Ranks
+	switch hv.Cat {
+	case CatHighCard:
+		return rankName(r[0]) + " high"
+	case CatOnePair:
+		return "Pair of " + rankPlural(r[0])
+	case CatTwoPair:
+		return rankPlural(r[0]) + " and " + rankPlural(r[1])
+	case CatTrips:
+		return "Three " + rankPlural(r[0])
+	case CatStraight:
+		if r[0] == RankFive {
+			return "Wheel straight"
+		}
+		return rankName(r[0]) + "-high straight"
+	case CatFlush:
+		return rankName(r[0]) + "-high flush"
+	case CatFullHouse:
+		return rankPlural(r[0]) + " full of " + rankPlural(r[1])
+	case CatQuads:
+		return "Four " + rankPlural(r[0])
+	case CatStraightFlush:
+		return rankName(r[0]) + "-high straight flush"
+	case CatRoyalFlush:
+		return "Royal flush"
+	default:
+		return hv.Cat.String()
+	}
+}
+
+// played expands Ranks (the category's ranks plus kickers) into the ranks of
+// the cards as they play, high first; a wheel's ace plays last. Stud's partial
+// boards may hold fewer than five.
+func (hv HandValue) played() []Rank {
+	if hv.Cat == CatStraight || hv.Cat == CatStraightFlush || hv.Cat == CatRoyalFlush {
+		top := hv.Ranks[0]
+		out := make([]Rank, 0, 5)
+		for i := Rank(0); i < 5; i++ {
+			r := top - i
+			if r < RankTwo {
+				r = RankAce // the wheel
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+	var groups []int
+	switch hv.Cat {
+	case CatOnePair:
+		groups = []int{2, 1, 1, 1}
+	case CatTwoPair:
+		groups = []int{2, 2, 1}
+	case CatTrips:
+		groups = []int{3, 1, 1}
+	case CatFullHouse:
+		groups = []int{3, 2}
+	case CatQuads:
+		groups = []int{4, 1}
+	default:
+		groups = []int{1, 1, 1, 1, 1}
+	}
+	out := make([]Rank, 0, 5)
+	for i, n := range groups {
+		if hv.Ranks[i] == 0 {
+			break
+		}
+		for ; n > 0; n-- {
+			out = append(out, hv.Ranks[i])
+		}
+	}
+	return out
+}
+
+var rankNames = map[Rank][2]string{
+	RankTwo: {"Two", "Twos"}, RankThree: {"Three", "Threes"}, RankFour: {"Four", "Fours"},
+	RankFive: {"Five", "Fives"}, RankSix: {"Six", "Sixes"}, RankSeven: {"Seven", "Sevens"},
+	RankEight: {"Eight", "Eights"}, RankNine: {"Nine", "Nines"}, RankTen: {"Ten", "Tens"},
+	RankJack: {"Jack", "Jacks"}, RankQueen: {"Queen", "Queens"}, RankKing: {"King", "Kings"},
+	RankAce: {"Ace", "Aces"},
+}
+
+func rankName(r Rank) string {
+	if n, ok := rankNames[r]; ok {
+		return n[0]
+	}
+	return fmt.Sprintf("rank(%d)", int(r))
+}
+
+func rankPlural(r Rank) string {
+	if n, ok := rankNames[r]; ok {
+		return n[1]
+	}
+	return fmt.Sprintf("rank(%d)", int(r))
+}
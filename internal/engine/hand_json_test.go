@@ -0,0 +1,24 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHandValuesMarshalWithADescription(t *testing.T) {
+	for _, tc := range []struct {
+		board, holes string
+		want         string
+	}{
+		{"Ac Ad Kh Ks 2c", "As 7d", `{"category":"Full House","description":"Aces full of Kings","ranks":["A","A","A","K","K"]}`},
+		{"Jc Jd 4h 4s 2c", "Ac 9d", `{"category":"Two Pair","description":"Jacks and Fours","ranks":["J","J","4","4","A"]}`},
+		{"Ah 2c 3d 4s 9h", "5c Kd", `{"category":"Straight","description":"Wheel straight","ranks":["5","4","3","2","A"]}`},
+		{"Kh Jh 9h 5h 2c", "3h 8d", `{"category":"Flush","description":"King-high flush","ranks":["K","J","9","5","3"]}`},
+	} {
+		hv, _ := BestHand7(cards(t, tc.board), cards(t, tc.holes))
+		b, err := json.Marshal(hv)
+		if err != nil || string(b) != tc.want {
+			t.Errorf("%s + %s: %s, %v\nwant %s", tc.board, tc.holes, b, err, tc.want)
+		}
+	}
+}
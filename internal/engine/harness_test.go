@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// seated sits players a, b, c, ... with these stacks at a 1/2 table, once
+// opts (if not nil) has set the table up.
+func seated(t testing.TB, opts func(*State), stacks ...int64) *State {
+	t.Helper()
+	s := NewState(1, 2)
+	if opts != nil {
+		opts(&s)
+	}
+	for i, stack := range stacks {
+		if err := s.SitStack(PlayerID(rune('a'+i)), stack); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &s
+}
+
+// dealt is seated with the first hand dealt, from seed 1.
+func dealt(t testing.TB, opts func(*State), stacks ...int64) *State {
+	t.Helper()
+	s := seated(t, opts, stacks...)
+	deal(t, s, 1)
+	return s
+}
+
+// deal starts s's next hand from seed.
+func deal(t testing.TB, s *State, seed int64) {
+	t.Helper()
+	if err := s.StartHand(rand.New(rand.NewSource(seed))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// blinds is an opts for dealt and seated that plays at sb/bb instead.
+func blinds(sb, bb int64) func(*State) {
+	return func(s *State) { s.SmallBlind, s.BigBlind = sb, bb }
+}
+
+// foldOut folds for whoever is to act until one player is left, and awards
+// them the pot.
+func foldOut(t testing.TB, s *State) {
+	t.Helper()
+	for {
+		if w, ok := s.OnlyOneInHand(); ok {
+			s.AwardUncontested(w)
+			return
+		}
+		if err := s.Fold(s.CurrentPlayer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
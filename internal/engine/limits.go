@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// Betting structures (State.Structure).
+const (
+	NoLimit    = "nolimit" // default when empty
+	PotLimit   = "potlimit"
+	FixedLimit = "fixedlimit"
+)
+
+// fixedLimitCap is how many bets and raises one fixed-limit street allows:
+// bet, raise, re-raise, cap. Preflop the big blind is the first of them.
+const fixedLimitCap = 4
+
+// LimitError is Bet or Raise refusing a size the betting structure does not
+// allow. Amount is the bet, or the raise on top of the call.
+type LimitError struct {
+	Structure string
+	Amount    int64
+	Max       int64 // the most allowed (fixed limit: the only size); 0 once the street is capped
+}
+
+func (e *LimitError) Error() string {
+	switch {
+	case e.Max == 0:
+		return fmt.Sprintf("%s: betting is capped this street", e.Structure)
+	case e.Structure == FixedLimit:
+		return fmt.Sprintf("%s: bets and raises are %d this street, not %d", e.Structure, e.Max, e.Amount)
+	}
+	return fmt.Sprintf("%s: %d is more than the pot allows (%d)", e.Structure, e.Amount, e.Max)
+}
+
+// betUnit is the fixed-limit bet and raise size: the small bet (the big
+// blind) on the first two streets, the big bet (twice that) from the turn —
+// in stud, from 5th street.
+func (s *State) betUnit() int64 {
+	switch s.Phase {
+	case PhaseTurn, PhaseRiver, PhaseSeventh:
+		return 2 * s.BigBlind
+	}
+	return s.BigBlind
+}
+
+// maxAdd is the largest bet, or raise on top of a call of need, that the
+// structure allows now; ok is false once a fixed-limit street is capped.
+// Pot limit allows the pot after the call.
+func (s *State) maxAdd(need int64) (most int64, ok bool) {
+	switch s.Structure {
+	case PotLimit:
+		return max(s.Pot+need, s.BigBlind), true
+	case FixedLimit:
+		if s.Raises >= fixedLimitCap {
+			return 0, false
+		}
+		return s.betUnit(), true
+	}
+	return math.MaxInt64, true
+}
+
+// checkLimit vets a bet or raise of add (after calling need) against the
+// structure. A fixed-limit player may go all in for less than the unit.
+func (s *State) checkLimit(add, need int64, allIn bool) error {
+	most, ok := s.maxAdd(need)
+	if !ok {
+		return &LimitError{Structure: s.Structure, Amount: add}
+	}
+	if add > most || (s.Structure == FixedLimit && add < most && !allIn) {
+		return &LimitError{Structure: s.Structure, Amount: add, Max: most}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+// limitTable deals a three-handed 1/2 hand under structure.
+func limitTable(t *testing.T, structure string) *State {
+	t.Helper()
+	return dealt(t, func(s *State) { s.Structure = structure }, 100, 100, 100)
+}
+
+func TestPotLimitRaiseAfterACallIsThePotPlusTheCall(t *testing.T) {
+	s := limitTable(t, PotLimit)
+	if err := s.Call(s.CurrentPlayer()); err != nil { // the button limps: pot 5
+		t.Fatal(err)
+	}
+	// the small blind calls 1 more, making 6, and may raise that much on top
+	sb := s.CurrentPlayer()
+	var le *LimitError
+	if err := s.Raise(sb, 7); !errors.As(err, &le) || le.Max != 6 || le.Amount != 7 {
+		t.Fatalf("raise of 7 over a pot of 5 and a call of 1: %v, want a LimitError with Max 6", err)
+	}
+	if err := s.Raise(sb, 6); err != nil {
+		t.Fatalf("pot-sized raise of 6: %v", err)
+	}
+	if s.CurrentBet != 8 || s.Pot != 12 {
+		t.Fatalf("bar %d, pot %d after the pot raise; want 8 and 12", s.CurrentBet, s.Pot)
+	}
+}
+
+func TestFixedLimitRejectsAnOversizedBet(t *testing.T) {
+	s := limitTable(t, FixedLimit)
+	checkAround(t, s)
+	s.AdvancePhase()
+	if s.Phase != PhaseFlop {
+		t.Fatalf("phase %s, want the flop", s.Phase)
+	}
+	p := s.CurrentPlayer()
+	var le *LimitError
+	if err := s.Bet(p, 4); !errors.As(err, &le) || le.Max != 2 {
+		t.Fatalf("flop bet of 4 at 1/2 fixed limit: %v, want a LimitError with Max 2", err)
+	}
+	if err := s.Bet(p, 2); err != nil {
+		t.Fatalf("small bet of 2: %v", err)
+	}
+	// raise, re-raise, cap: the fourth bet closes the street to raises
+	for range 3 {
+		if err := s.Raise(s.CurrentPlayer(), 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Raise(s.CurrentPlayer(), 2); !errors.As(err, &le) || le.Max != 0 {
+		t.Fatalf("a fifth bet: %v, want the street capped", err)
+	}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import "sort"
+
+// LowValue is an ace-to-five low: five distinct ranks, highest first, with the
+// ace counted as 1. Straights and flushes do not count against a low.
+type LowValue [5]int
+
+// Better reports whether l is a better (lower) low than o.
+func (l LowValue) Better(o LowValue) bool {
+	for i := range l {
+		if l[i] != o[i] {
+			return l[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (l LowValue) String() string {
+	names := map[int]string{1: "A", 2: "2", 3: "3", 4: "4", 5: "5", 6: "6", 7: "7", 8: "8"}
+	out := ""
+	for i, r := range l {
+		if i > 0 {
+			out += "-"
+		}
+		out += names[r]
+	}
+	return out
+}
+
+// lowRank is r's value in a low hand (ace plays as 1).
+func lowRank(r Rank) int {
+	if r == RankAce {
+		return 1
+	}
+	return int(r)
+}
+
+// BestLow8 returns the best eight-or-better low from the board and holes, the
+// five cards making it, and whether any low qualifies at all.
+func BestLow8(board, holes []Card) (LowValue, []Card, bool) {
+	byRank := map[int]Card{}
+	for _, c := range append(append([]Card{}, board...), holes...) {
+		if r := lowRank(c.Rank); r <= 8 {
+			if _, ok := byRank[r]; !ok {
+				byRank[r] = c
+			}
+		}
+	}
+	if len(byRank) < 5 {
+		return LowValue{}, nil, false
+	}
+	ranks := make([]int, 0, len(byRank))
+	for r := range byRank {
+		ranks = append(ranks, r)
+	}
+	sort.Ints(ranks)
+	var lv LowValue
+	cards := make([]Card, 5)
+	for i := 0; i < 5; i++ {
+		lv[i] = ranks[4-i]
+		cards[i] = byRank[ranks[4-i]]
+	}
+	return lv, cards, true
+}
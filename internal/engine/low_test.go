@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// hiLoShowdown plays a limped heads-up hi-lo pot of 4 to showdown with these
+// cards.
+func hiLoShowdown(t *testing.T, board, a, b string) (*State, ShowdownSummary) {
+	t.Helper()
+	s := headsUp(t, 100, 100)
+	s.HiLo = true
+	if err := s.Call(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	s.Board = cards(t, board)
+	s.Holes = map[PlayerID][]Card{"a": cards(t, a), "b": cards(t, b)}
+	return s, s.ResolveShowdown()
+}
+
+func TestHiLoSplitsThePot(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		board, a, b string
+		wonA, wonB  int64
+	}{
+		// no five ranks of eight or under: b's straight takes it all
+		{"scoop", "Kd Qs 9d 8c 3h", "Ah Ac", "Jc Th", 0, 4},
+		// a's trip kings have no low; b's 7-4-3-2-A takes the other half
+		{"split", "Ah 2d 7c Kd Qs", "Kc Ks", "3h 4c", 2, 2},
+		// a's flush takes the high half and ties b's wheel for the low
+		{"quartered", "Ah 2d 3c Kd 9d", "4d 5d", "4c 5h", 3, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sum := hiLoShowdown(t, tc.board, tc.a, tc.b)
+			won := map[PlayerID]int64{}
+			for _, w := range sum.Winners {
+				won[w.Player] = w.Won
+			}
+			if won["a"] != tc.wonA || won["b"] != tc.wonB || sum.TotalPayout != 4 {
+				t.Fatalf("a won %d, b won %d of %d; want %d and %d of 4",
+					won["a"], won["b"], sum.TotalPayout, tc.wonA, tc.wonB)
+			}
+			if s.Seats["a"].Stack != 98+tc.wonA || s.Seats["b"].Stack != 98+tc.wonB {
+				t.Fatalf("stacks a %d, b %d", s.Seats["a"].Stack, s.Seats["b"].Stack)
+			}
+		})
+	}
+}
+
+func TestHiLoOddChipGoesToTheHighHalf(t *testing.T) {
+	s := NewState(1, 2)
+	for _, p := range []PlayerID{"a", "b", "c"} {
+		if err := s.SitStack(p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.StartHand(rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+	s.HiLo = true
+	// the button limps, the small blind folds its 1, the big blind checks: 5
+	high := s.CurrentPlayer()
+	if err := s.Call(high); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Fold(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	low := s.CurrentPlayer()
+	if err := s.Check(low); err != nil {
+		t.Fatal(err)
+	}
+	s.Board = cards(t, "Ah 2d 7c Kd Qs")
+	s.Holes = map[PlayerID][]Card{high: cards(t, "Kc Ks"), low: cards(t, "3h 4c")}
+	sum := s.ResolveShowdown()
+	if p := sum.Pots[0]; p.Amount != 5 || p.Share != 3 || p.LowShare != 2 {
+		t.Fatalf("pot %d split %d high / %d low, want 5 as 3 / 2", p.Amount, p.Share, p.LowShare)
+	}
+}
+
+func TestShowdownNamesTheHighAndLowWinners(t *testing.T) {
+	_, sum := hiLoShowdown(t, "Kd Qs 9d 8c 3h", "Ah Ac", "Jc Th")
+	if !slices.Equal(sum.HighWinners, []PlayerID{"b"}) || len(sum.LowWinners) != 0 || len(sum.Pots[0].LowWinners) != 0 {
+		t.Fatalf("no qualifying low: high %v, low %v; want b alone and no low", sum.HighWinners, sum.LowWinners)
+	}
+
+	_, sum = hiLoShowdown(t, "Ah 2d 3c Kd 9d", "4d 5d", "4c 5h")
+	if !slices.Equal(sum.HighWinners, []PlayerID{"a"}) || !slices.Equal(sum.LowWinners, []PlayerID{"a", "b"}) {
+		t.Fatalf("quartered: high %v, low %v; want a, then a and b", sum.HighWinners, sum.LowWinners)
+	}
+}
+
+func TestLowIgnoresStraightsAndFlushes(t *testing.T) {
+	lv, five, ok := BestLow8(cards(t, "Ah 2h 3h Kc Qd"), cards(t, "4h 5h"))
+	if !ok || lv != (LowValue{5, 4, 3, 2, 1}) || len(five) != 5 {
+		t.Fatalf("a steel wheel: low %v (%v, %v), want the nut 5-4-3-2-A", lv, five, ok)
+	}
+	if _, _, ok := BestLow8(cards(t, "Ah 2h 9c Kc Qd"), cards(t, "2c 7d")); ok {
+		t.Fatal("four low ranks qualified as a low")
+	}
+	if _, _, ok := BestLow8(cards(t, "Ah 2h 3c Kc Qd"), cards(t, "4c 9d")); ok {
+		t.Fatal("a nine qualified for an eight-or-better low")
+	}
+}
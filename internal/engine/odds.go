@@ -0,0 +1,163 @@
+package engine
+
+import "math/rand"
+
+// CategoryDistribution returns, for two hole cards on a partial board (0–5
+// cards), the fraction of run-outs ending in each hand category. When the
+// number of distinct run-outs is at most iters they are all enumerated exactly;
+// otherwise iters run-outs are sampled with a fixed seed, so results are
+// reproducible. Returns nil for invalid input (wrong counts or repeated cards).
+func CategoryDistribution(holes, board []Card, iters int) map[Category]float64 {
+	if len(holes) != 2 || len(board) > 5 || iters <= 0 {
+		return nil
+	}
+	if distinctCards(board, map[PlayerID][]Card{"": holes}) != nil {
+		return nil
+	}
+	deck := remainingDeck(holes, board)
+	need := 5 - len(board)
+
+	counts := make(map[Category]int)
+	total := 0
+	hand := make([]Card, 0, 7)
+	tally := func(extra []Card) {
+		hand = append(append(append(hand[:0], board...), extra...), holes...)
+		counts[Category(FastEval7(hand)>>20)]++
+		total++
+	}
+
+	if combinations(len(deck), need) <= iters {
+		pick := make([]Card, 0, need)
+		var walk func(start int)
+		walk = func(start int) {
+			if len(pick) == need {
+				tally(pick)
+				return
+			}
+			for i := start; i < len(deck); i++ {
+				pick = append(pick, deck[i])
+				walk(i + 1)
+				pick = pick[:len(pick)-1]
+			}
+		}
+		walk(0)
+	} else {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < iters; i++ {
+			// partial Fisher-Yates: the first need cards form the run-out
+			for j := 0; j < need; j++ {
+				k := j + r.Intn(len(deck)-j)
+				deck[j], deck[k] = deck[k], deck[j]
+			}
+			tally(deck[:need])
+		}
+	}
+
+	dist := make(map[Category]float64, len(counts))
+	for cat, n := range counts {
+		dist[cat] = float64(n) / float64(total)
+	}
+	return dist
+}
+
+// combinations is n choose k, saturating well above any practical iters.
+func combinations(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := 1
+	for i := 0; i < k; i++ {
+		c = c * (n - i) / (i + 1)
+		if c > 1<<40 {
+			return c
+		}
+	}
+	return c
+}
+
+// Equity estimates the share of the pot two hole cards win against opponents
+// random hands: each of iters trials deals the opponents' holes and the rest of
+// the board from the cards not in hole or board. A win scores 1 and an n-way
+// tie 1/n. r drives the deals; nil uses a fixed seed, so results repeat.
+// Returns 0 for invalid input (wrong counts, repeated cards, too few cards).
+func Equity(hole, board []Card, opponents, iters int, r *rand.Rand) float64 {
+	if len(hole) != 2 || len(board) > 5 || opponents < 1 || iters <= 0 {
+		return 0
+	}
+	if distinctCards(board, map[PlayerID][]Card{"": hole}) != nil {
+		return 0
+	}
+	deck := remainingDeck(hole, board)
+	need := 5 - len(board)
+	draw := need + 2*opponents
+	if draw > len(deck) {
+		return 0
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(1))
+	}
+
+	hand := make([]Card, 0, 7)
+	score := func(holes, run []Card) int32 {
+		hand = append(append(append(hand[:0], board...), run...), holes...)
+		return FastEval7(hand)
+	}
+	var won float64
+	for i := 0; i < iters; i++ {
+		// partial Fisher-Yates: the run-out first, then two cards per opponent
+		for j := 0; j < draw; j++ {
+			k := j + r.Intn(len(deck)-j)
+			deck[j], deck[k] = deck[k], deck[j]
+		}
+		run := deck[:need]
+		mine, ties := score(hole, run), 1
+		beaten := false
+		for o := 0; o < opponents; o++ {
+			theirs := score(deck[need+2*o:need+2*o+2], run)
+			if theirs > mine {
+				beaten = true
+				break
+			}
+			if theirs == mine {
+				ties++
+			}
+		}
+		if !beaten {
+			won += 1 / float64(ties)
+		}
+	}
+	return won / float64(iters)
+}
+
+// Outs lists the unseen cards that would lift the best hand made from hole and
+// a flop or turn board into a higher category, in deck order. It is nil
+// preflop, on the river and for invalid input. See OutsByCategory.
+func Outs(hole, board []Card) []Card {
+	var outs []Card
+	walkOuts(hole, board, func(c Card, _ Category) { outs = append(outs, c) })
+	return outs
+}
+
+// OutsByCategory groups Outs by the category each card makes, so a flush
+// draw shows its 9 outs under Flush, apart from the cards that merely pair.
+func OutsByCategory(hole, board []Card) map[Category][]Card {
+	outs := map[Category][]Card{}
+	walkOuts(hole, board, func(c Card, cat Category) { outs[cat] = append(outs[cat], c) })
+	return outs
+}
+
+func walkOuts(hole, board []Card, fn func(Card, Category)) {
+	if len(hole) != 2 || len(board) < 3 || len(board) > 4 {
+		return
+	}
+	if distinctCards(board, map[PlayerID][]Card{"": hole}) != nil {
+		return
+	}
+	hand := append(append(make([]Card, 0, 7), board...), hole...)
+	now := Category(FastEval7(hand) >> 20)
+	for _, c := range remainingDeck(hole, board) {
+		if cat := Category(FastEval7(append(hand, c)) >> 20); cat > now {
+			fn(c, cat)
+		}
+	}
+}
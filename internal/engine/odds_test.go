@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFloppedFlushDrawMakesItOftenEnough(t *testing.T) {
+	holes, board := cards(t, "As Ks"), cards(t, "2s 7s Jd")
+
+	// 1081 turn-and-river pairs, 378 of them with a spade: few enough to enumerate
+	dist := CategoryDistribution(holes, board, 2000)
+	if got, want := dist[CatFlush], 378.0/1081; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("flush by the river %.4f of the time, want %.4f", got, want)
+	}
+	var sum float64
+	for _, f := range dist {
+		sum += f
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("fractions sum to %v: %v", sum, dist)
+	}
+
+	sampled := CategoryDistribution(holes, board, 500)
+	if got := sampled[CatFlush]; math.Abs(got-378.0/1081) > 0.07 {
+		t.Fatalf("sampled flush fraction %.4f, want about 0.35", got)
+	}
+	if CategoryDistribution(holes, cards(t, "As 7s Jd"), 100) != nil {
+		t.Fatal("a card both in the hand and on the board was accepted")
+	}
+}
+
+func TestAcesWinAboutEightyFivePercentHeadsUp(t *testing.T) {
+	aces := cards(t, "As Ah")
+	eq := Equity(aces, nil, 1, 20000, rand.New(rand.NewSource(1)))
+	if math.Abs(eq-0.85) > 0.015 {
+		t.Fatalf("AA against one random hand: %.3f, want about 0.85", eq)
+	}
+	if again := Equity(aces, nil, 1, 20000, rand.New(rand.NewSource(1))); again != eq {
+		t.Fatalf("the same seed gave %.4f, then %.4f", eq, again)
+	}
+	if more := Equity(aces, nil, 4, 20000, rand.New(rand.NewSource(1))); more >= eq {
+		t.Fatalf("AA against four hands %.3f, no worse than against one (%.3f)", more, eq)
+	}
+
+	// a royal on the board: every hand plays it, so every pot is split
+	if eq := Equity(cards(t, "2c 3d"), cards(t, "Ah Kh Qh Jh Th"), 1, 1000, nil); eq != 0.5 {
+		t.Fatalf("playing the board for a split: equity %v, want 0.5", eq)
+	}
+	if eq := Equity(aces, cards(t, "As 7s Jd"), 1, 100, nil); eq != 0 {
+		t.Fatalf("a card both in the hand and on the board: equity %v, want 0", eq)
+	}
+}
+
+func TestOutsToTheNextCategory(t *testing.T) {
+	for _, tc := range []struct {
+		name, holes, board string
+		cat                Category
+		outs               int
+	}{
+		{"flush draw", "As Ks", "2s 7s Jd", CatFlush, 9},
+		{"open-ended straight draw", "8h 9c", "Td Jc 2s", CatStraight, 8},
+		{"gutshot on the turn", "8h 9c", "Td Qc 2s 3d", CatStraight, 4},
+	} {
+		byCat := OutsByCategory(cards(t, tc.holes), cards(t, tc.board))
+		if got := len(byCat[tc.cat]); got != tc.outs {
+			t.Errorf("%s: %d outs to a %s (%v), want %d", tc.name, got, tc.cat, byCat[tc.cat], tc.outs)
+		}
+		total := 0
+		for _, cs := range byCat {
+			total += len(cs)
+		}
+		if all := Outs(cards(t, tc.holes), cards(t, tc.board)); len(all) != total {
+			t.Errorf("%s: Outs lists %d cards, OutsByCategory %d", tc.name, len(all), total)
+		}
+	}
+	if Outs(cards(t, "As Ks"), nil) != nil || Outs(cards(t, "As Ks"), cards(t, "2s 7s Jd 4c 9h")) != nil {
+		t.Fatal("outs counted preflop or on the river")
+	}
+}
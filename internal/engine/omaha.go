@@ -0,0 +1,54 @@
+package engine
+
+// BestHandOmaha evaluates the best Omaha hand: exactly two of the hole cards
+// with exactly three of the board, over every C(4,2)×C(5,3) combination. A
+// board flush is therefore no use to a player holding fewer than two of the suit.
+func BestHandOmaha(board, holes []Card) (HandValue, [5]Card) {
+	var best HandValue
+	var bestFive [5]Card
+	found := false
+	omahaHands(board, holes, func(hand []Card) {
+		hv, five := BestHand7(hand, nil)
+		if !found || best.Less(hv) {
+			best, bestFive, found = hv, five, true
+		}
+	})
+	if !found {
+		// too few cards for a legal hand (board not out yet): rate what there is
+		return BestHand7(board, holes)
+	}
+	return best, bestFive
+}
+
+// BestLow8Omaha is BestLow8 under the same two-plus-three rule.
+func BestLow8Omaha(board, holes []Card) (LowValue, []Card, bool) {
+	var best LowValue
+	var bestCards []Card
+	found := false
+	omahaHands(board, holes, func(hand []Card) {
+		lv, cards, ok := BestLow8(hand, nil)
+		if ok && (!found || lv.Better(best)) {
+			best, bestCards, found = lv, cards, true
+		}
+	})
+	return best, bestCards, found
+}
+
+// omahaHands calls fn with every five-card hand of two hole and three board
+// cards. fn must not keep the slice.
+func omahaHands(board, holes []Card, fn func([]Card)) {
+	hand := make([]Card, 5)
+	for i := 0; i < len(holes); i++ {
+		for j := i + 1; j < len(holes); j++ {
+			hand[0], hand[1] = holes[i], holes[j]
+			for a := 0; a < len(board); a++ {
+				for b := a + 1; b < len(board); b++ {
+					for c := b + 1; c < len(board); c++ {
+						hand[2], hand[3], hand[4] = board[a], board[b], board[c]
+						fn(hand)
+					}
+				}
+			}
+		}
+	}
+}
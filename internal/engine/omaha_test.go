@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestOmahaNeedsTwoOfTheSuitInHand(t *testing.T) {
+	board := cards(t, "Ah Kh Qh 7h 2c") // four hearts out there
+	for _, tc := range []struct {
+		holes string
+		cat   Category
+	}{
+		{"Jh 3c 4d 5s", CatHighCard}, // one heart: no flush, and nothing else
+		{"Jh 3h 4d 5s", CatFlush},    // two hearts play with three from the board
+		{"9c 9d 3s 4s", CatOnePair},
+	} {
+		if hv, _ := BestHandOmaha(board, cards(t, tc.holes)); hv.Cat != tc.cat {
+			t.Errorf("%s on %v: %s, want %s", tc.holes, board, hv.Cat, tc.cat)
+		}
+	}
+
+	// dealt four apiece and shown down under Omaha rules, the lone heart loses
+	s := NewState(1, 2)
+	s.Variant = VariantOmaha
+	for _, p := range []PlayerID{"a", "b"} {
+		if err := s.SitStack(p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.StartHand(rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range s.Order {
+		if n := len(s.Holes[p]); n != 4 {
+			t.Fatalf("%s dealt %d hole cards, want 4", p, n)
+		}
+	}
+	if err := s.Call(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	s.Board = board
+	s.Holes = map[PlayerID][]Card{"a": cards(t, "Jh 3c 4d 5s"), "b": cards(t, "9c 9d 3s 4s")}
+	if sum := s.ResolveShowdown(); len(sum.Winners) != 1 || sum.Winners[0].Player != "b" {
+		t.Fatalf("winners %+v, want b's nines over a's unplayable flush", sum.Winners)
+	}
+}
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// TestStartHandDependsOnlyOnSeats builds the same five seats, with the button
+// on d, twice: once joined in seat order and once out of it with Order laid
+// out backwards before it is sorted. Both deal the same hand from one seed.
+func TestStartHandDependsOnlyOnSeats(t *testing.T) {
+	seats := map[PlayerID]int{"d": 0, "b": 1, "e": 2, "a": 3, "c": 4}
+	table := func(join ...PlayerID) *State {
+		s := NewState(1, 2)
+		s.Ante, s.Straddles = 1, 1
+		for _, p := range join {
+			if err := s.SitStack(p, 100); err != nil {
+				t.Fatal(err)
+			}
+			s.Seats[p].Index = seats[p]
+		}
+		slices.Reverse(s.Order)
+		s.sortOrder()
+		s.DealerIdx = slices.Index(s.Order, "d")
+		return &s
+	}
+	x := table("d", "b", "e", "a", "c")
+	y := table("c", "a", "e", "b", "d")
+	if !slices.Equal(x.Order, y.Order) {
+		t.Fatalf("orders %v and %v from the same seats", x.Order, y.Order)
+	}
+
+	for _, s := range []*State{x, y} {
+		if err := s.StartHand(rand.New(rand.NewSource(7))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for p := range seats {
+		a, b := x.Seats[p], y.Seats[p]
+		if a.Committed != b.Committed || a.TotalCommitted != b.TotalCommitted || a.Stack != b.Stack {
+			t.Errorf("%s: posted %d (%d in all, %d left) vs %d (%d, %d)",
+				p, a.Committed, a.TotalCommitted, a.Stack, b.Committed, b.TotalCommitted, b.Stack)
+		}
+		if !slices.Equal(x.Holes[p], y.Holes[p]) {
+			t.Errorf("%s dealt %v vs %v", p, x.Holes[p], y.Holes[p])
+		}
+	}
+	if x.Pot != y.Pot || x.CurrentBet != y.CurrentBet || x.CurrentPlayer() != y.CurrentPlayer() || !slices.Equal(x.Deck, y.Deck) {
+		t.Fatalf("pot %d / %d, bar %d / %d, %s / %s to act, or the decks differ",
+			x.Pot, y.Pot, x.CurrentBet, y.CurrentBet, x.CurrentPlayer(), y.CurrentPlayer())
+	}
+	// the straddle, after the big blind, is the bar
+	if x.CurrentBet != 4 || x.Pot != 5+1+2+4 {
+		t.Fatalf("bar %d and pot %d; want 4 and the antes, blinds and straddle (12)", x.CurrentBet, x.Pot)
+	}
+}
+
+func TestHeadsUpTheButtonIsTheSmallBlind(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	button := s.Order[s.DealerIdx]
+	other := after(s, button, 1)
+	if s.Seats[button].Committed != 1 || s.Seats[other].Committed != 2 {
+		t.Fatalf("button %s posted %d, %s posted %d; want the small and big blind",
+			button, s.Seats[button].Committed, other, s.Seats[other].Committed)
+	}
+
+	// preflop the button acts first and the big blind closes the action
+	if cur := s.CurrentPlayer(); cur != button {
+		t.Fatalf("preflop: %s to act, want the button %s", cur, button)
+	}
+	if err := s.Call(button); err != nil {
+		t.Fatal(err)
+	}
+	if cur := s.CurrentPlayer(); cur != other {
+		t.Fatalf("preflop: %s to act after the call, want the big blind %s", cur, other)
+	}
+	if err := s.Check(other); err != nil {
+		t.Fatal(err)
+	}
+	// after the flop the button acts last
+	for _, phase := range []Phase{PhaseFlop, PhaseTurn, PhaseRiver} {
+		if !s.RoundClosed() {
+			t.Fatalf("%s left open", s.Phase)
+		}
+		s.AdvancePhase()
+		if s.Phase != phase {
+			t.Fatalf("advanced to %s, want %s", s.Phase, phase)
+		}
+		for _, p := range []PlayerID{other, button} {
+			if cur := s.CurrentPlayer(); cur != p {
+				t.Fatalf("%s: %s to act, want %s", phase, cur, p)
+			}
+			if err := s.Check(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if !s.RoundClosed() {
+		t.Fatal("river left open")
+	}
+}
+
+func TestButtonMovesOneSeatEachHandAndWraps(t *testing.T) {
+	s := seated(t, nil, 100, 100, 100, 100)
+	var buttons []PlayerID
+	for hand := 0; hand < 6; hand++ {
+		deal(t, s, int64(hand))
+		buttons = append(buttons, s.Order[s.DealerIdx])
+		foldOut(t, s)
+	}
+	for i := 1; i < len(buttons); i++ {
+		if want := after(s, buttons[i-1], 1); buttons[i] != want {
+			t.Fatalf("buttons %v: hand %d went to %s, want %s", buttons, i, buttons[i], want)
+		}
+	}
+}
+
+func TestAdvanceButtonSkipsSeatsNotDealtIn(t *testing.T) {
+	s := NewState(1, 2)
+	for _, p := range []PlayerID{"a", "b", "c", "d", "e"} {
+		if err := s.SitStack(p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetSittingOut("c", true); err != nil {
+		t.Fatal(err)
+	}
+	s.resetSeats()
+	button := func() PlayerID { return s.Order[s.DealerIdx] }
+
+	s.DealerIdx = 1 // b
+	s.advanceButton()
+	if button() != "d" {
+		t.Fatalf("from b the button went to %s, want d past c, who sits out", button())
+	}
+	s.advanceButton()
+	s.advanceButton()
+	if button() != "a" {
+		t.Fatalf("from e the button went to %s, want it to wrap to a", button())
+	}
+
+	// seats come and go between hands; the button stays with its player
+	if err := s.SitStack("f", 100); err != nil {
+		t.Fatal(err)
+	}
+	s.Leave("b")
+	if button() != "a" {
+		t.Fatalf("a join and a leave moved the button to %s", button())
+	}
+	s.Leave("a") // the button's own seat empties
+	s.resetSeats()
+	s.advanceButton()
+	if button() != "d" {
+		t.Fatalf("after the button left it went to %s, want d, the next seat dealt in", button())
+	}
+}
+
+func TestLateJoinerDoesNotShiftTheButton(t *testing.T) {
+	s := NewState(1, 2)
+	for _, p := range []PlayerID{"b", "c", "d", "e"} {
+		if err := s.SitStack(p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	playHand := func(seed int64) {
+		t.Helper()
+		deal(t, &s, seed)
+		foldOut(t, &s)
+	}
+	playHand(1)
+	button, sbSeat := s.Order[s.DealerIdx], s.Last.SB
+
+	// a sorts first by name but takes the next open seat, after e
+	if err := s.SitStack("a", 100); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(s.Order, []PlayerID{"b", "c", "d", "e", "a"}) || s.Seats["a"].Index != 4 {
+		t.Fatalf("order %v with a in seat %d; want a appended in seat 4", s.Order, s.Seats["a"].Index)
+	}
+	if s.Order[s.DealerIdx] != button {
+		t.Fatalf("a's join moved the button from %s to %s", button, s.Order[s.DealerIdx])
+	}
+	playHand(2)
+	if got := s.Order[s.DealerIdx]; s.Seats[got].Index != sbSeat || s.Last.Button != sbSeat {
+		t.Fatalf("next hand's button is %s in seat %d, want seat %d, last hand's small blind", got, s.Seats[got].Index, sbSeat)
+	}
+}
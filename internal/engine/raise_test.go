@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// raiseSpot deals three-handed at 1/2 and gives the first to act preflop
+// stack chips, facing the big blind.
+func raiseSpot(t *testing.T, stack int64) (*State, PlayerID) {
+	t.Helper()
+	s := dealt(t, nil, 100, 100, 100)
+	p := s.CurrentPlayer()
+	s.Seats[p].Stack = stack
+	return s, p
+}
+
+func TestAllInForExactlyAMinRaiseIsAFullRaise(t *testing.T) {
+	s, p := raiseSpot(t, 4) // call 2, raise 2: all of it
+	if err := s.Raise(p, 2); err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Seats[p]; !st.AllIn || st.Stack != 0 {
+		t.Fatalf("shover not all in: %+v", st)
+	}
+	if s.CurrentBet != 4 || s.LastRaiseSize != 2 || s.ActorsToAct != 2 {
+		t.Fatalf("bar %d, raise size %d, %d to act; want a full raise to 4 that both blinds answer",
+			s.CurrentBet, s.LastRaiseSize, s.ActorsToAct)
+	}
+}
+
+func TestAllInShortOfAMinRaiseDoesNotReopen(t *testing.T) {
+	s, p := raiseSpot(t, 3) // call 2, raise 1: all of it
+	if err := s.Raise(p, 1); err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Seats[p]; !st.AllIn || st.Committed != 3 {
+		t.Fatalf("short shove: %+v", st)
+	}
+	if s.CurrentBet != 2 || s.LastRaiseSize != 2 {
+		t.Fatalf("a short all-in moved the bar to %d (raise size %d)", s.CurrentBet, s.LastRaiseSize)
+	}
+}
+
+func TestRaiseBelowTheMinimumIsRefused(t *testing.T) {
+	s, p := raiseSpot(t, 100)
+	if err := s.Raise(p, 1); !errors.Is(err, ErrBelowMinRaise) {
+		t.Fatalf("raise by 1 over a 2 bet: %v, want ErrBelowMinRaise", err)
+	}
+	if err := s.Raise(p, 2); err != nil {
+		t.Fatalf("raise by exactly the minimum: %v", err)
+	}
+}
+
+func TestAllInBetMarksTheBettorAllIn(t *testing.T) {
+	s, _ := raiseSpot(t, 100)
+	for s.CurrentPlayer() != "" && !s.RoundClosed() {
+		if err := s.Call(s.CurrentPlayer()); err != nil {
+			if err := s.Check(s.CurrentPlayer()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	s.AdvancePhase()
+	q := s.CurrentPlayer()
+	stack := s.Seats[q].Stack
+	if err := s.Bet(q, stack); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Seats[q].AllIn {
+		t.Fatalf("%s bet their whole stack but is not all in", q)
+	}
+}
+
+func TestCallingOffTheStackIsAllInAndSkipped(t *testing.T) {
+	s, p := raiseSpot(t, 2) // calls the big blind with all of it
+	if err := s.Call(p); err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Seats[p]; !st.AllIn || st.Stack != 0 {
+		t.Fatalf("caller: %+v", st)
+	}
+	for !s.RoundClosed() {
+		q := s.CurrentPlayer()
+		if q == p {
+			t.Fatalf("%s asked to act with no chips", p)
+		}
+		if err := s.Call(q); err != nil {
+			if err := s.Check(q); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	s.AdvancePhase()
+	for !s.RoundClosed() {
+		q := s.CurrentPlayer()
+		if q == p {
+			t.Fatalf("%s asked to act on the flop with no chips", p)
+		}
+		if err := s.Check(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFirstPreflopRaiseIsToTwiceTheBigBlind(t *testing.T) {
+	deal := func() *State { return dealt(t, blinds(5, 10), 1000, 1000, 1000) }
+
+	s := deal()
+	p := s.CurrentPlayer()
+	err := s.Raise(p, 9) // to 19
+	if !errors.Is(err, ErrBelowMinRaise) || !strings.Contains(err.Error(), "at least 20") {
+		t.Fatalf("raise to 19 over a 10 big blind: %v, want ErrBelowMinRaise naming 20", err)
+	}
+	if st := s.Seats[p]; st.Committed != 0 || st.Stack != 1000 || s.CurrentBet != 10 || s.CurrentPlayer() != p {
+		t.Fatalf("a refused raise changed the hand: %+v, bar %d", st, s.CurrentBet)
+	}
+
+	s = deal()
+	p = s.CurrentPlayer()
+	if err := s.Raise(p, 10); err != nil { // to 20
+		t.Fatalf("raise to exactly 2×BB: %v", err)
+	}
+	if s.CurrentBet != 20 || s.LastRaiseSize != 10 || s.Seats[p].Committed != 20 {
+		t.Fatalf("bar %d, raise size %d, %s in for %d; want a raise to 20 by 10",
+			s.CurrentBet, s.LastRaiseSize, p, s.Seats[p].Committed)
+	}
+}
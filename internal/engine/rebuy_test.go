@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRebuyIsRefusedMidHand(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	before := s.Seats["a"].Stack
+	if err := s.AddChips("a", 50); !errors.Is(err, ErrHandInProgress) {
+		t.Fatalf("mid-hand rebuy: %v, want ErrHandInProgress", err)
+	}
+	if st := s.Seats["a"]; st.Stack != before || st.TotalBuyin != 100 {
+		t.Fatalf("refused rebuy changed the seat: %+v", st)
+	}
+}
+
+func TestRebuyIsClampedToTheMaxBuyin(t *testing.T) {
+	s := NewState(1, 2)
+	s.MaxBuyin = 200
+	if err := s.SitStack("a", 150); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddChips("a", 100); err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Seats["a"]; st.Stack != 200 || st.TotalBuyin != 200 {
+		t.Fatalf("rebuy of 100 on 150: stack %d, bought in %d; want both 200", st.Stack, st.TotalBuyin)
+	}
+	if err := s.AddChips("a", 1); err == nil {
+		t.Fatal("rebuy accepted at the max buy-in")
+	}
+}
@@ -0,0 +1,74 @@
+package engine
+
+import "errors"
+
+// Showing and mucking. Every hand still in at showdown is evaluated, but only
+// the hands that are tabled are made public. Going round from the last
+// aggressor of the final betting round (or, with no bet on it, the first
+// player left of the button), each player:
+//
+//   - shows if they are first to show, or won any part of a pot: a hand has
+//     to be tabled to claim chips, so a winner cannot muck
+//   - otherwise shows if they chose to (ActShow) and mucks if they chose to
+//     (ActMuck)
+//   - otherwise shows only a hand that beats or ties the best shown so far
+//
+// Choices are made during the hand, since showdown resolves as soon as the
+// last street closes. A player who wins uncontested shows only by choice.
+
+// SetReveal records whether p shows (true) or mucks their hand at showdown.
+func (s *State) SetReveal(p PlayerID, show bool) error {
+	st, ok := s.Seats[p]
+	if !ok {
+		return ErrUnknownPlayer
+	}
+	if !s.HandActive || !st.InHand || st.Folded {
+		return errors.New("not in a hand")
+	}
+	st.Show, st.Muck = show, !show
+	return nil
+}
+
+// showOrder lists the players at showdown in the order they show.
+func (s *State) showOrder() []PlayerID {
+	n := len(s.Order)
+	start := (s.DealerIdx + 1) % max(n, 1)
+	for i, pid := range s.Order {
+		if pid == s.Aggressor {
+			start = i
+		}
+	}
+	var out []PlayerID
+	for k := 0; k < n; k++ {
+		pid := s.Order[(start+k)%n]
+		if st := s.Seats[pid]; st.InHand && !st.Folded {
+			out = append(out, pid)
+		}
+	}
+	return out
+}
+
+// reveals decides who shows (see above) given each showdown hand's value and
+// who won chips, and returns the hole cards of those who did.
+func (s *State) reveals(ev Evaluator, vals map[PlayerID]HandValue, won map[PlayerID]int64) (map[PlayerID]bool, map[PlayerID][]Card) {
+	revealed := make(map[PlayerID]bool)
+	shown := make(map[PlayerID][]Card)
+	var best HandValue
+	for i, pid := range s.showOrder() {
+		st := s.Seats[pid]
+		v := vals[pid]
+		_, winner := won[pid]
+		show := i == 0 || winner || st.Show
+		if !show && !st.Muck {
+			show = !ev.Less(v, best)
+		}
+		revealed[pid] = show
+		if show {
+			shown[pid] = append([]Card{}, s.Holes[pid]...)
+			if i == 0 || ev.Less(best, v) {
+				best = v
+			}
+		}
+	}
+	return revealed, shown
+}
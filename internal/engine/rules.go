@@ -31,6 +31,41 @@ func (s *State) countNeedToAct() int {
 	return need
 }
 
+// OnlyOneInHand reports the last player still holding cards once everyone else
+// has folded (or left); the hand is theirs without a showdown.
+func (s *State) OnlyOneInHand() (PlayerID, bool) {
+	if !s.HandActive {
+		return "", false
+	}
+	var last PlayerID
+	n := 0
+	for _, pid := range s.Order {
+		if st, ok := s.Seats[pid]; ok && st.InHand && !st.Folded {
+			last = pid
+			n++
+		}
+	}
+	return last, n == 1
+}
+
+// NoMoreBetting reports that the rest of the hand can only be dealt out: every
+// player still in is all-in, or one is not but has nobody left to bet against
+// and no bet to call.
+func (s *State) NoMoreBetting() bool {
+	if !s.HandActive {
+		return false
+	}
+	elig := 0
+	var last *Seat
+	for _, pid := range s.Order {
+		if s.eligible(pid) {
+			elig++
+			last = s.Seats[pid]
+		}
+	}
+	return elig == 0 || (elig == 1 && last.Committed >= s.CurrentBet)
+}
+
 // RoundClosed returns true when betting is closed this street.
 // (Authority may auto-advance when this becomes true.)
 func (s *State) RoundClosed() bool {
@@ -38,12 +73,161 @@ func (s *State) RoundClosed() bool {
 		return false
 	}
 	elig := 0
+	var last *Seat
 	for _, pid := range s.Order {
 		if s.eligible(pid) {
 			elig++
+			last = s.Seats[pid]
 		}
 	}
-	return s.ActorsToAct <= 0 || elig <= 1
+	switch {
+	case s.ActorsToAct <= 0 || elig == 0:
+		return true
+	case elig == 1:
+		// everyone else is all-in or folded: only a bet still to call keeps it open
+		return last.Committed >= s.CurrentBet
+	}
+	return false
+}
+
+// Move is a betting decision kind, as reported by LegalActions.
+type Move string
+
+const (
+	MoveFold  Move = "fold"
+	MoveCheck Move = "check"
+	MoveCall  Move = "call"
+	MoveBet   Move = "bet"
+	MoveRaise Move = "raise"
+)
+
+// LegalAction is one option for the player to act. Min/Max are the chip
+// bounds: the call amount for a call, the bet size for a bet, and the
+// "raise-to" total (same meaning as a RAISE action's Amount) for a raise.
+type LegalAction struct {
+	Move Move
+	Min  int64
+	Max  int64
+}
+
+// LegalActions lists what p may do right now; nil unless it is p's turn.
+func (s *State) LegalActions(p PlayerID) []LegalAction {
+	if !s.HandActive || s.CurrentPlayer() != p || !s.eligible(p) {
+		return nil
+	}
+	st := s.Seats[p]
+	need := s.CurrentBet - st.Committed
+	var out []LegalAction
+	if need > 0 {
+		out = append(out, LegalAction{Move: MoveFold})
+		call := min64(need, st.Stack)
+		out = append(out, LegalAction{Move: MoveCall, Min: call, Max: call})
+	} else {
+		out = append(out, LegalAction{Move: MoveCheck})
+	}
+	most, ok := s.maxAdd(max(need, 0))
+	switch {
+	case !ok:
+		// fixed limit: the street is capped
+	case s.CurrentBet == 0:
+		if st.Stack >= s.BigBlind && st.Stack > 0 {
+			hi := min(most, st.Stack)
+			lo := s.BigBlind
+			if s.Structure == FixedLimit {
+				lo = hi
+			}
+			out = append(out, LegalAction{Move: MoveBet, Min: lo, Max: hi})
+		}
+	case st.Stack > need:
+		allIn := st.Committed + st.Stack
+		maxTo := allIn
+		if most < allIn-s.CurrentBet {
+			maxTo = s.CurrentBet + most
+		}
+		minTo := s.CurrentBet + s.LastRaiseSize
+		if s.Structure == FixedLimit {
+			minTo = maxTo
+		}
+		if minTo > maxTo {
+			minTo = maxTo // short all-in raise
+		}
+		out = append(out, LegalAction{Move: MoveRaise, Min: minTo, Max: maxTo})
+	}
+	return out
+}
+
+// ToCall is what p must add to stay in: the gap to the current bet, capped
+// at their stack.
+func (s *State) ToCall(p PlayerID) int64 {
+	st, ok := s.Seats[p]
+	if !ok || st.Committed >= s.CurrentBet {
+		return 0
+	}
+	return min64(s.CurrentBet-st.Committed, st.Stack)
+}
+
+// PotAfter is the pot once p has called — the base for pot-sized bets.
+func (s *State) PotAfter(p PlayerID) int64 { return s.Pot + s.ToCall(p) }
+
+// Bet-sizing preset names, as keys of BetPresets.
+const (
+	PresetMin       = "min"
+	PresetThird     = "1/3"
+	PresetHalf      = "1/2"
+	PresetTwoThirds = "2/3"
+	PresetPot       = "pot"
+	PresetAllIn     = "allin"
+)
+
+// BetPresets returns quick-button sizes for p as "raise-to" totals (the bet
+// size when opening): a fraction of the pot after calling, added on top of
+// the current bet, clamped to [minimum legal, all-in]. Nil when p cannot bet
+// or raise now.
+func (s *State) BetPresets(p PlayerID) map[string]int64 {
+	var la *LegalAction
+	for _, o := range s.LegalActions(p) {
+		if o.Move == MoveBet || o.Move == MoveRaise {
+			o := o
+			la = &o
+		}
+	}
+	if la == nil {
+		return nil
+	}
+	base, pot := s.CurrentBet, s.PotAfter(p)
+	frac := func(num, den int64) int64 {
+		return max(la.Min, min(la.Max, base+pot*num/den))
+	}
+	return map[string]int64{
+		PresetMin:       la.Min,
+		PresetThird:     frac(1, 3),
+		PresetHalf:      frac(1, 2),
+		PresetTwoThirds: frac(2, 3),
+		PresetPot:       frac(1, 1),
+		PresetAllIn:     la.Max,
+	}
+}
+
+// SetAutoCheck toggles p's auto-check (check-down) preference.
+func (s *State) SetAutoCheck(p PlayerID, on bool) error {
+	st, ok := s.Seats[p]
+	if !ok {
+		return ErrUnknownPlayer
+	}
+	st.AutoCheck = on
+	return nil
+}
+
+// AutoCheckDue reports whether the player to act should be checked for
+// automatically: they opted in and there is no bet to face. A bet only
+// overrides the opt-in for the rest of its street: the player decides for
+// themselves until the next street, when no bet is faced again.
+func (s *State) AutoCheckDue() (PlayerID, bool) {
+	pid := s.CurrentPlayer()
+	if !s.HandActive || pid == "" || s.CurrentBet != 0 || !s.eligible(pid) {
+		return "", false
+	}
+	return pid, s.Seats[pid].AutoCheck
 }
 
 // Utility used by raise/call logic.
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"maps"
+	"math/rand"
+	"testing"
+)
+
+func TestBetPresetsOnTheFlop(t *testing.T) {
+	s := NewState(1, 2)
+	for _, p := range []PlayerID{"a", "b", "c"} {
+		if err := s.SitStack(p, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.StartHand(rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+	checkAround(t, &s)
+	s.AdvancePhase() // a pot of 6, everyone on 98
+
+	opener := s.CurrentPlayer()
+	want := map[string]int64{PresetMin: 2, PresetThird: 2, PresetHalf: 3, PresetTwoThirds: 4, PresetPot: 6, PresetAllIn: 98}
+	if got := s.BetPresets(opener); !maps.Equal(got, want) {
+		t.Fatalf("opening presets %v, want %v", got, want)
+	}
+	if err := s.Bet(opener, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	// facing 6 the pot after calling is 18: fractions of it go on top of the 6
+	p := s.CurrentPlayer()
+	want = map[string]int64{PresetMin: 12, PresetThird: 12, PresetHalf: 15, PresetTwoThirds: 18, PresetPot: 24, PresetAllIn: 98}
+	if got := s.BetPresets(p); !maps.Equal(got, want) {
+		t.Fatalf("presets facing a bet %v, want %v", got, want)
+	}
+
+	s.Seats[p].Stack = 20 // a pot-sized raise to 24 would be more than p has
+	want = map[string]int64{PresetMin: 12, PresetThird: 12, PresetHalf: 15, PresetTwoThirds: 18, PresetPot: 20, PresetAllIn: 20}
+	if got := s.BetPresets(p); !maps.Equal(got, want) {
+		t.Fatalf("short-stacked presets %v, want %v", got, want)
+	}
+
+	if got := s.BetPresets(opener); got != nil {
+		t.Fatalf("presets %v for a player not to act", got)
+	}
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPublicSnapshotClearsHolesFromAnEarlierHand(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	var r State
+	r.RestoreFromSnapshot(s.FullSnapshot())
+	if len(r.Holes) != 2 {
+		t.Fatalf("restored holes %v, want both players'", r.Holes)
+	}
+
+	if err := s.Fold(s.CurrentPlayer()); err != nil {
+		t.Fatal(err)
+	}
+	if w, ok := s.OnlyOneInHand(); ok {
+		s.AwardUncontested(w)
+	}
+	if err := s.StartHand(rand.New(rand.NewSource(2))); err != nil {
+		t.Fatal(err)
+	}
+
+	r.RestoreFromSnapshot(s.Snapshot())
+	if len(r.Holes) != 0 {
+		t.Fatalf("public snapshot of the next hand left holes %v behind", r.Holes)
+	}
+	r.RestoreFromSnapshot(s.SnapshotFor("a"))
+	if len(r.Holes) != 1 || !slices.Equal(r.Holes["a"], s.Holes["a"]) {
+		t.Fatalf("a's snapshot restored holes %v, want a's %v only", r.Holes, s.Holes["a"])
+	}
+}
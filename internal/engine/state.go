@@ -1,7 +1,10 @@
 package engine
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"sort"
 )
@@ -11,6 +14,9 @@ var (
 	ErrUnknownPlayer  = errors.New("unknown player")
 	ErrInsufficient   = errors.New("insufficient chips")
 	ErrNotPlayersTurn = errors.New("not player's turn")
+	ErrBelowMinRaise  = errors.New("raise too small (below min-raise)")
+	ErrHandInProgress = errors.New("a hand is in progress")
+	ErrTableFull      = errors.New("table full")
 )
 
 func NewState(sb, bb int64) State {
@@ -29,102 +35,409 @@ func NewState(sb, bb int64) State {
 	}
 }
 
+// BuyinError is Sit refusing a buy-in outside the table's band.
+type BuyinError struct {
+	Buyin, Min, Max int64 // Max 0: no cap
+}
+
+func (e *BuyinError) Error() string {
+	if e.Buyin < e.Min {
+		return fmt.Sprintf("buy-in %d is below the minimum (%d)", e.Buyin, e.Min)
+	}
+	return fmt.Sprintf("buy-in %d is above the maximum (%d)", e.Buyin, e.Max)
+}
+
+// Sit seats p with a buy-in of buyin, which must lie within MinBuyin and
+// (when set) MaxBuyin; outside it Sit returns a *BuyinError.
 func (s *State) Sit(p PlayerID, buyin int64) error {
+	if buyin < s.MinBuyin || (s.MaxBuyin > 0 && buyin > s.MaxBuyin) {
+		return &BuyinError{Buyin: buyin, Min: s.MinBuyin, Max: s.MaxBuyin}
+	}
+	return s.SitStack(p, buyin)
+}
+
+// SitStack seats p with a stack they already own, such as one moved from
+// another table, so the buy-in band does not apply. Both refuse a player
+// beyond MaxSeats with ErrTableFull.
+func (s *State) SitStack(p PlayerID, stack int64) error {
 	if _, ok := s.Seats[p]; ok {
 		return ErrAlreadySeated
 	}
-	s.Seats[p] = &Seat{Player: p, Stack: buyin, InHand: false}
+	if s.MaxSeats > 0 && len(s.Order) >= s.MaxSeats {
+		return ErrTableFull
+	}
+	s.Seats[p] = &Seat{Player: p, Index: s.freeSeat(), Stack: stack, InHand: false, TotalBuyin: stack}
+	var button PlayerID
+	if s.DealerIdx < len(s.Order) {
+		button = s.Order[s.DealerIdx]
+	}
 	s.Order = append(s.Order, p)
 	s.sortOrder()
+	// the button stays with its player, wherever the new seat is
+	for i, id := range s.Order {
+		if id == button {
+			s.DealerIdx = i
+		}
+	}
+	return nil
+}
+
+// SetSittingOut sits p out of (or back into) future hands. A hand p is
+// already playing is unaffected.
+func (s *State) SetSittingOut(p PlayerID, out bool) error {
+	st, ok := s.Seats[p]
+	if !ok {
+		return ErrUnknownPlayer
+	}
+	st.SittingOut = out
+	return nil
+}
+
+// AddChips is a rebuy or top-up: it adds amt to p's stack and to what they
+// have bought in. Stacks only change between hands, so it fails while one is
+// being played. With MaxBuyin set, the stack is topped up to MaxBuyin at most
+// and anything over is not added; a stack already there is refused.
+func (s *State) AddChips(p PlayerID, amt int64) error {
+	st, ok := s.Seats[p]
+	if !ok {
+		return ErrUnknownPlayer
+	}
+	if amt <= 0 {
+		return errors.New("rebuy amount must be positive")
+	}
+	if s.HandActive {
+		return ErrHandInProgress
+	}
+	if s.MaxBuyin > 0 && st.Stack+amt > s.MaxBuyin {
+		amt = s.MaxBuyin - st.Stack
+		if amt <= 0 {
+			return fmt.Errorf("stack %d is already at the max buy-in (%d)", st.Stack, s.MaxBuyin)
+		}
+	}
+	st.Stack += amt
+	st.TotalBuyin += amt
 	return nil
 }
 
 func (s *State) Leave(p PlayerID) {
 	delete(s.Seats, p)
 	delete(s.Holes, p)
+	delete(s.Upcards, p)
 	// remove from order
 	out := s.Order[:0]
-	for _, id := range s.Order {
+	for i, id := range s.Order {
 		if id != p {
 			out = append(out, id)
+		} else if i <= s.DealerIdx {
+			// Keep the button where it was: on the same player, or on the
+			// seat before a vacated button so the next hand's button is
+			// the player who sat after it.
+			s.DealerIdx--
 		}
 	}
 	s.Order = out
+	if s.DealerIdx < 0 {
+		s.DealerIdx = len(s.Order) - 1
+	}
+	if s.DealerIdx < 0 || s.DealerIdx >= len(s.Order) {
+		s.DealerIdx = 0
+	}
 	if s.TurnIdx >= len(s.Order) {
 		s.TurnIdx = 0
 	}
 }
 
+// freeSeat is the lowest seat number no one is sitting in: a joiner takes the
+// first open seat and everyone else keeps theirs.
+func (s *State) freeSeat() int {
+	taken := make(map[int]bool, len(s.Seats))
+	for _, st := range s.Seats {
+		taken[st.Index] = true
+	}
+	i := 0
+	for taken[i] {
+		i++
+	}
+	return i
+}
+
+// sortOrder lays Order out by seat number, the order play goes round in.
 func (s *State) sortOrder() {
-	sort.Slice(s.Order, func(i, j int) bool { return s.Order[i] < s.Order[j] })
+	sort.Slice(s.Order, func(i, j int) bool { return s.Seats[s.Order[i]].Index < s.Seats[s.Order[j]].Index })
 }
 
-// StartHand deals new hand, posts blinds, sets turn to UTG (after BB)
+// StartHand deals a new hand. Every node must reach identical contributions
+// and cards from the same seed, so the sequence is fixed:
+//
+//  1. reset per-hand seat state (only seats with chips are dealt in) and
+//     move the button and blinds under the dead-button rule (blinds.go)
+//  2. antes: Ante from every seat dealt in, as dead money
+//  3. post the small blind unless it is dead, then the big blind; heads-up
+//     the button posts the small blind and the other player the big blind
+//  4. straddles: Straddles seats from UTG outward, each posting double the
+//     last, then the button if ButtonStraddle (double again)
+//  5. shuffle, then deal two hole cards to each player starting at the small
+//     blind (the big blind when the small blind is dead)
+//     (both at once, or one per pass over two passes when RoundRobin is set)
+//  6. set the bet bar to the biggest post and hand the turn to the seat after
+//     the last straddler (UTG when none); with a button straddle action
+//     starts at the small blind so the button acts last
+//
+// Positions are always derived from Order (seats in seat-number order),
+// DealerIdx and Last, never from map iteration.
 func (s *State) StartHand(r *rand.Rand) error {
-	if len(s.Order) < 2 {
-		return errors.New("need at least 2 players")
+	if min := s.minPlayers(); s.funded() < min {
+		return fmt.Errorf("need at least %d players with chips", min)
 	}
-	// reset board/pot/committed
-	s.Pot = 0
-	for _, seat := range s.Seats {
-		seat.Committed = 0
-		seat.InHand = true
-		seat.Folded = false
-		seat.AllIn = false
+	// check the whole hand's card budget now rather than run dry mid-hand
+	if need := CardsNeeded(s.Variant, s.funded(), s.BurnCards); need > DeckSize(s.Variant) {
+		return fmt.Errorf("deck too small: %d players need %d cards", s.funded(), need)
+	}
+	if s.Variant == VariantStud {
+		return s.startStud(r)
 	}
+	// 1) reset board/pot/committed, rotate dealer
+	s.Pot = 0
+	s.resetSeats()
 	s.HandActive = true
-	// rotate dealer
-	s.DealerIdx = (s.DealerIdx + 1) % len(s.Order)
-	// post blinds (SB = next, BB = next)
-	sbIdx := (s.DealerIdx + 1) % len(s.Order)
-	bbIdx := (s.DealerIdx + 2) % len(s.Order)
-	s.postBlind(s.Order[sbIdx], s.SmallBlind)
+	if s.SitOutBlinds {
+		// sitting-out players keep their place in the blind rotation
+		for _, st := range s.Seats {
+			if st.SittingOut && st.Stack > 0 {
+				st.InHand = true
+			}
+		}
+	}
+	sbIdx, bbIdx := s.placeBlinds()
+
+	// 2) antes
+	if s.Ante > 0 {
+		for _, pid := range s.Order {
+			if st := s.Seats[pid]; st.InHand {
+				s.postAnte(st)
+			}
+		}
+	}
+
+	// 3) post blinds (no small blind when it is dead)
+	if sbIdx >= 0 {
+		s.postBlind(s.Order[sbIdx], s.SmallBlind)
+	}
 	s.postBlind(s.Order[bbIdx], s.BigBlind)
-	// set turn to UTG (after BB)
-	s.TurnIdx = (bbIdx + 1) % len(s.Order)
-	s.Phase = PhasePreflop
-	// set round state
-	s.CurrentBet = s.BigBlind
-	s.LastRaiseSize = s.BigBlind
-	s.ActorsToAct = s.countNeedToAct()
-	// shuffle new deck
-	s.Deck = NewDeck(r)
+	s.foldSittingOut()
+
+	// 4) straddles
+	bar, first := s.postStraddles(sbIdx, bbIdx)
+
+	// 5) shuffle new deck, deal hole cards (2 per active player, from the SB)
+	s.Deck = NewDeckFor(s.Variant, r)
 	s.Board = s.Board[:0]
-	// clear + deal hole cards (2 per active player, in seat order)
-	s.Holes = make(map[PlayerID][]Card, len(s.Seats))
+	if sbIdx < 0 {
+		sbIdx = bbIdx
+	}
+	if err := s.dealHoles(sbIdx); err != nil {
+		return err
+	}
+
+	// 6) round state; turn to UTG (after BB and any straddles)
+	s.Phase = PhasePreflop
+	s.CurrentBet = bar
+	s.LastRaiseSize = bar // the biggest post plays as the big blind: the first raise is to at least 2×bar
+	s.Raises = 1          // ...and as the street's opening bet
+	// Posting is not acting: every live player acts at least once preflop,
+	// which is what gives the big blind its option when everyone just calls.
+	// Blinds all-in from the post are not eligible and are skipped.
+	s.ActorsToAct = 0
 	for _, pid := range s.Order {
-		st := s.Seats[pid]
-		if st.InHand && !st.Folded {
-			if len(s.Deck) < 2 {
+		if s.eligible(pid) {
+			s.ActorsToAct++
+		}
+	}
+	s.TurnIdx = first
+	if !s.eligible(s.Order[s.TurnIdx]) {
+		s.advanceTurn()
+	}
+	return nil
+}
+
+// foldSittingOut takes sitting-out players back out of a hand they were only
+// in to be given the blinds (SitOutBlinds): a blind they posted stays in
+// the pot as dead money and they are folded; the rest were never dealt in.
+func (s *State) foldSittingOut() {
+	for _, st := range s.Seats {
+		if !st.SittingOut || !st.InHand {
+			continue
+		}
+		st.Folded = st.TotalCommitted > 0
+		st.InHand = false
+	}
+}
+
+// postStraddles posts the configured straddles and returns the resulting bet
+// bar and the index of the first seat to act preflop. UTG straddles never
+// reach the button (that is what ButtonStraddle is for), and a button straddle
+// needs a live button distinct from both blinds, and a small blind to act
+// first. A straddler short of the full amount goes all in, and the bar is only
+// what they could post.
+func (s *State) postStraddles(sbIdx, bbIdx int) (bar int64, first int) {
+	bar, first = s.BigBlind, s.nextDealtIn(bbIdx)
+	dealt := s.dealtIn()
+	idx := bbIdx
+	for k := 0; k < s.Straddles && k < dealt-3; k++ {
+		idx = s.nextDealtIn(idx)
+		bar = max(bar, s.postBlind(s.Order[idx], 2*bar))
+		first = s.nextDealtIn(idx)
+	}
+	if s.ButtonStraddle && dealt >= 3 && sbIdx >= 0 && !s.DeadButton && s.Seats[s.Order[s.DealerIdx]].InHand {
+		bar = max(bar, s.postBlind(s.Order[s.DealerIdx], 2*bar))
+		first = sbIdx
+	}
+	return bar, first
+}
+
+// dealHoles gives two hole cards to every player in the hand, starting at
+// seat first. By default each player takes two consecutive cards; with
+// RoundRobin one card goes to each player per pass, as dealt by hand.
+func (s *State) dealHoles(first int) error {
+	n := len(s.Order)
+	s.Holes = make(map[PlayerID][]Card, len(s.Seats))
+	per, passes := holeCards(s.Variant), 1
+	if s.RoundRobin {
+		per, passes = 1, per
+	}
+	for pass := 0; pass < passes; pass++ {
+		for i := 0; i < n; i++ {
+			pid := s.Order[(first+i)%n]
+			st := s.Seats[pid]
+			if !st.InHand || st.Folded {
+				continue
+			}
+			if len(s.Deck) < per {
 				return errors.New("deck underflow dealing holes")
 			}
-			s.Holes[pid] = []Card{s.Deck[0], s.Deck[1]}
-			s.Deck = s.Deck[2:]
+			s.Holes[pid] = append(s.Holes[pid], s.Deck[:per]...)
+			s.Deck = s.Deck[per:]
 		}
 	}
 	return nil
 }
 
-func (s *State) postBlind(p PlayerID, amt int64) {
+// resetSeats clears per-hand seat state; seats without chips sit the hand out.
+func (s *State) resetSeats() {
+	for _, seat := range s.Seats {
+		seat.Committed = 0
+		seat.TotalCommitted = 0
+		seat.InHand = seat.Stack > 0 && !seat.SittingOut
+		seat.Folded = false
+		seat.AllIn = false
+		seat.Show, seat.Muck = false, false
+	}
+	s.Aggressor = ""
+}
+
+// nextDealtIn returns the index of the next seat after i that is in the hand.
+func (s *State) nextDealtIn(i int) int {
+	n := len(s.Order)
+	for k := 1; k <= n; k++ {
+		if idx := (i + k) % n; s.Seats[s.Order[idx]].InHand {
+			return idx
+		}
+	}
+	return (i + 1) % n
+}
+
+// advanceButton moves the button to the next seat dealt into the hand
+// (resetSeats must have run), wrapping around the table and skipping empty
+// and sitting-out seats. Sit and Leave keep DealerIdx on the same player, so
+// the button never stays put while two or more players are dealt in.
+func (s *State) advanceButton() {
+	s.DealerIdx = s.nextDealtIn(s.DealerIdx)
+}
+
+// dealtIn counts the players dealt into the current hand.
+func (s *State) dealtIn() int {
+	n := 0
+	for _, st := range s.Seats {
+		if st.InHand || st.Folded {
+			n++
+		}
+	}
+	return n
+}
+
+// funded counts seated players who will be dealt in: chips, and not sitting out.
+func (s *State) funded() int {
+	n := 0
+	for _, st := range s.Seats {
+		if st.Stack > 0 && !st.SittingOut {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *State) minPlayers() int {
+	if s.MinPlayers < 2 {
+		return 2
+	}
+	return s.MinPlayers
+}
+
+// CanStart reports whether a new hand may be dealt now: none in progress and
+// enough players seated with chips.
+func (s *State) CanStart() bool {
+	return !s.HandActive && s.funded() >= s.minPlayers()
+}
+
+// postBlind posts amt for p, or all p has left, and returns what was posted.
+func (s *State) postBlind(p PlayerID, amt int64) int64 {
 	seat := s.Seats[p]
 	if seat.Stack <= 0 {
 		seat.AllIn = true
-		return
+		return 0
 	}
 	pay := amt
 	if seat.Stack < amt {
 		pay = seat.Stack
 		seat.AllIn = true
 	}
-	seat.Stack -= pay
-	seat.Committed += pay
+	s.putIn(seat, pay)
+	return pay
+}
+
+// postAnte takes the ante from st: into the pot and the hand's total, but not
+// this street's bet, so it never counts towards calling.
+func (s *State) postAnte(st *Seat) {
+	pay := min(s.Ante, st.Stack)
+	st.Stack -= pay
+	st.TotalCommitted += pay
 	s.Pot += pay
+	if st.Stack == 0 {
+		st.AllIn = true
+	}
+}
+
+// putIn moves amt from a seat's stack into the pot, tracking it both for the
+// current street (Committed) and for the whole hand (TotalCommitted).
+func (s *State) putIn(st *Seat, amt int64) {
+	st.Stack -= amt
+	st.Committed += amt
+	st.TotalCommitted += amt
+	s.Pot += amt
+	if st.Stack == 0 {
+		st.AllIn = true // whether it was a call, a bet or a full raise
+	}
 }
 
 func (s *State) AdvancePhase() {
+	if s.Variant == VariantStud {
+		s.advanceStud()
+		return
+	}
 	switch s.Phase {
 	case PhasePreflop:
 		// deal 3 board cards
+		s.burn()
 		if len(s.Deck) >= 3 {
 			s.Board = append(s.Board, s.Deck[:3]...)
 			s.Deck = s.Deck[3:]
@@ -132,6 +445,7 @@ func (s *State) AdvancePhase() {
 		s.resetCommittedAndSetTurnFromDealer()
 		s.Phase = PhaseFlop
 	case PhaseFlop:
+		s.burn()
 		if len(s.Deck) >= 1 {
 			s.Board = append(s.Board, s.Deck[0])
 			s.Deck = s.Deck[1:]
@@ -139,6 +453,7 @@ func (s *State) AdvancePhase() {
 		s.resetCommittedAndSetTurnFromDealer()
 		s.Phase = PhaseTurn
 	case PhaseTurn:
+		s.burn()
 		if len(s.Deck) >= 1 {
 			s.Board = append(s.Board, s.Deck[0])
 			s.Deck = s.Deck[1:]
@@ -151,6 +466,39 @@ func (s *State) AdvancePhase() {
 	}
 }
 
+// RabbitHunt returns the community cards that would have completed the board,
+// taken from the leftover deck exactly as AdvancePhase would have dealt them.
+// It does not touch the deck, board or results. Hold'em only, between hands,
+// and only while this hand's deck is still known (a snapshot clears it).
+func (s *State) RabbitHunt() ([]Card, error) {
+	if s.HandActive {
+		return nil, errors.New("rabbit hunt: hand still in progress")
+	}
+	if s.Variant == VariantStud {
+		return nil, errors.New("rabbit hunt: no community cards in stud")
+	}
+	if len(s.Board) >= 5 {
+		return nil, errors.New("rabbit hunt: the board was complete")
+	}
+	// replay the remaining streets (flop 3, turn 1, river 1) with their burns
+	var out []Card
+	deck := s.Deck
+	for dealt, street := 0, 3; dealt < 5; dealt, street = dealt+street, 1 {
+		if dealt < len(s.Board) {
+			continue
+		}
+		if s.BurnCards {
+			deck = deck[min(1, len(deck)):]
+		}
+		if len(deck) < street {
+			return nil, errors.New("rabbit hunt: deck not available")
+		}
+		out = append(out, deck[:street]...)
+		deck = deck[street:]
+	}
+	return out, nil
+}
+
 func (s *State) resetCommittedAndSetTurnFromDealer() {
 	for _, seat := range s.Seats {
 		seat.Committed = 0
@@ -166,7 +514,12 @@ func (s *State) resetCommittedAndSetTurnFromDealer() {
 	s.TurnIdx = first
 	s.CurrentBet = 0
 	s.LastRaiseSize = s.BigBlind
+	s.Raises = 0
+	s.Aggressor = ""
 	s.ActorsToAct = s.countNeedToAct()
+	if !s.eligible(s.Order[first]) {
+		s.advanceTurn()
+	}
 }
 
 // CurrentPlayer returns the PlayerID whose turn it is, or "" if none.
@@ -177,14 +530,65 @@ func (s *State) CurrentPlayer() PlayerID {
 	return s.Order[s.TurnIdx]
 }
 
-// Dealer returns the dealer's PlayerID, or "" if none.
+// Dealer returns the dealer's PlayerID, or "" if none or the button is dead.
 func (s *State) Dealer() PlayerID {
-	if len(s.Order) == 0 {
+	if len(s.Order) == 0 || s.DeadButton {
 		return ""
 	}
 	return s.Order[s.DealerIdx]
 }
 
+// TotalChips returns every chip on the table: all stacks plus the pot.
+// Committed amounts are already part of Pot, so they are not added again.
+func (s *State) TotalChips() int64 {
+	total := s.Pot
+	for _, st := range s.Seats {
+		total += st.Stack
+	}
+	return total
+}
+
+// Hash fingerprints the public state every replica shares — seats in Order,
+// pot, bet, board, phase, button and turn — so a follower can tell it has
+// drifted from the authority. Hole cards and the deck are left out: only
+// the authority knows them all.
+func (s *State) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	num := func(v int64) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		_, _ = h.Write(buf[:])
+	}
+	flag := func(b bool) {
+		if b {
+			num(1)
+		} else {
+			num(0)
+		}
+	}
+	for _, pid := range s.Order {
+		_, _ = h.Write([]byte(pid))
+		num(0) // separator: ids are variable length
+		if st, ok := s.Seats[pid]; ok {
+			num(st.Stack)
+			num(st.Committed)
+			flag(st.InHand)
+			flag(st.Folded)
+			flag(st.AllIn)
+		}
+	}
+	num(s.Pot)
+	num(s.CurrentBet)
+	for _, c := range s.Board {
+		num(int64(c.Rank)<<8 | int64(c.Suit))
+	}
+	num(int64(s.Phase))
+	num(int64(s.DealerIdx))
+	num(int64(s.TurnIdx))
+	flag(s.HandActive)
+	return h.Sum64()
+}
+
 // SeatView is a read-only view for UIs/CLIs.
 type SeatView struct {
 	Player    PlayerID
@@ -193,6 +597,28 @@ type SeatView struct {
 	InHand    bool
 	AllIn     bool
 	Folded    bool
+
+	TotalCommitted int64 // invested this hand, across all streets
+}
+
+// Result is one seated player's session result.
+type Result struct {
+	Player PlayerID
+	Buyin  int64 // initial buy-in plus rebuys
+	Stack  int64
+	Net    int64 // Stack - Buyin: profit (or loss, when negative)
+}
+
+// Results reports each seated player's net result, in seat order. Chips still
+// in the pot of a running hand are not counted.
+func (s *State) Results() []Result {
+	out := make([]Result, 0, len(s.Order))
+	for _, pid := range s.Order {
+		if st, ok := s.Seats[pid]; ok {
+			out = append(out, Result{Player: pid, Buyin: st.TotalBuyin, Stack: st.Stack, Net: st.Stack - st.TotalBuyin})
+		}
+	}
+	return out
 }
 
 // Summary is a compact snapshot of user-facing state.
@@ -217,6 +643,8 @@ func (s *State) Summary() Summary {
 				InHand:    seat.InHand,
 				AllIn:     seat.AllIn,
 				Folded:    seat.Folded,
+
+				TotalCommitted: seat.TotalCommitted,
 			})
 		} else {
 			// Seat was removed but still in Order (shouldn't happen, but be safe)
@@ -261,13 +689,16 @@ func (s *State) Bet(p PlayerID, amt int64) error {
 	if st.Stack < amt {
 		return ErrInsufficient
 	}
+	if err := s.checkLimit(amt, 0, amt == st.Stack); err != nil {
+		return err
+	}
 
-	st.Stack -= amt
-	st.Committed += amt
-	s.Pot += amt
+	s.putIn(st, amt)
 
 	s.CurrentBet = st.Committed
 	s.LastRaiseSize = amt
+	s.Raises++
+	s.Aggressor = p
 	s.ActorsToAct = s.countNeedToAct()
 	s.advanceTurn()
 	return nil
@@ -304,9 +735,8 @@ func (s *State) Fold(p PlayerID) error {
 	if err := s.ensureTurn(p); err != nil {
 		return err
 	}
-	if s.CurrentBet > 0 && st.Committed < s.CurrentBet {
-		s.ActorsToAct-- // one fewer to call
-	}
+	// Only players still owed a decision get the turn, so every fold settles one.
+	s.ActorsToAct--
 	st.Folded = true
 	st.InHand = false
 	s.advanceTurn()
@@ -330,11 +760,12 @@ func (s *State) Call(p PlayerID) error {
 		return errors.New("already matched")
 	}
 
-	// Full call
+	// Full call (calling off the whole stack leaves the player all-in)
 	if st.Stack >= need {
-		st.Stack -= need
-		st.Committed += need
-		s.Pot += need
+		s.putIn(st, need)
+		if st.Stack == 0 {
+			st.AllIn = true
+		}
 		s.ActorsToAct-- // this actor has acted
 		s.advanceTurn()
 		return nil
@@ -346,10 +777,8 @@ func (s *State) Call(p PlayerID) error {
 	if allin <= 0 {
 		return ErrInsufficient
 	}
-	st.Stack = 0
+	s.putIn(st, allin)
 	st.AllIn = true
-	st.Committed += allin
-	s.Pot += allin
 
 	s.ActorsToAct-- // they acted this street
 	s.advanceTurn()
@@ -377,39 +806,40 @@ func (s *State) Raise(p PlayerID, add int64) error {
 		need = s.CurrentBet - st.Committed
 	}
 	total := need + add
+	if err := s.checkLimit(add, need, st.Stack <= total); err != nil {
+		return err
+	}
 
-	// FULL RAISE path: meets min-raise and player can cover
+	// FULL RAISE path: meets min-raise and player can cover (an all-in for
+	// exactly a min-raise or more is a full raise and reopens the action)
 	if add >= s.LastRaiseSize && st.Stack >= total {
 		// pay call part (if behind)
 		if need > 0 {
-			st.Stack -= need
-			st.Committed += need
-			s.Pot += need
+			s.putIn(st, need)
 		}
 		// pay raise part
-		st.Stack -= add
-		st.Committed += add
-		s.Pot += add
+		s.putIn(st, add)
 
-		s.CurrentBet = st.Committed        // new bar
-		s.LastRaiseSize = add              // min-raise updates
+		s.CurrentBet = st.Committed // new bar
+		s.LastRaiseSize = add       // min-raise updates
+		s.Raises++
+		s.Aggressor = p
 		s.ActorsToAct = s.countNeedToAct() // everyone else must respond
 		s.advanceTurn()
 		return nil
 	}
 
 	// SHORT ALL-IN raise path:
-	// - allow if it's exactly all-in (stack < total), even if add < LastRaiseSize
+	// - allow if it's all-in, even if add < LastRaiseSize: more than they have
+	//   (stack < total), or all they have but short of a min-raise
 	// - does NOT reopen action:
 	//     • do NOT change CurrentBet or LastRaiseSize
 	//     • only this actor is removed from "to act" (if they were behind)
-	if st.Stack < total {
+	if st.Stack < total || (st.Stack == total && add < s.LastRaiseSize) {
 		// call what you can up to CurrentBet first
 		callPart := min64(st.Stack, need)
 		if callPart > 0 {
-			st.Stack -= callPart
-			st.Committed += callPart
-			s.Pot += callPart
+			s.putIn(st, callPart)
 		}
 		// whatever remains is the raise-by portion (below min-raise), shove it
 		remain := st.Stack
@@ -418,25 +848,21 @@ func (s *State) Raise(p PlayerID, add int64) error {
 			// but keep safety:
 			return ErrInsufficient
 		}
-		st.Stack = 0
+		s.putIn(st, remain)
 		st.AllIn = true
-		st.Committed += remain
-		s.Pot += remain
+		s.Aggressor = p
 
-		// This actor has acted this street. We DO NOT reset ActorsToAct,
+		// This actor has acted this street (they only had the turn because they
+		// were owed a decision). We DO NOT reset ActorsToAct,
 		// we DO NOT change CurrentBet/LastRaiseSize (no reopen).
-		if st.Committed-remain < s.CurrentBet { // were they behind before?
-			s.ActorsToAct--
-		} else if need > 0 { // conservative decrement if they were behind
-			s.ActorsToAct--
-		}
+		s.ActorsToAct--
 		s.advanceTurn()
 		return nil
 	}
 
 	// Not all-in and below min-raise -> reject
 	if add < s.LastRaiseSize {
-		return errors.New("raise too small (below min-raise)")
+		return fmt.Errorf("%w: raise to at least %d", ErrBelowMinRaise, s.CurrentBet+s.LastRaiseSize)
 	}
 
 	// Reaching here means st.Stack >= total but we didn't hit full-raise clause,
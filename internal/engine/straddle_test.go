@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"testing"
+)
+
+// straddleTable deals a hand at a 1/2 table of players with these stacks,
+// seated in order as p0, p1, ...
+func straddleTable(t *testing.T, straddles int, button bool, stacks ...int64) *State {
+	t.Helper()
+	return dealt(t, func(s *State) { s.Straddles, s.ButtonStraddle = straddles, button }, stacks...)
+}
+
+// posted returns who posted amt preflop.
+func posted(t *testing.T, s *State, amt int64) PlayerID {
+	t.Helper()
+	for _, p := range s.Order {
+		if s.Seats[p].Committed == amt {
+			return p
+		}
+	}
+	t.Fatalf("nobody posted %d: %+v", amt, s.Seats)
+	return ""
+}
+
+// after returns the player k seats after p.
+func after(s *State, p PlayerID, k int) PlayerID {
+	for i, id := range s.Order {
+		if id == p {
+			return s.Order[(i+k)%len(s.Order)]
+		}
+	}
+	return ""
+}
+
+// callAround calls for whoever is to act until want is, and fails if the
+// street ends first.
+func callAround(t *testing.T, s *State, want PlayerID) {
+	t.Helper()
+	for i := 0; s.CurrentPlayer() != want; i++ {
+		if i > len(s.Order) || s.RoundClosed() {
+			t.Fatalf("action never reached %s", want)
+		}
+		if err := s.Call(s.CurrentPlayer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDoubleStraddleActsAfterTheSecondStraddler(t *testing.T) {
+	s := straddleTable(t, 2, false, 100, 100, 100, 100, 100, 100)
+	bb, utg, utg1 := posted(t, s, 2), posted(t, s, 4), posted(t, s, 8)
+	if after(s, bb, 1) != utg || after(s, utg, 1) != utg1 {
+		t.Fatalf("straddles not posted from UTG outward: bb %s, %s, %s", bb, utg, utg1)
+	}
+	if s.CurrentBet != 8 || s.LastRaiseSize != 8 {
+		t.Fatalf("bar %d (raise size %d), want 8", s.CurrentBet, s.LastRaiseSize)
+	}
+	if first := after(s, utg1, 1); s.CurrentPlayer() != first {
+		t.Fatalf("%s to act first, want %s", s.CurrentPlayer(), first)
+	}
+	// the last straddler closes the action, with the option to raise
+	callAround(t, s, utg1)
+	if err := s.Check(utg1); err != nil {
+		t.Fatal(err)
+	}
+	if !s.RoundClosed() {
+		t.Fatalf("preflop went on past the last straddler's option, to %s", s.CurrentPlayer())
+	}
+}
+
+func TestButtonStraddleActsLastPreflop(t *testing.T) {
+	s := straddleTable(t, 0, true, 100, 100, 100, 100)
+	sb, btn := posted(t, s, 1), posted(t, s, 4)
+	if s.Order[s.DealerIdx] != btn {
+		t.Fatalf("%s straddled, but %s has the button", btn, s.Order[s.DealerIdx])
+	}
+	if s.CurrentBet != 4 || s.LastRaiseSize != 4 {
+		t.Fatalf("bar %d (raise size %d), want 4", s.CurrentBet, s.LastRaiseSize)
+	}
+	if s.CurrentPlayer() != sb {
+		t.Fatalf("%s to act first, want the small blind %s", s.CurrentPlayer(), sb)
+	}
+	callAround(t, s, btn)
+	if err := s.Check(btn); err != nil {
+		t.Fatal(err)
+	}
+	if !s.RoundClosed() {
+		t.Fatalf("preflop went on past the button's option, to %s", s.CurrentPlayer())
+	}
+}
+
+func TestShortStraddleSetsTheBarAtWhatItPosted(t *testing.T) {
+	s := straddleTable(t, 2, false, 100, 100, 100, 100, 3, 100)
+	short := s.Order[4]
+	if st := s.Seats[short]; !st.AllIn || st.Committed != 3 {
+		t.Fatalf("short straddler %s: %+v", short, st)
+	}
+	// e posted 3 as UTG: the next straddle doubles that, not the missing 4
+	if s.CurrentBet != 6 || s.LastRaiseSize != 6 || posted(t, s, 6) != after(s, short, 1) {
+		t.Fatalf("bar %d (raise size %d), want 6 after a short straddle", s.CurrentBet, s.LastRaiseSize)
+	}
+
+	s = straddleTable(t, 1, false, 100, 100, 100, 100, 3, 100)
+	if s.CurrentBet != 3 || s.LastRaiseSize != 3 {
+		t.Fatalf("bar %d (raise size %d), want the 3 the straddler had", s.CurrentBet, s.LastRaiseSize)
+	}
+	if err := s.Call(s.CurrentPlayer()); err != nil || s.Seats[s.Order[5]].Committed != 3 {
+		t.Fatalf("calling the short straddle: %v, %+v", err, s.Seats[s.Order[5]])
+	}
+}
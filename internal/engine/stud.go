@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Seven-card stud skeleton.
+//
+// There is no shared board: every player receives their own cards across five
+// streets, and Holes holds all of them (down and up). Upcards mirrors the
+// face-up subset, which is public information. Streets map onto phases as:
+//
+//	PhasePreflop → 3rd street (2 down, 1 up; lowest upcard posts the bring-in)
+//	PhaseFlop    → 4th street (1 up)
+//	PhaseTurn    → 5th street (1 up)
+//	PhaseRiver   → 6th street (1 up)
+//	PhaseSeventh → 7th street (1 down)
+//
+// The bring-in is SmallBlind; there are no antes or completions yet. From 4th
+// street on, the best hand showing acts first.
+
+func (s *State) startStud(r *rand.Rand) error {
+	s.Pot = 0
+	s.resetSeats()
+	s.HandActive = true
+	s.advanceButton()
+	s.Phase = PhasePreflop
+	s.Deck = NewDeck(r)
+	s.Board = s.Board[:0]
+	s.Holes = make(map[PlayerID][]Card, len(s.Seats))
+	s.Upcards = make(map[PlayerID][]Card, len(s.Seats))
+
+	// 3rd street: two down, one up, dealt one at a time starting left of the dealer
+	s.burn()
+	s.studDeal(false)
+	s.studDeal(false)
+	s.studDeal(true)
+
+	// bring-in: lowest upcard (suit breaks ties, clubs lowest)
+	bring := s.studBringIn()
+	s.postBlind(s.Order[bring], s.SmallBlind)
+	s.CurrentBet = s.SmallBlind
+	s.LastRaiseSize = s.SmallBlind
+	s.Raises = 0 // the bring-in is not a bet
+	s.ActorsToAct = s.countNeedToAct()
+	s.TurnIdx = bring
+	s.advanceTurn()
+	return nil
+}
+
+// advanceStud deals the next stud street and hands action to the best showing hand.
+func (s *State) advanceStud() {
+	switch s.Phase {
+	case PhasePreflop:
+		s.Phase = PhaseFlop
+	case PhaseFlop:
+		s.Phase = PhaseTurn
+	case PhaseTurn:
+		s.Phase = PhaseRiver
+	case PhaseRiver:
+		s.Phase = PhaseSeventh
+	case PhaseSeventh:
+		s.Phase = PhaseShowdown
+		s.HandActive = false
+		return
+	default:
+		return
+	}
+	s.burn()
+	s.studDeal(s.Phase != PhaseSeventh)
+	s.resetCommittedAndSetTurnFromDealer()
+	if idx, ok := s.studFirstToAct(); ok {
+		s.TurnIdx = idx
+	}
+}
+
+// studDeal gives one card to every live player, starting left of the dealer.
+func (s *State) studDeal(up bool) {
+	n := len(s.Order)
+	for i := 1; i <= n; i++ {
+		pid := s.Order[(s.DealerIdx+i)%n]
+		st := s.Seats[pid]
+		if !st.InHand || st.Folded {
+			continue
+		}
+		if len(s.Deck) == 0 {
+			return
+		}
+		c := s.Deck[0]
+		s.Deck = s.Deck[1:]
+		s.Holes[pid] = append(s.Holes[pid], c)
+		if up {
+			s.Upcards[pid] = append(s.Upcards[pid], c)
+		}
+	}
+}
+
+func (s *State) studBringIn() int {
+	best := -1
+	var low Card
+	for i, pid := range s.Order {
+		up := s.Upcards[pid]
+		if len(up) == 0 {
+			continue
+		}
+		c := up[len(up)-1]
+		if best == -1 || c.Rank < low.Rank || (c.Rank == low.Rank && c.Suit < low.Suit) {
+			best, low = i, c
+		}
+	}
+	if best == -1 {
+		return (s.DealerIdx + 1) % len(s.Order)
+	}
+	return best
+}
+
+// studFirstToAct returns the eligible player with the best hand showing.
+// Ties go to the first such player left of the dealer.
+func (s *State) studFirstToAct() (int, bool) {
+	n := len(s.Order)
+	best := -1
+	var bestVal HandValue
+	for i := 1; i <= n; i++ {
+		idx := (s.DealerIdx + i) % n
+		pid := s.Order[idx]
+		if !s.eligible(pid) {
+			continue
+		}
+		v := showingValue(s.Upcards[pid])
+		if best == -1 || bestVal.Less(v) {
+			best, bestVal = idx, v
+		}
+	}
+	return best, best != -1
+}
+
+// showingValue scores up to four exposed cards: only pairs, two pair, trips and
+// quads count (straights and flushes need five cards), then high cards.
+func showingValue(cards []Card) HandValue {
+	var rankCount [15]int
+	for _, c := range cards {
+		rankCount[c.Rank]++
+	}
+	type group struct {
+		rank Rank
+		cnt  int
+	}
+	var groups []group
+	for r := 14; r >= 2; r-- {
+		if rankCount[r] > 0 {
+			groups = append(groups, group{rank: Rank(r), cnt: rankCount[r]})
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].cnt > groups[j].cnt })
+
+	hv := HandValue{Cat: CatHighCard}
+	switch {
+	case len(groups) > 0 && groups[0].cnt == 4:
+		hv.Cat = CatQuads
+	case len(groups) > 0 && groups[0].cnt == 3:
+		hv.Cat = CatTrips
+	case len(groups) > 1 && groups[0].cnt == 2 && groups[1].cnt == 2:
+		hv.Cat = CatTwoPair
+	case len(groups) > 0 && groups[0].cnt == 2:
+		hv.Cat = CatOnePair
+	}
+	for i := 0; i < len(groups) && i < 5; i++ {
+		hv.Ranks[i] = groups[i].rank
+	}
+	return hv
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+// studTable deals a 1-bring-in stud hand to a, b and c with 100 each.
+func studTable(t *testing.T) *State {
+	t.Helper()
+	return dealt(t, func(s *State) { s.Variant = VariantStud }, 100, 100, 100)
+}
+
+// checkAround calls or checks for whoever is to act until the street closes.
+func checkAround(t *testing.T, s *State) {
+	t.Helper()
+	for i := 0; !s.RoundClosed(); i++ {
+		if i > 2*len(s.Order) {
+			t.Fatal("street never closed")
+		}
+		p := s.CurrentPlayer()
+		var err error
+		if s.Seats[p].Committed < s.CurrentBet {
+			err = s.Call(p)
+		} else {
+			err = s.Check(p)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStudDealsEveryStreetToEachPlayer(t *testing.T) {
+	s := studTable(t)
+
+	// the lowest upcard brings it in
+	var low PlayerID
+	for _, p := range s.Order {
+		if low == "" || s.Upcards[p][0].Rank < s.Upcards[low][0].Rank ||
+			(s.Upcards[p][0].Rank == s.Upcards[low][0].Rank && s.Upcards[p][0].Suit < s.Upcards[low][0].Suit) {
+			low = p
+		}
+	}
+	if s.Seats[low].Committed != 1 || s.Pot != 1 {
+		t.Fatalf("bring-in: %s has %d in, pot %d; want the low card's 1", low, s.Seats[low].Committed, s.Pot)
+	}
+
+	for _, street := range []struct {
+		phase    Phase
+		down, up int
+	}{
+		{PhasePreflop, 2, 1},
+		{PhaseFlop, 2, 2},
+		{PhaseTurn, 2, 3},
+		{PhaseRiver, 2, 4},
+		{PhaseSeventh, 3, 4},
+	} {
+		if s.Phase != street.phase {
+			t.Fatalf("phase %v, want %v", s.Phase, street.phase)
+		}
+		if len(s.Board) != 0 {
+			t.Fatalf("%v: stud dealt a board %v", s.Phase, s.Board)
+		}
+		for _, p := range s.Order {
+			if len(s.Holes[p]) != street.down+street.up || len(s.Upcards[p]) != street.up {
+				t.Fatalf("%v: %s holds %d cards, %d up; want %d, %d up",
+					s.Phase, p, len(s.Holes[p]), len(s.Upcards[p]), street.down+street.up, street.up)
+			}
+		}
+		checkAround(t, s)
+		s.AdvancePhase()
+	}
+	if s.Phase != PhaseShowdown || s.HandActive {
+		t.Fatalf("after 7th street: phase %v, active %v", s.Phase, s.HandActive)
+	}
+
+	// 21 cards out of one deck: nobody shares a card
+	seen := map[Card]bool{}
+	for _, p := range s.Order {
+		for _, c := range s.Holes[p] {
+			if seen[c] {
+				t.Fatalf("%v dealt twice", c)
+			}
+			seen[c] = true
+		}
+	}
+}
+
+func TestStudShowdownEvaluatesEachPlayersSevenCards(t *testing.T) {
+	s := studTable(t)
+	for s.Phase != PhaseShowdown {
+		checkAround(t, s)
+		s.AdvancePhase()
+	}
+	// b's trip aces need the 7th card; without it a's trip kings would win
+	s.Holes["a"] = cards(t, "Ks Kd Kc 5c 9d Jd 2c")
+	s.Holes["b"] = cards(t, "As Ad 3c 4c 8h 7d Ac")
+	s.Holes["c"] = cards(t, "Qs Qd 6h 6s Th 2d 3h")
+
+	sum := s.ResolveShowdown()
+	if len(sum.Pots) != 1 || !slices.Equal(sum.Pots[0].Winners, []PlayerID{"b"}) {
+		t.Fatalf("pots %+v, want b to take the only pot", sum.Pots)
+	}
+	if s.Seats["b"].Stack != 100+2*sum.Pots[0].Amount/3 {
+		t.Fatalf("b has %d after winning %d", s.Seats["b"].Stack, sum.Pots[0].Amount)
+	}
+}
@@ -61,6 +61,7 @@ const (
 	PhaseTurn
 	PhaseRiver
 	PhaseShowdown
+	PhaseSeventh // stud only: 7th street (kept last so existing values stay stable)
 )
 
 func (p Phase) String() string {
@@ -75,84 +76,262 @@ func (p Phase) String() string {
 		return "river"
 	case PhaseShowdown:
 		return "showdown"
+	case PhaseSeventh:
+		return "seventh"
 	default:
 		return fmt.Sprintf("phase(%d)", int(p))
 	}
 }
 
+// Game variants. The empty string means hold'em.
+const (
+	VariantHoldem = "holdem"
+	VariantStud   = "stud"
+	VariantOmaha  = "omaha"     // four hole cards; hands use exactly two of them
+	VariantShort  = "shortdeck" // hold'em with 2s–5s removed; a flush beats a full house
+)
+
+// FormatCards renders cards space-separated, e.g. "As Kd".
+func FormatCards(cs []Card) string {
+	out := ""
+	for i, c := range cs {
+		if i > 0 {
+			out += " "
+		}
+		out += c.String()
+	}
+	return out
+}
+
 // PlayerID is a stable identifier (e.g. NodeID string)
 type PlayerID = string
 
 type Seat struct {
 	Player    PlayerID
+	Index     int // seat number, fixed while the player sits; Order runs in seat-number order
 	Stack     int64
 	Committed int64 // chips committed this betting round
-	InHand    bool
-	AllIn     bool
-	Folded    bool
+
+	TotalCommitted int64 // chips committed this hand, across all streets
+	InHand         bool
+	AllIn          bool
+	Folded         bool
+	AutoCheck      bool // check automatically whenever no bet is faced (a bet means a real decision, for that street only)
+	SittingOut     bool // keeps seat and stack but is not dealt in
+	Show           bool // table the hand at showdown even if it loses (see reveal.go)
+	Muck           bool // muck a losing hand at showdown rather than show it
+
+	TotalBuyin int64 // chips bought in this session: the first buy-in plus every rebuy
 }
 
 // Live state with game logic
 type State struct {
-	SmallBlind    int64
-	BigBlind      int64
-	DealerIdx     int
-	Order         []PlayerID
-	TurnIdx       int
-	Phase         Phase
-	Pot           int64
-	Seats         map[PlayerID]*Seat
-	Deck          []Card
-	Board         []Card
-	Holes         map[PlayerID][]Card
-	CurrentBet    int64 // highest committed in this round
-	ActorsToAct   int   // # eligible players who still must act this street
-	LastRaiseSize int64 // size of last raise increment (open counts as a raise from 0)
-	HandActive    bool  // true between StartHand() and end of hand
+	Variant    string // VariantHoldem (default), VariantOmaha, VariantShort or VariantStud
+	MinPlayers int    // players required to start a hand (values below 2 mean 2)
+	RoundRobin bool   // deal hole cards one at a time around the table instead of two at once
+	OpenCards  bool   // hole cards are public: broadcast snapshots carry all of them
+	BurnCards  bool   // burn one card before each board street (stud: each dealing round)
+
+	Straddles      int    // hold'em: straddles posted from UTG outward, each double the last
+	ButtonStraddle bool   // hold'em: the button posts a further straddle and acts last preflop
+	HiLo           bool   // split each pot between the best high and the best 8-or-better low
+	Structure      string // NoLimit (default), PotLimit or FixedLimit: see limits.go
+	MaxSeats       int    // Sit refuses players beyond this many (0 = no cap)
+	SitOutBlinds   bool   // hold'em: sitting-out players post the blinds that reach them, then fold (else they are skipped)
+	MinBuyin       int64  // smallest buy-in Sit accepts
+	MaxBuyin       int64  // largest buy-in Sit accepts, and AddChips tops a stack up to (0 = no cap)
+	SmallBlind     int64
+	BigBlind       int64
+	Ante           int64 // hold'em: dead money from every player dealt in, before the blinds
+	DealerIdx      int
+	DeadButton     bool       // hold'em: the button is on an empty seat; DealerIdx is the player before it
+	Last           *Positions // hold'em: the last hand's button and blinds (see blinds.go); nil before the first
+	Order          []PlayerID
+	TurnIdx        int
+	Phase          Phase
+	Pot            int64
+	Seats          map[PlayerID]*Seat
+	Deck           []Card
+	Board          []Card
+	Holes          map[PlayerID][]Card
+	Upcards        map[PlayerID][]Card // stud: face-up cards (public)
+	CurrentBet     int64               // highest committed in this round
+	ActorsToAct    int                 // # eligible players who still must act this street
+	LastRaiseSize  int64               // size of last raise increment (open counts as a raise from 0)
+	Raises         int                 // bets and raises made this street (fixed limit caps them)
+	Aggressor      PlayerID            // last to bet or raise this street; shows first at showdown
+	HandActive     bool                // true between StartHand() and end of hand
 }
 
 // Serializable struct for network/discovery
 type EngineSnapshot struct {
-	SmallBlind int64
-	BigBlind   int64
-	DealerIdx  int
-	Order      []PlayerID
-	TurnIdx    int
-	Phase      Phase
-	Pot        int64
-	Board      []Card
-	Seats      map[PlayerID]Seat
+	Variant    string
+	MinPlayers int
+	RoundRobin bool
+	OpenCards  bool
+	BurnCards  bool
+
+	Straddles      int
+	ButtonStraddle bool
+	HiLo           bool
+	Structure      string `json:",omitempty"`
+	MaxSeats       int    `json:",omitempty"`
+	SitOutBlinds   bool   `json:",omitempty"`
+	MinBuyin       int64  `json:",omitempty"`
+	MaxBuyin       int64  `json:",omitempty"`
+	SmallBlind     int64
+	BigBlind       int64
+	Ante           int64 `json:",omitempty"`
+	DealerIdx      int
+	DeadButton     bool       `json:",omitempty"`
+	Last           *Positions `json:",omitempty"`
+	Order          []PlayerID
+	TurnIdx        int
+	Phase          Phase
+	Pot            int64
+	Board          []Card
+	Upcards        map[PlayerID][]Card
+	Seats          map[PlayerID]Seat
+
+	// betting-round state, so a follower resyncing mid-hand can keep applying
+	HandActive    bool
+	CurrentBet    int64
+	LastRaiseSize int64
+	ActorsToAct   int
+	Raises        int      `json:",omitempty"`
+	Aggressor     PlayerID `json:",omitempty"`
+
+	// Holes is empty in broadcast snapshots; a targeted snapshot (SnapshotFor)
+	// carries the recipient's own cards only.
+	Holes map[PlayerID][]Card `json:",omitempty"`
+}
+
+// SnapshotFor is Snapshot plus p's own hole cards — for unicast to p only.
+func (s *State) SnapshotFor(p PlayerID) EngineSnapshot {
+	ss := s.Snapshot()
+	if hc, ok := s.Holes[p]; ok && !s.OpenCards {
+		ss.Holes = map[PlayerID][]Card{p: append([]Card{}, hc...)}
+	}
+	return ss
 }
 
 // Snapshot produces a serializable copy of the current engine state.
+// It never includes hole cards, so it is safe to broadcast — unless the table
+// plays OpenCards, where every hole card is public by design.
 func (s *State) Snapshot() EngineSnapshot {
 	seatsCopy := make(map[PlayerID]Seat, len(s.Seats))
 	for id, st := range s.Seats {
 		seatsCopy[id] = *st
 	}
+	upCopy := make(map[PlayerID][]Card, len(s.Upcards))
+	for id, cs := range s.Upcards {
+		upCopy[id] = append([]Card{}, cs...)
+	}
+	var last *Positions
+	if s.Last != nil {
+		p := *s.Last
+		last = &p
+	}
+	var holes map[PlayerID][]Card
+	if s.OpenCards {
+		holes = s.holesCopy()
+	}
 	return EngineSnapshot{
-		SmallBlind: s.SmallBlind,
-		BigBlind:   s.BigBlind,
-		DealerIdx:  s.DealerIdx,
-		Order:      append([]PlayerID{}, s.Order...),
-		TurnIdx:    s.TurnIdx,
-		Phase:      s.Phase,
-		Pot:        s.Pot,
-		Board:      append([]Card{}, s.Board...),
-		Seats:      seatsCopy,
+		Variant:    s.Variant,
+		MinPlayers: s.MinPlayers,
+		RoundRobin: s.RoundRobin,
+		OpenCards:  s.OpenCards,
+		BurnCards:  s.BurnCards,
+
+		Straddles:      s.Straddles,
+		ButtonStraddle: s.ButtonStraddle,
+		HiLo:           s.HiLo,
+		Structure:      s.Structure,
+		MaxSeats:       s.MaxSeats,
+		SitOutBlinds:   s.SitOutBlinds,
+		MinBuyin:       s.MinBuyin,
+		MaxBuyin:       s.MaxBuyin,
+		Holes:          holes,
+		SmallBlind:     s.SmallBlind,
+		BigBlind:       s.BigBlind,
+		Ante:           s.Ante,
+		DealerIdx:      s.DealerIdx,
+		DeadButton:     s.DeadButton,
+		Last:           last,
+		Order:          append([]PlayerID{}, s.Order...),
+		TurnIdx:        s.TurnIdx,
+		Phase:          s.Phase,
+		Pot:            s.Pot,
+		Board:          append([]Card{}, s.Board...),
+		Upcards:        upCopy,
+		Seats:          seatsCopy,
+
+		HandActive:    s.HandActive,
+		CurrentBet:    s.CurrentBet,
+		LastRaiseSize: s.LastRaiseSize,
+		ActorsToAct:   s.ActorsToAct,
+		Raises:        s.Raises,
+		Aggressor:     s.Aggressor,
+	}
+}
+
+// FullSnapshot is Snapshot with every player's hole cards. It is for local
+// persistence only (see table.Export) and must never be sent to peers.
+func (s *State) FullSnapshot() EngineSnapshot {
+	ss := s.Snapshot()
+	ss.Holes = s.holesCopy()
+	return ss
+}
+
+func (s *State) holesCopy() map[PlayerID][]Card {
+	out := make(map[PlayerID][]Card, len(s.Holes))
+	for id, cs := range s.Holes {
+		out[id] = append([]Card{}, cs...)
 	}
+	return out
 }
 
 // RestoreFromSnapshot installs a previously captured snapshot into the engine.
 func (s *State) RestoreFromSnapshot(ss EngineSnapshot) {
+	s.Variant = ss.Variant
+	s.MinPlayers = ss.MinPlayers
+	s.RoundRobin = ss.RoundRobin
+	s.OpenCards = ss.OpenCards
+	s.BurnCards = ss.BurnCards
+	s.Straddles = ss.Straddles
+	s.ButtonStraddle = ss.ButtonStraddle
+	s.HiLo = ss.HiLo
+	s.Structure = ss.Structure
+	s.MaxSeats = ss.MaxSeats
+	s.SitOutBlinds = ss.SitOutBlinds
+	s.MinBuyin = ss.MinBuyin
+	s.MaxBuyin = ss.MaxBuyin
 	s.SmallBlind = ss.SmallBlind
 	s.BigBlind = ss.BigBlind
+	s.Ante = ss.Ante
 	s.DealerIdx = ss.DealerIdx
+	s.DeadButton = ss.DeadButton
+	s.Last = nil
+	if ss.Last != nil {
+		p := *ss.Last
+		s.Last = &p
+	}
 	s.Order = append([]PlayerID{}, ss.Order...)
 	s.TurnIdx = ss.TurnIdx
 	s.Phase = ss.Phase
 	s.Pot = ss.Pot
 	s.Board = append([]Card{}, ss.Board...)
+	s.Deck = nil // not carried in snapshots; whatever we held belongs to another hand
+	s.HandActive = ss.HandActive
+	s.CurrentBet = ss.CurrentBet
+	s.LastRaiseSize = ss.LastRaiseSize
+	s.ActorsToAct = ss.ActorsToAct
+	s.Raises = ss.Raises
+	s.Aggressor = ss.Aggressor
+	s.Upcards = make(map[PlayerID][]Card, len(ss.Upcards))
+	for id, cs := range ss.Upcards {
+		s.Upcards[id] = append([]Card{}, cs...)
+	}
 
 	// Rebuild Seats as pointers from the value map in the snapshot
 	if s.Seats == nil {
@@ -166,4 +345,24 @@ func (s *State) RestoreFromSnapshot(ss EngineSnapshot) {
 		copy := st
 		s.Seats[id] = &copy
 	}
+	// snapshots from before seat numbers leave them all zero: number the
+	// seats in the order the snapshot gives
+	numbered := make(map[int]bool, len(s.Seats))
+	for _, st := range s.Seats {
+		numbered[st.Index] = true
+	}
+	if len(numbered) < len(s.Seats) {
+		for i, id := range s.Order {
+			if st, ok := s.Seats[id]; ok {
+				st.Index = i
+			}
+		}
+	}
+
+	// Targeted snapshots carry the recipient's hole cards, broadcast ones
+	// none: any held from an earlier hand are stale either way
+	s.Holes = make(map[PlayerID][]Card, len(ss.Holes))
+	for id, cs := range ss.Holes {
+		s.Holes[id] = append([]Card{}, cs...)
+	}
 }
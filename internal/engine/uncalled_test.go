@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+// headsUp deals a 1/2 hand between a and b with these stacks.
+func headsUp(t *testing.T, a, b int64) *State {
+	t.Helper()
+	return dealt(t, nil, a, b)
+}
+
+// shove puts the player to act all in.
+func shove(t *testing.T, s *State) PlayerID {
+	t.Helper()
+	p := s.CurrentPlayer()
+	st := s.Seats[p]
+	if err := s.Raise(p, st.Stack-(s.CurrentBet-st.Committed)); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestFoldToAnOversizedShoveReturnsTheUncalledBet(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	shover := shove(t, s)
+	folder := s.CurrentPlayer()
+	if err := s.Fold(folder); err != nil {
+		t.Fatal(err)
+	}
+	winner, ok := s.OnlyOneInHand()
+	if !ok || winner != shover {
+		t.Fatalf("after the fold %q is left, want %s", winner, shover)
+	}
+	sum := s.AwardUncontested(winner)
+	// the shove was called only as far as the big blind's 2
+	if sum.UncalledReturn != 98 || sum.UncalledTo != shover || sum.TotalPayout != 4 {
+		t.Fatalf("returned %d to %s, paid %d; want 98 to %s and a pot of 4",
+			sum.UncalledReturn, sum.UncalledTo, sum.TotalPayout, shover)
+	}
+	if s.Seats[shover].Stack != 102 || s.Seats[folder].Stack != 98 {
+		t.Fatalf("stacks %d / %d, want 102 / 98", s.Seats[shover].Stack, s.Seats[folder].Stack)
+	}
+}
+
+func TestShowdownReturnsWhatTheShortStackCouldNotCall(t *testing.T) {
+	s := headsUp(t, 100, 100)
+	shover := shove(t, s)
+	caller := s.CurrentPlayer()
+	s.Seats[caller].Stack = 48 // 2 in as the big blind, 50 in all
+	if err := s.Call(caller); err != nil {
+		t.Fatal(err)
+	}
+	for s.Phase != PhaseShowdown {
+		s.AdvancePhase()
+	}
+	sum := s.ResolveShowdown()
+	if sum.UncalledReturn != 50 || sum.UncalledTo != shover || sum.TotalPayout != 100 {
+		t.Fatalf("returned %d to %s, paid %d; want 50 to %s and a pot of 100",
+			sum.UncalledReturn, sum.UncalledTo, sum.TotalPayout, shover)
+	}
+}
+
+func TestEachPotGoesToTheBestHandAmongItsPlayers(t *testing.T) {
+	s := dealt(t, nil, 20, 50, 100)
+	for !s.RoundClosed() {
+		p := s.CurrentPlayer()
+		st := s.Seats[p]
+		var err error
+		if st.Committed+st.Stack > s.CurrentBet {
+			err = s.Raise(p, st.Stack-(s.CurrentBet-st.Committed)) // all in
+		} else {
+			err = s.Call(p)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for s.Phase != PhaseShowdown {
+		s.AdvancePhase()
+	}
+	s.Board = cards(t, "2c 7d 9h Js 3s")
+	s.Holes["a"] = cards(t, "Jc Jd") // best: trip jacks, but all in for 20
+	s.Holes["b"] = cards(t, "9c 9d") // second: trip nines, all in for 50
+	s.Holes["c"] = cards(t, "Ah Kd") // ace high, covering everyone
+
+	sum := s.ResolveShowdown()
+	if sum.UncalledReturn != 50 || sum.UncalledTo != "c" {
+		t.Fatalf("returned %d to %s, want c's uncalled 50", sum.UncalledReturn, sum.UncalledTo)
+	}
+	if len(sum.Pots) != 2 || sum.Pots[0].Amount != 60 || !slices.Equal(sum.Pots[0].Winners, []PlayerID{"a"}) ||
+		sum.Pots[1].Amount != 60 || !slices.Equal(sum.Pots[1].Winners, []PlayerID{"b"}) {
+		t.Fatalf("pots %+v, want a's main pot of 60 and b's side pot of 60", sum.Pots)
+	}
+	for p, want := range map[PlayerID]int64{"a": 60, "b": 60, "c": 50} {
+		if got := s.Seats[p].Stack; got != want {
+			t.Errorf("%s has %d, want %d", p, got, want)
+		}
+	}
+}
+
+func TestSidePotsFollowEachAllInDepth(t *testing.T) {
+	s := dealt(t, nil, 30, 60, 100, 100)
+	for !s.RoundClosed() {
+		p := s.CurrentPlayer()
+		st := s.Seats[p]
+		var err error
+		if st.Committed+st.Stack > s.CurrentBet {
+			err = s.Raise(p, st.Stack-(s.CurrentBet-st.Committed)) // all in
+		} else {
+			err = s.Call(p)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.AdvancePhase() // the pots span streets, not just the one in play
+
+	want := []SidePot{
+		{120, []PlayerID{"a", "b", "c", "d"}},
+		{90, []PlayerID{"b", "c", "d"}},
+		{80, []PlayerID{"c", "d"}},
+	}
+	got := s.ComputeSidePots()
+	if len(got) != len(want) {
+		t.Fatalf("pots %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Amount != want[i].Amount || !slices.Equal(got[i].Eligible, want[i].Eligible) {
+			t.Fatalf("pot %d is %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import "fmt"
+
+// Validate checks the state's internal consistency: seating order matches the
+// seats, indexes are in range, chip amounts are sane and no card appears twice.
+// It is meant for state received from elsewhere (snapshots) before trusting it.
+func (s *State) Validate() error {
+	seen := make(map[PlayerID]struct{}, len(s.Order))
+	for _, pid := range s.Order {
+		if _, dup := seen[pid]; dup {
+			return fmt.Errorf("player %s seated twice", pid)
+		}
+		seen[pid] = struct{}{}
+		if _, ok := s.Seats[pid]; !ok {
+			return fmt.Errorf("player %s in order but has no seat", pid)
+		}
+	}
+	if len(s.Seats) != len(s.Order) {
+		return fmt.Errorf("%d seats but %d players in order", len(s.Seats), len(s.Order))
+	}
+	if len(s.Order) > 0 {
+		if s.DealerIdx < 0 || s.DealerIdx >= len(s.Order) {
+			return fmt.Errorf("dealer index %d out of range", s.DealerIdx)
+		}
+		if s.TurnIdx < 0 || s.TurnIdx >= len(s.Order) {
+			return fmt.Errorf("turn index %d out of range", s.TurnIdx)
+		}
+	}
+
+	if s.Pot < 0 || s.CurrentBet < 0 {
+		return fmt.Errorf("negative pot (%d) or current bet (%d)", s.Pot, s.CurrentBet)
+	}
+	var committed int64
+	for pid, st := range s.Seats {
+		if st.Stack < 0 || st.Committed < 0 || st.TotalCommitted < 0 {
+			return fmt.Errorf("player %s has negative chips", pid)
+		}
+		if s.HandActive {
+			committed += st.TotalCommitted
+		}
+	}
+	// Players who left mid-hand leave their chips behind, so the pot may hold
+	// more than the seated players put in — never less.
+	if committed > s.Pot {
+		return fmt.Errorf("pot %d below seated contributions %d", s.Pot, committed)
+	}
+
+	// Stud upcards are also in Holes, so check each against the board separately.
+	if err := distinctCards(s.Board, s.Holes); err != nil {
+		return err
+	}
+	return distinctCards(s.Board, s.Upcards)
+}
+
+func distinctCards(board []Card, hands map[PlayerID][]Card) error {
+	seen := make(map[Card]struct{})
+	see := func(cs []Card) error {
+		for _, c := range cs {
+			if _, ok := rankToChar(c.Rank); !ok {
+				return fmt.Errorf("invalid card rank %d", c.Rank)
+			}
+			if _, ok := suitToChar(c.Suit); !ok {
+				return fmt.Errorf("invalid card suit %d", c.Suit)
+			}
+			if _, dup := seen[c]; dup {
+				return fmt.Errorf("duplicate card %s", c.String())
+			}
+			seen[c] = struct{}{}
+		}
+		return nil
+	}
+	if err := see(board); err != nil {
+		return err
+	}
+	for _, cs := range hands {
+		if err := see(cs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package netx
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"p2poker/internal/protocol"
+)
+
+// InboxPolicy is what a full inbox does with one more message. Blocking
+// keeps every message but stalls the sender: a TCP reader stops reading its
+// connection, and the dispatcher stops routing for every table. The drop
+// policies keep the sender moving and count what they discard; a table that
+// missed messages notices the gap and resyncs from a snapshot. Proposals and
+// heartbeats are never discarded (see undroppable): nothing would notice
+// their loss until it was too late.
+type InboxPolicy int
+
+const (
+	InboxBlock      InboxPolicy = iota // wait for room (the default)
+	InboxDropOldest                    // discard the oldest queued message to make room
+	InboxDropNewest                    // discard the arriving message
+)
+
+func (p InboxPolicy) String() string {
+	switch p {
+	case InboxBlock:
+		return "block"
+	case InboxDropOldest:
+		return "drop-oldest"
+	case InboxDropNewest:
+		return "drop-newest"
+	default:
+		return fmt.Sprintf("policy(%d)", int(p))
+	}
+}
+
+// ParseInboxPolicy reads "block", "drop-oldest" or "drop-newest".
+func ParseInboxPolicy(s string) (InboxPolicy, error) {
+	for _, p := range []InboxPolicy{InboxBlock, InboxDropOldest, InboxDropNewest} {
+		if s == p.String() {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown inbox policy %q (want block, drop-oldest or drop-newest)", s)
+}
+
+// undroppable reports whether a full inbox must wait for room for msg rather
+// than discard it, whatever its policy. A lost proposal is never answered, so
+// its player waits on an action nobody saw, and lost heartbeats look like a
+// dead authority and set off a takeover.
+func undroppable(msg protocol.NetMessage) bool {
+	return msg.Type == protocol.MsgPropose || msg.Type == protocol.MsgHeartbeat
+}
+
+// Deliver puts msg on ch under policy p, adding every message it discards to
+// dropped. An undroppable msg waits for room; under InboxDropOldest an
+// undroppable message that would have been evicted is queued again, behind
+// what is already waiting, and msg waits for room after it.
+func Deliver(ch chan protocol.NetMessage, msg protocol.NetMessage, p InboxPolicy, dropped *atomic.Uint64) {
+	if undroppable(msg) {
+		p = InboxBlock
+	}
+	switch p {
+	case InboxDropNewest:
+		select {
+		case ch <- msg:
+		default:
+			dropped.Add(1)
+		}
+	case InboxDropOldest:
+		for {
+			select {
+			case ch <- msg:
+				return
+			default:
+			}
+			select {
+			case old := <-ch:
+				if undroppable(old) {
+					ch <- old
+					ch <- msg
+					return
+				}
+				dropped.Add(1)
+			default: // drained meanwhile; try again
+			}
+		}
+	default:
+		ch <- msg
+	}
+}
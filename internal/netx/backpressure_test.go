@@ -0,0 +1,128 @@
+package netx
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+func numbered(typ protocol.MsgType, seq uint64) protocol.NetMessage {
+	return protocol.NetMessage{Type: typ, Seq: seq}
+}
+
+func drain(ch chan protocol.NetMessage) []uint64 {
+	var seqs []uint64
+	for len(ch) > 0 {
+		seqs = append(seqs, (<-ch).Seq)
+	}
+	return seqs
+}
+
+func equalSeqs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDropPoliciesFloodAFullInbox(t *testing.T) {
+	for _, tc := range []struct {
+		p    InboxPolicy
+		kept []uint64
+	}{
+		{InboxDropNewest, []uint64{1, 2, 3, 4}},
+		{InboxDropOldest, []uint64{7, 8, 9, 10}},
+	} {
+		ch := make(chan protocol.NetMessage, 4)
+		var dropped atomic.Uint64
+		for i := uint64(1); i <= 10; i++ {
+			Deliver(ch, numbered(protocol.MsgCommit, i), tc.p, &dropped)
+		}
+		if got := drain(ch); !equalSeqs(got, tc.kept) || dropped.Load() != 6 {
+			t.Errorf("%s: kept %v and dropped %d, want %v and 6", tc.p, got, dropped.Load(), tc.kept)
+		}
+	}
+}
+
+// deliverLater runs Deliver and reports when it has returned.
+func deliverLater(ch chan protocol.NetMessage, msg protocol.NetMessage, p InboxPolicy, dropped *atomic.Uint64) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		Deliver(ch, msg, p, dropped)
+		close(done)
+	}()
+	return done
+}
+
+func returned(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+func TestBlockPolicyWaitsForRoom(t *testing.T) {
+	ch := make(chan protocol.NetMessage, 1)
+	var dropped atomic.Uint64
+	Deliver(ch, numbered(protocol.MsgCommit, 1), InboxBlock, &dropped)
+	done := deliverLater(ch, numbered(protocol.MsgCommit, 2), InboxBlock, &dropped)
+	if returned(done) {
+		t.Fatal("delivered into a full inbox")
+	}
+	<-ch
+	if !returned(done) || (<-ch).Seq != 2 || dropped.Load() != 0 {
+		t.Fatal("blocked message lost")
+	}
+}
+
+func TestProposalsAndHeartbeatsAreNeverDropped(t *testing.T) {
+	for _, p := range []InboxPolicy{InboxDropNewest, InboxDropOldest} {
+		for _, typ := range []protocol.MsgType{protocol.MsgPropose, protocol.MsgHeartbeat} {
+			// arriving at a full inbox, it waits
+			ch := make(chan protocol.NetMessage, 2)
+			var dropped atomic.Uint64
+			Deliver(ch, numbered(protocol.MsgCommit, 1), p, &dropped)
+			Deliver(ch, numbered(protocol.MsgCommit, 2), p, &dropped)
+			done := deliverLater(ch, numbered(typ, 3), p, &dropped)
+			if returned(done) && dropped.Load() > 0 {
+				t.Fatalf("%s: %s dropped something to make room", p, typ)
+			}
+			<-ch
+			if !returned(done) {
+				t.Fatalf("%s: %s never delivered", p, typ)
+			}
+			if got := drain(ch); !equalSeqs(got, []uint64{2, 3}) || dropped.Load() != 0 {
+				t.Fatalf("%s: %s: inbox %v, %d dropped", p, typ, got, dropped.Load())
+			}
+
+		}
+	}
+}
+
+func TestDropOldestNeverEvictsAProposal(t *testing.T) {
+	ch := make(chan protocol.NetMessage, 2)
+	var dropped atomic.Uint64
+	Deliver(ch, numbered(protocol.MsgPropose, 1), InboxDropOldest, &dropped)
+	Deliver(ch, numbered(protocol.MsgCommit, 2), InboxDropOldest, &dropped)
+	// the proposal goes to the back of the queue and the commit waits behind it
+	done := deliverLater(ch, numbered(protocol.MsgCommit, 3), InboxDropOldest, &dropped)
+	if returned(done) {
+		t.Fatal("made room by evicting the proposal")
+	}
+	got := []uint64{(<-ch).Seq}
+	if !returned(done) {
+		t.Fatal("commit never delivered")
+	}
+	if got = append(got, drain(ch)...); !equalSeqs(got, []uint64{2, 1, 3}) || dropped.Load() != 0 {
+		t.Fatalf("inbox %v, %d dropped; want [2 1 3], none", got, dropped.Load())
+	}
+}
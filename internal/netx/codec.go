@@ -3,6 +3,7 @@ package netx
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -11,13 +12,36 @@ import (
 	"p2poker/internal/protocol"
 )
 
-// length‑prefixed JSON codec: [u32 len][json bytes]
+// length‑prefixed JSON codec: [u32 len][body]
+//
+// The body is either bare JSON (always starting with '{'), as every peer has
+// always sent, or a one-byte encoding header followed by the payload. Decode
+// reads all three forms, so a peer that never compresses still interoperates;
+// one that does should only turn it on once its peers decode gzip frames.
+
+const maxFrame = 10 * 1024 * 1024
+
+// Body encodings. JSON never starts with either byte.
+const (
+	encRaw  byte = 0x00 // header then plain JSON
+	encGzip byte = 0x01 // header then gzip-compressed JSON
+)
+
+// CompressThreshold gzips frames whose JSON is at least this many bytes
+// (large snapshots, mostly), when that makes them smaller. 0 sends every frame
+// as bare JSON. Set it before starting the network.
+var CompressThreshold = 0
 
 func Encode(msg protocol.NetMessage) ([]byte, error) {
 	b, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
+	if CompressThreshold > 0 && len(b) >= CompressThreshold {
+		if z, err := deflate(b); err == nil && len(z) < len(b) {
+			b = z
+		}
+	}
 	var buf bytes.Buffer
 	if err := binary.Write(&buf, binary.BigEndian, uint32(len(b))); err != nil {
 		return nil, err
@@ -28,19 +52,50 @@ func Encode(msg protocol.NetMessage) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// deflate returns the encGzip body for b.
+func deflate(b []byte) ([]byte, error) {
+	var z bytes.Buffer
+	z.WriteByte(encGzip)
+	zw := gzip.NewWriter(&z)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return z.Bytes(), nil
+}
+
 func Decode(r *bufio.Reader) (protocol.NetMessage, error) {
 	var msg protocol.NetMessage
 	var n uint32
 	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
 		return msg, err
 	}
-	if n > 10*1024*1024 {
+	if n > maxFrame {
 		return msg, fmt.Errorf("frame too large: %d", n)
 	}
 	buf := make([]byte, n)
 	if _, err := io.ReadFull(r, buf); err != nil {
 		return msg, err
 	}
+	if len(buf) > 0 {
+		switch buf[0] {
+		case encRaw:
+			buf = buf[1:]
+		case encGzip:
+			zr, err := gzip.NewReader(bytes.NewReader(buf[1:]))
+			if err != nil {
+				return msg, fmt.Errorf("gzip frame: %w", err)
+			}
+			if buf, err = io.ReadAll(io.LimitReader(zr, maxFrame+1)); err != nil {
+				return msg, fmt.Errorf("gzip frame: %w", err)
+			}
+			if len(buf) > maxFrame {
+				return msg, fmt.Errorf("frame too large inflated")
+			}
+		}
+	}
 	if err := json.Unmarshal(buf, &msg); err != nil {
 		return msg, err
 	}
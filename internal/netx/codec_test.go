@@ -0,0 +1,83 @@
+package netx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// bigSnapshot is a SNAPSHOT frame for a table of n seated players.
+func bigSnapshot(t *testing.T, n int) protocol.NetMessage {
+	t.Helper()
+	s := engine.NewState(1, 2)
+	for i := 0; i < n; i++ {
+		if err := s.SitStack(engine.PlayerID(fmt.Sprintf("player-%03d", i)), 1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ej, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return protocol.NetMessage{
+		Type:  protocol.MsgSnapshot,
+		From:  "n-1",
+		Table: "t-1",
+		Seq:   42,
+		State: &protocol.TableSnapshot{Seq: 42, Authority: "n-1", EngineJSON: ej},
+	}
+}
+
+func roundTrip(t *testing.T, msg protocol.NetMessage) ([]byte, protocol.NetMessage) {
+	t.Helper()
+	frame, err := Encode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(bufio.NewReader(bytes.NewReader(frame)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frame, got
+}
+
+func TestLargeSnapshotsAreGzippedAndDecodeUnchanged(t *testing.T) {
+	msg := bigSnapshot(t, 500)
+
+	raw, plain := roundTrip(t, msg)
+	defer func(old int) { CompressThreshold = old }(CompressThreshold)
+	CompressThreshold = 1024
+	zipped, inflated := roundTrip(t, msg)
+
+	if zipped[4] != encGzip {
+		t.Fatalf("body starts %#x, want the gzip header", zipped[4])
+	}
+	if len(zipped)*4 > len(raw) {
+		t.Fatalf("gzipped frame is %d bytes of %d, want under a quarter", len(zipped), len(raw))
+	}
+	if !reflect.DeepEqual(inflated, plain) || !bytes.Equal(inflated.State.EngineJSON, plain.State.EngineJSON) {
+		t.Fatal("gzipped snapshot decoded differently from the bare one")
+	}
+
+	// frames under the threshold still go out as bare JSON for old peers
+	small, _ := roundTrip(t, protocol.NetMessage{Type: protocol.MsgHeartbeat, From: "n-1"})
+	if small[4] != '{' {
+		t.Fatalf("small frame body starts %#x, want bare JSON", small[4])
+	}
+}
+
+func TestDecodeReadsTheRawHeader(t *testing.T) {
+	b, _ := json.Marshal(protocol.NetMessage{Type: protocol.MsgHeartbeat, From: "n-2", Seq: 3})
+	body := append([]byte{encRaw}, b...)
+	frame := append([]byte{0, 0, 0, byte(len(body))}, body...)
+	got, err := Decode(bufio.NewReader(bytes.NewReader(frame)))
+	if err != nil || got.From != "n-2" || got.Seq != 3 {
+		t.Fatalf("decoded %+v, %v", got, err)
+	}
+}
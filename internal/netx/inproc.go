@@ -21,6 +21,14 @@ func NewInproc() *Inproc {
 
 func (n *Inproc) Inbox() <-chan protocol.NetMessage  { return n.inbox }
 func (n *Inproc) Outbox() chan<- protocol.NetMessage { return n.outbox }
+func (n *Inproc) OutboxLen() int                     { return len(n.outbox) }
+func (n *Inproc) OutboxCap() int                     { return cap(n.outbox) }
+
+// SendTo delivers msg to this process, the only node there is.
+func (n *Inproc) SendTo(_ protocol.NodeID, msg protocol.NetMessage) error {
+	n.inbox <- msg
+	return nil
+}
 
 func (n *Inproc) Start(ctx context.Context) error {
 	go func() {
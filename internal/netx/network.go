@@ -2,12 +2,25 @@ package netx
 
 import (
 	"context"
+	"errors"
+
 	"p2poker/internal/protocol"
 )
 
+// ErrUnknownNode is returned by SendTo when no connection leads to the node.
+var ErrUnknownNode = errors.New("no connection to node")
+
 type Network interface {
 	Inbox() <-chan protocol.NetMessage
+	// Outbox broadcasts, except that a message with To set is sent to that
+	// node alone (see SendTo), or broadcast if it cannot be reached directly.
 	Outbox() chan<- protocol.NetMessage
+	// SendTo writes msg to node only.
+	SendTo(node protocol.NodeID, msg protocol.NetMessage) error
+	// OutboxLen and OutboxCap expose send-side backpressure: messages queued
+	// but not yet written, and the queue's capacity (sends block once full).
+	OutboxLen() int
+	OutboxCap() int
 	Start(ctx context.Context) error
 	Close() error
 }
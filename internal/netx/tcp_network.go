@@ -3,10 +3,16 @@ package netx
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
+	"errors"
 	"io"
 	"log"
+	"math/rand"
 	"net"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"p2poker/internal/protocol"
 )
@@ -14,15 +20,62 @@ import (
 // TCP implements Network with a simple peer fan‑out writer and per‑conn readers.
 // All messages placed on Outbox() are broadcast to all connected peers.
 // Use AddPeer to dial and connect to others.
+//
+// Each side of a new connection first sends a HELLO frame carrying its
+// NodeID; until that arrives the connection carries nothing else. Peers are
+// keyed by NodeID, so a node reached twice (both sides dialing at once, or a
+// reconnect) keeps a single connection: of two live ones, the one dialed by
+// the lexicographically smaller NodeID.
+//
+// Addresses passed to AddPeer are remembered: when such a connection drops
+// it is re-dialed with capped, jittered exponential backoff until the node is
+// reachable again. Inbound connections are left for their dialer to restore.
 
 type TCP struct {
-	addr   string
-	inbox  chan protocol.NetMessage
-	outbox chan protocol.NetMessage
+	addr    string
+	self    protocol.NodeID // sent in our HELLO; see SetNodeID
+	tls     *tls.Config     // non-nil: every connection is TLS (see NewTLS)
+	inbox   chan protocol.NetMessage
+	outbox  chan protocol.NetMessage
+	policy  InboxPolicy   // when inbox is full; see SetInboxPolicy
+	dropped atomic.Uint64 // inbound messages discarded by policy
 
-	ln    net.Listener
-	mu    sync.RWMutex
-	peers map[string]net.Conn // addr -> conn
+	ln     net.Listener
+	ctx    context.Context // from Start; ends redials
+	mu     sync.RWMutex
+	peers  map[protocol.NodeID]peerConn
+	dials  map[string]*dialState // addresses we dialed, for reconnects
+	closed bool
+	onPeer func(protocol.NodeID, bool) // see OnPeer
+}
+
+// Reconnect backoff: the first retry waits about redialMin, doubling per
+// failed attempt up to redialMax, each wait jittered by up to ±20%.
+const (
+	redialMin = 250 * time.Millisecond
+	redialMax = 30 * time.Second
+)
+
+// dialState tracks an address given to AddPeer.
+type dialState struct {
+	node     protocol.NodeID // learned from its HELLO
+	retrying bool            // a redial loop is running
+	attempts int             // failed redials since it was last connected
+}
+
+// PeerState is one entry of Peers.
+type PeerState struct {
+	Node      protocol.NodeID // empty until a dialed address has answered once
+	Addr      string
+	Dialed    bool // we dialed it (and so redial it); otherwise it dialed us
+	Connected bool
+	Attempts  int // failed reconnects so far, while down
+}
+
+// peerConn is a handshaken connection and which end dialed it.
+type peerConn struct {
+	conn   net.Conn
+	dialer protocol.NodeID
 }
 
 func NewTCP(addr string) *TCP {
@@ -30,25 +83,57 @@ func NewTCP(addr string) *TCP {
 		addr:   addr,
 		inbox:  make(chan protocol.NetMessage, 4096),
 		outbox: make(chan protocol.NetMessage, 4096),
-		peers:  make(map[string]net.Conn),
+		peers:  make(map[protocol.NodeID]peerConn),
+		dials:  make(map[string]*dialState),
 	}
 }
 
+// SetNodeID sets the identity this transport announces to peers. Set it
+// before Start (and before AddPeer).
+func (t *TCP) SetNodeID(id protocol.NodeID) {
+	t.mu.Lock()
+	t.self = id
+	t.mu.Unlock()
+}
+
+// OnPeer registers fn to hear when a node becomes reachable (up=true, once
+// its handshake completes) and when its connection drops.
+// fn runs on the connection's reader goroutine. Set it before Start.
+func (t *TCP) OnPeer(fn func(node protocol.NodeID, up bool)) { t.onPeer = fn }
+
+// SetInboxPolicy sets what a reader does when the inbox is full (see
+// InboxPolicy); the default blocks the connection. Set it before Start.
+func (t *TCP) SetInboxPolicy(p InboxPolicy) { t.policy = p }
+
+// InboxDropped counts the inbound messages the inbox policy has discarded.
+func (t *TCP) InboxDropped() uint64 { return t.dropped.Load() }
+
 func (t *TCP) Inbox() <-chan protocol.NetMessage  { return t.inbox }
 func (t *TCP) Outbox() chan<- protocol.NetMessage { return t.outbox }
+func (t *TCP) OutboxLen() int                     { return len(t.outbox) }
+func (t *TCP) OutboxCap() int                     { return cap(t.outbox) }
 
 func (t *TCP) Start(ctx context.Context) error {
 	ln, err := net.Listen("tcp", t.addr)
 	if err != nil {
 		return err
 	}
+	if t.tls != nil {
+		ln = tls.NewListener(ln, t.tls)
+	}
 	t.ln = ln
+	t.mu.Lock()
+	t.ctx = ctx
+	t.mu.Unlock()
 	log.Printf("tcp listening on %s", t.addr)
 
 	// accept loop
 	go func() {
 		for {
 			c, err := ln.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return // Close
+			}
 			if err != nil {
 				select {
 				case <-ctx.Done():
@@ -58,9 +143,7 @@ func (t *TCP) Start(ctx context.Context) error {
 				log.Printf("accept error: %v", err)
 				continue
 			}
-			addr := c.RemoteAddr().String()
-			t.addConn(addr, c)
-			go t.readLoop(ctx, c)
+			t.addConn(ctx, c, "")
 		}
 	}()
 
@@ -71,7 +154,9 @@ func (t *TCP) Start(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case msg := <-t.outbox:
-				t.broadcast(msg)
+				if msg.To == "" || t.SendTo(msg.To, msg) != nil {
+					t.broadcast(msg.Broadcastable())
+				}
 			}
 		}
 	}()
@@ -83,49 +168,248 @@ func (t *TCP) Close() error {
 		_ = t.ln.Close()
 	}
 	t.mu.Lock()
-	for _, c := range t.peers {
-		_ = c.Close()
+	t.closed = true
+	for _, p := range t.peers {
+		_ = p.conn.Close()
 	}
-	t.peers = map[string]net.Conn{}
+	t.peers = map[protocol.NodeID]peerConn{}
 	t.mu.Unlock()
 	return nil
 }
 
-// AddPeer dials a remote and registers it as a peer.
+// PeerAddrs returns the addresses of currently connected peers, sorted.
+func (t *TCP) PeerAddrs() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]string, 0, len(t.peers))
+	for _, p := range t.peers {
+		out = append(out, p.conn.RemoteAddr().String())
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Peers reports every connected peer and every dialed address that is down,
+// sorted by address.
+func (t *TCP) Peers() []PeerState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]PeerState, 0, len(t.peers)+len(t.dials))
+	dialedNode := make(map[protocol.NodeID]bool, len(t.dials))
+	for addr, d := range t.dials {
+		if d.node != "" {
+			dialedNode[d.node] = true
+		}
+		_, up := t.peers[d.node]
+		out = append(out, PeerState{Node: d.node, Addr: addr, Dialed: true, Connected: up && d.node != "", Attempts: d.attempts})
+	}
+	for id, p := range t.peers {
+		if !dialedNode[id] {
+			out = append(out, PeerState{Node: id, Addr: p.conn.RemoteAddr().String(), Connected: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Addr < out[j].Addr })
+	return out
+}
+
+// AddPeer dials a remote and registers it as a peer. The address is kept
+// and re-dialed whenever its connection drops, even if this first dial fails.
 func (t *TCP) AddPeer(addr string) error {
-	c, err := net.Dial("tcp", addr)
+	t.mu.Lock()
+	if t.dials[addr] == nil {
+		t.dials[addr] = &dialState{}
+	}
+	t.mu.Unlock()
+	err := t.dial(addr)
+	if err != nil {
+		t.lostDial(addr)
+	}
+	return err
+}
+
+func (t *TCP) dial(addr string) error {
+	var c net.Conn
+	var err error
+	if t.tls != nil {
+		c, err = tls.Dial("tcp", addr, t.tls)
+	} else {
+		c, err = net.Dial("tcp", addr)
+	}
 	if err != nil {
 		return err
 	}
-	t.addConn(addr, c)
-	go t.readLoop(context.Background(), c)
+	t.addConn(t.runCtx(), c, addr)
 	return nil
 }
 
-func (t *TCP) addConn(addr string, c net.Conn) {
+func (t *TCP) runCtx() context.Context {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+// lostDial starts redialing addr unless a redial loop already runs.
+func (t *TCP) lostDial(addr string) {
 	t.mu.Lock()
-	if old, ok := t.peers[addr]; ok {
-		_ = old.Close()
+	defer t.mu.Unlock()
+	d := t.dials[addr]
+	if d == nil || d.retrying || t.closed {
+		return
 	}
-	if tc, ok := c.(*net.TCPConn); ok {
+	d.retrying = true
+	go t.redial(addr)
+}
+
+// redial re-dials addr with backoff until a dial succeeds (the handshake then
+// runs as for any connection), the node is reachable over another connection,
+// or the transport is closed.
+func (t *TCP) redial(addr string) {
+	ctx := t.runCtx()
+	delay := redialMin
+	for {
+		jitter := time.Duration(rand.Int63n(int64(delay)/5*2+1)) - delay/5
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay + jitter):
+		}
+		t.mu.Lock()
+		d := t.dials[addr]
+		_, up := t.peers[d.node]
+		if t.closed || (d.node != "" && up) {
+			d.retrying = false
+			t.mu.Unlock()
+			return
+		}
+		d.retrying = false // a connection that drops after this dial restarts the loop
+		t.mu.Unlock()
+
+		err := t.dial(addr)
+		if err == nil {
+			return
+		}
+		t.mu.Lock()
+		if d.retrying || t.closed {
+			t.mu.Unlock()
+			return
+		}
+		d.retrying = true
+		d.attempts++
+		n := d.attempts
+		t.mu.Unlock()
+		log.Printf("redial %s failed (attempt %d): %v", addr, n, err)
+		delay = min(delay*2, redialMax)
+	}
+}
+
+// addConn starts reading a new connection, which first trades HELLOs; the
+// peer is registered once its own arrives (see register). dialAddr is the
+// address we dialed, empty for an accepted connection.
+func (t *TCP) addConn(ctx context.Context, c net.Conn, dialAddr string) {
+	raw := c
+	if tc, ok := c.(*tls.Conn); ok {
+		raw = tc.NetConn()
+	}
+	if tc, ok := raw.(*net.TCPConn); ok {
 		_ = tc.SetNoDelay(true)
 	}
-	t.peers[addr] = c
-	t.mu.Unlock()
-	log.Printf("peer connected: %s", addr)
+	go t.readLoop(ctx, c, dialAddr)
 }
 
-func (t *TCP) readLoop(ctx context.Context, c net.Conn) {
+// register adopts c as the connection to node unless an existing one wins the
+// tie-break, reporting whether c was kept and whether node is newly reachable.
+func (t *TCP) register(node protocol.NodeID, c net.Conn, dialed bool) (kept, up bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	dialer := node
+	if dialed {
+		dialer = t.self
+	}
+	old, exists := t.peers[node]
+	if exists && old.dialer != dialer && old.dialer == min(t.self, node) {
+		// both sides dialed: the smaller NodeID's connection stays
+		return false, false
+	}
+	if exists {
+		// the loser of a simultaneous dial, or a stale connection from a reconnect
+		_ = old.conn.Close()
+	}
+	t.peers[node] = peerConn{conn: c, dialer: dialer}
+	return true, !exists
+}
+
+func (t *TCP) readLoop(ctx context.Context, c net.Conn, dialAddr string) {
+	addr := c.RemoteAddr().String()
+	var node protocol.NodeID
 	defer func() {
-		addr := c.RemoteAddr().String()
+		if dialAddr != "" {
+			defer t.lostDial(dialAddr)
+		}
 		_ = c.Close()
 		t.mu.Lock()
-		delete(t.peers, addr)
+		p, ok := t.peers[node]
+		down := ok && p.conn == c // not replaced by a newer connection
+		if down {
+			delete(t.peers, node)
+		}
 		t.mu.Unlock()
-		log.Printf("peer disconnected: %s", addr)
+		if down {
+			log.Printf("peer disconnected: %s (%s)", node, addr)
+			if t.onPeer != nil {
+				t.onPeer(node, false)
+			}
+		}
 	}()
 
+	// On its own goroutine, so a slow peer (or TLS handshake) never holds up
+	// the accept loop.
+	t.mu.RLock()
+	frame, err := Encode(protocol.NetMessage{From: t.self, Type: protocol.MsgHello})
+	t.mu.RUnlock()
+	if err == nil {
+		_, err = c.Write(frame)
+	}
+	if err != nil {
+		log.Printf("handshake with %s failed: %v", addr, err)
+		return
+	}
 	r := bufio.NewReader(c)
+	hello, err := Decode(r)
+	if err != nil || hello.Type != protocol.MsgHello || hello.From == "" {
+		log.Printf("handshake with %s failed: no HELLO", addr)
+		return
+	}
+	t.mu.RLock()
+	self := t.self
+	t.mu.RUnlock()
+	if hello.From == self {
+		// ourselves, or another node with our id: its action ids would
+		// collide with ours
+		log.Printf("handshake with %s refused: peer claims our node id %s", addr, self)
+		return
+	}
+	kept, up := t.register(hello.From, c, dialAddr != "")
+	if dialAddr != "" {
+		t.mu.Lock()
+		if d := t.dials[dialAddr]; d != nil {
+			d.node = hello.From
+			d.attempts = 0
+		}
+		t.mu.Unlock()
+	}
+	if !kept {
+		log.Printf("peer %s (%s): dropping duplicate connection", hello.From, addr)
+		return
+	}
+	node = hello.From
+	log.Printf("peer connected: %s (%s)", node, addr)
+	if up && t.onPeer != nil {
+		t.onPeer(node, true)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -143,11 +427,27 @@ func (t *TCP) readLoop(ctx context.Context, c net.Conn) {
 				return
 			}
 			// deliver inbound message
-			t.inbox <- msg
+			Deliver(t.inbox, msg, t.policy, &t.dropped)
 		}
 	}
 }
 
+// SendTo writes msg on the connection to node.
+func (t *TCP) SendTo(node protocol.NodeID, msg protocol.NetMessage) error {
+	t.mu.RLock()
+	p, ok := t.peers[node]
+	t.mu.RUnlock()
+	if !ok {
+		return ErrUnknownNode
+	}
+	frame, err := Encode(msg)
+	if err != nil {
+		return err
+	}
+	_, err = p.conn.Write(frame)
+	return err
+}
+
 func (t *TCP) broadcast(msg protocol.NetMessage) {
 	frame, err := Encode(msg)
 	if err != nil {
@@ -157,8 +457,8 @@ func (t *TCP) broadcast(msg protocol.NetMessage) {
 	// snapshot of peers to avoid holding lock while writing
 	t.mu.RLock()
 	peers := make([]net.Conn, 0, len(t.peers))
-	for _, c := range t.peers {
-		peers = append(peers, c)
+	for _, p := range t.peers {
+		peers = append(peers, p.conn)
 	}
 	t.mu.RUnlock()
 	for _, c := range peers {
@@ -0,0 +1,180 @@
+package netx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+func startTCP(t *testing.T, ctx context.Context, id protocol.NodeID) *TCP {
+	t.Helper()
+	n := NewTCP("127.0.0.1:0")
+	n.SetNodeID(id)
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = n.Close() })
+	return n
+}
+
+func connected(n *TCP) int {
+	c := 0
+	for _, p := range n.Peers() {
+		if p.Connected {
+			c++
+		}
+	}
+	return c
+}
+
+func TestPeerWithOurNodeIDIsRefused(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := startTCP(t, ctx, "n-1")
+	twin := startTCP(t, ctx, "n-1")
+	other := startTCP(t, ctx, "n-2")
+
+	if err := twin.AddPeer(a.ln.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.AddPeer(a.ln.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for connected(other) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if connected(other) != 1 {
+		t.Fatal("a distinct node never connected")
+	}
+	for _, p := range a.Peers() {
+		if p.Connected && p.Node == "n-1" {
+			t.Fatal("accepted a peer with our own node id")
+		}
+	}
+	if connected(twin) != 0 {
+		t.Fatal("the twin registered a connection")
+	}
+}
+
+func TestOutboxLenCountsWhatIsNotYetSent(t *testing.T) {
+	n := NewTCP("127.0.0.1:0")
+	for i := 0; i < 3; i++ {
+		n.Outbox() <- protocol.NetMessage{Type: protocol.MsgHeartbeat}
+	}
+	if n.OutboxLen() != 3 || n.OutboxCap() != 4096 {
+		t.Fatalf("outbox %d/%d before the writer runs, want 3/4096", n.OutboxLen(), n.OutboxCap())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := n.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for n.OutboxLen() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if l := n.OutboxLen(); l != 0 {
+		t.Fatalf("%d messages still queued once the writer ran", l)
+	}
+}
+
+// dialerOf reports who dialed n's connection to node ("" if there is none).
+func dialerOf(n *TCP, node protocol.NodeID) protocol.NodeID {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.peers[node].dialer
+}
+
+func TestSimultaneousDialsKeepTheSmallerIDsConnection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := startTCP(t, ctx, "n-1")
+	b := startTCP(t, ctx, "n-2")
+
+	errs := make(chan error, 2)
+	go func() { errs <- a.AddPeer(b.ln.Addr().String()) }()
+	go func() { errs <- b.AddPeer(a.ln.Addr().String()) }()
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	settled := func() bool { return dialerOf(a, "n-2") == "n-1" && dialerOf(b, "n-1") == "n-1" }
+	deadline := time.Now().Add(2 * time.Second)
+	for !settled() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond) // let the losing connection close
+	if !settled() || len(a.PeerAddrs()) != 1 || len(b.PeerAddrs()) != 1 {
+		t.Fatalf("a keeps %v (dialed by %s), b keeps %v (dialed by %s); want one connection each, n-1's",
+			a.PeerAddrs(), dialerOf(a, "n-2"), b.PeerAddrs(), dialerOf(b, "n-1"))
+	}
+
+	// the kept connection carries unicast both ways, addressed by NodeID
+	if err := a.SendTo("n-2", protocol.NetMessage{From: "n-1", Type: protocol.MsgHeartbeat}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SendTo("n-1", protocol.NetMessage{From: "n-2", Type: protocol.MsgHeartbeat}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		to   *TCP
+		from protocol.NodeID
+	}{{b, "n-1"}, {a, "n-2"}} {
+		select {
+		case m := <-tc.to.Inbox():
+			if m.From != tc.from {
+				t.Fatalf("got a message from %s, want %s", m.From, tc.from)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("nothing from %s", tc.from)
+		}
+	}
+}
+
+func TestDroppedDialIsRedialedWhenTheListenerReturns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := startTCP(t, ctx, "n-1")
+	b := startTCP(t, ctx, "n-2")
+	addr := b.ln.Addr().String()
+	if err := a.AddPeer(addr); err != nil {
+		t.Fatal(err)
+	}
+	waitFor := func(msg string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: peers %+v", msg, a.Peers())
+			}
+		}
+	}
+	waitFor("never connected", func() bool { return connected(a) == 1 })
+
+	_ = b.Close()
+	waitFor("no failed redial", func() bool {
+		ps := a.Peers()
+		return len(ps) == 1 && !ps[0].Connected && ps[0].Dialed && ps[0].Attempts > 0
+	})
+	for _, p := range b.Peers() {
+		if p.Dialed {
+			t.Fatalf("the accepting side dials back: %+v", p)
+		}
+	}
+
+	back := NewTCP(addr)
+	back.SetNodeID("n-2")
+	if err := back.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer back.Close()
+	waitFor("never reconnected", func() bool { return connected(a) == 1 })
+	if ps := a.Peers(); ps[0].Node != "n-2" || ps[0].Attempts != 0 {
+		t.Fatalf("after the reconnect: %+v", ps[0])
+	}
+}
@@ -0,0 +1,44 @@
+package netx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+)
+
+// NewTLS is NewTCP over TLS: the listener and every dial are wrapped in cfg,
+// and the framing on top is unchanged. cfg needs a certificate to serve;
+// RootCAs (and ClientCAs, for mutual auth) decide which peers are trusted.
+func NewTLS(addr string, cfg *tls.Config) *TCP {
+	t := NewTCP(addr)
+	t.tls = cfg
+	return t
+}
+
+// LoadTLSConfig builds a config serving certFile/keyFile. Peers are verified
+// against the PEM bundle caFile, both as servers and as clients; with no
+// caFile the link is encrypted but peers are not authenticated.
+func LoadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if caFile == "" {
+		cfg.InsecureSkipVerify = true
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("tls: no certificates in " + caFile)
+	}
+	cfg.RootCAs = pool
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
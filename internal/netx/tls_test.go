@@ -0,0 +1,116 @@
+package netx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+// selfSigned writes a self-signed certificate for 127.0.0.1, and its key, to
+// dir, returning their paths. The certificate is its own CA.
+func selfSigned(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "p2poker test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	for path, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return certFile, keyFile
+}
+
+func TestTLSCarriesFramesBetweenVerifiedPeers(t *testing.T) {
+	certFile, keyFile := selfSigned(t, t.TempDir())
+	cfg, err := LoadTLSConfig(certFile, keyFile, certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := func(id protocol.NodeID) *TCP {
+		n := NewTLS("127.0.0.1:0", cfg)
+		n.SetNodeID(id)
+		if err := n.Start(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	a, b := start("n-1"), start("n-2")
+	defer a.Close()
+	if err := a.AddPeer(b.ln.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for connected(a) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := a.SendTo("n-2", protocol.NetMessage{From: "n-1", Type: protocol.MsgHeartbeat, Seq: 7}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-b.Inbox():
+		if m.From != "n-1" || m.Seq != 7 {
+			t.Fatalf("received %+v", m)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("nothing arrived over TLS")
+	}
+
+	// a plaintext peer gets no handshake through
+	plain := startTCP(t, ctx, "n-3")
+	if err := plain.AddPeer(b.ln.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if connected(plain) != 0 {
+		t.Fatal("a plaintext peer connected to a TLS listener")
+	}
+
+	// closing drops the peer on the other end, as over plain TCP
+	_ = b.Close()
+	deadline = time.Now().Add(2 * time.Second)
+	for connected(a) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if connected(a) != 0 {
+		t.Fatalf("a still connected after b closed: %+v", a.Peers())
+	}
+}
@@ -5,6 +5,7 @@ type ActionType string
 const (
 	ActCreateTable ActionType = "CREATE_TABLE"
 	ActJoin        ActionType = "JOIN"
+	ActObserve     ActionType = "OBSERVE" // watch without a seat: never in Order, never dealt in; LEAVE ends it
 	ActLeave       ActionType = "LEAVE"
 	ActStartHand   ActionType = "START_HAND"
 	ActBet         ActionType = "BET"
@@ -15,6 +16,17 @@ const (
 	ActKick        ActionType = "KICK"
 	ActAdvance     ActionType = "ADVANCE_PHASE"
 	ActShowdown    ActionType = "SHOWDOWN"
+	ActCloseTable  ActionType = "CLOSE_TABLE"
+	ActAutoCheck   ActionType = "AUTO_CHECK" // Meta["on"]: bool (default true)
+	ActReset       ActionType = "RESET"      // Meta["keep_seats"]: bool (default false)
+	ActRebuy       ActionType = "REBUY"      // Amount: chips to add (0 = the table's minimum buy-in)
+	ActSitOut      ActionType = "SIT_OUT"    // keep the seat but skip hands; Meta["reason"]: string (optional)
+	ActSitIn       ActionType = "SIT_IN"
+	ActBlindLevel  ActionType = "BLIND_LEVEL" // Amount: the level to move to (index into TableConfig.BlindSchedule)
+	ActSeedCommit  ActionType = "SEED_COMMIT" // Meta["hash"]: string, engine.SeedCommitment of the player's secret seed
+	ActSeedReveal  ActionType = "SEED_REVEAL" // Meta["seed"]: string, the committed seed in decimal
+	ActShow        ActionType = "SHOW"        // show this hand at showdown even if it loses
+	ActMuck        ActionType = "MUCK"        // muck this hand at showdown unless it wins
 )
 
 type Action struct {
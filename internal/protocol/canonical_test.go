@@ -0,0 +1,34 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalIgnoresMetaOrder(t *testing.T) {
+	// the same message, decoded from wire bytes with Meta in different orders
+	var a, b NetMessage
+	if err := json.Unmarshal([]byte(`{"type":"PROPOSE","action":{"id":"x","type":"SHOW","meta":{"z":1,"cards":["As","Kd"],"a":{"y":true,"b":2.50}}}}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"action":{"meta":{"a":{"b":2.50,"y":true},"cards":["As","Kd"],"z":1},"type":"SHOW","id":"x"},"type":"PROPOSE"}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	ca, err := Canonical(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := Canonical(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ca, cb) {
+		t.Fatalf("canonical encodings differ:\n%s\n%s", ca, cb)
+	}
+
+	b.Action.Meta["z"] = 2.0
+	if cb, _ = Canonical(b); bytes.Equal(ca, cb) {
+		t.Fatal("a changed Meta encodes the same")
+	}
+}
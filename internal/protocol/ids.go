@@ -1,16 +1,117 @@
 package protocol
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type NodeID string
 
 type TableID string
 
-func NewNodeID() NodeID   { return NodeID(fmt.Sprintf("n-%d", rand.Int63())) }
-func NewTableID() TableID { return TableID(fmt.Sprintf("t-%d", rand.Int63())) }
+// IDGen derives node, table and action ids and shuffle seeds from a single
+// randomness source. Nodes own one; seeding it with a fixed math/rand source
+// makes a whole cluster reproducible.
+type IDGen struct {
+	mu sync.Mutex
+	r  *rand.Rand
+
+	origin NodeID // set: action ids are <origin>-<counter>
+	next   uint64
+}
+
+// NewIDGen wraps src. A nil src selects crypto-strength randomness (production default).
+func NewIDGen(src rand.Source) *IDGen {
+	if src == nil {
+		src = cryptoSource{}
+	}
+	return &IDGen{r: rand.New(src)}
+}
+
+func (g *IDGen) int63() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.r.Int63()
+}
+
+func (g *IDGen) NodeID() NodeID   { return NodeID(fmt.Sprintf("n-%d", g.int63())) }
+func (g *IDGen) TableID() TableID { return TableID(fmt.Sprintf("t-%d", g.int63())) }
+
+// SetOrigin makes action ids <id>-<counter>: unique across nodes, monotonic
+// per node, and telling at a glance who proposed what.
+func (g *IDGen) SetOrigin(id NodeID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.origin = id
+}
+
+// Counter is the last action id counter handed out; Resume continues from it
+// (a migrated node must not reissue ids its tables have already seen).
+func (g *IDGen) Counter() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.next
+}
+
+// Resume continues action ids for origin after counter.
+func (g *IDGen) Resume(origin NodeID, counter uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.origin, g.next = origin, counter
+}
+
+// Observe moves the counter past id when it is one of this origin's, so ids
+// already in a replayed log are never issued again.
+func (g *IDGen) Observe(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	rest, ok := strings.CutPrefix(id, string(g.origin)+"-")
+	if g.origin == "" || !ok {
+		return
+	}
+	if n, err := strconv.ParseUint(rest, 10, 64); err == nil && n > g.next {
+		g.next = n
+	}
+}
+
+// ActionID generates an action id for deduplication: <origin>-<counter> once
+// an origin is set, otherwise a random a-<n>.
+func (g *IDGen) ActionID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.origin == "" {
+		return fmt.Sprintf("a-%d", g.r.Int63())
+	}
+	g.next++
+	return fmt.Sprintf("%s-%d", g.origin, g.next)
+}
+
+// Seed draws a shuffle seed. Action ids are predictable, so decks are seeded
+// from here instead.
+func (g *IDGen) Seed() int64 { return g.int63() }
+
+var defaultIDs = NewIDGen(nil)
+
+func NewNodeID() NodeID   { return defaultIDs.NodeID() }
+func NewTableID() TableID { return defaultIDs.TableID() }
 
 // RandActionID generates a unique-ish action id for deduplication.
-func RandActionID() string { return fmt.Sprintf("a-%d", rand.Int63()) }
+func RandActionID() string { return defaultIDs.ActionID() }
+
+// cryptoSource is a math/rand Source backed by crypto/rand. Seed is a no-op.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 { return int64(cryptoSource{}.Uint64() &^ (1 << 63)) }
+func (cryptoSource) Seed(int64)   {}
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
@@ -0,0 +1,43 @@
+package protocol
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestActionIDsAreUniqueAcrossNodesAndMonotonicPerNode(t *testing.T) {
+	seen := map[string]NodeID{}
+	for _, node := range []NodeID{"n-1", "n-2", "n-12"} {
+		g := NewIDGen(rand.NewSource(1)) // same source: only the origin tells them apart
+		g.SetOrigin(node)
+		var last uint64
+		for i := 0; i < 100; i++ {
+			id := g.ActionID()
+			if other, dup := seen[id]; dup {
+				t.Fatalf("%s issued %s, already issued by %s", node, id, other)
+			}
+			seen[id] = node
+			rest, ok := strings.CutPrefix(id, string(node)+"-")
+			if !ok {
+				t.Fatalf("id %s does not name its origin %s", id, node)
+			}
+			n, err := strconv.ParseUint(rest, 10, 64)
+			if err != nil || n <= last {
+				t.Fatalf("%s: counter %q after %d", id, rest, last)
+			}
+			last = n
+		}
+	}
+}
+
+func TestObserveSkipsIDsAlreadyIssued(t *testing.T) {
+	g := NewIDGen(rand.NewSource(1))
+	g.SetOrigin("n-1")
+	g.Observe("n-1-41")
+	g.Observe("n-2-99") // someone else's
+	if id := g.ActionID(); id != "n-1-42" {
+		t.Fatalf("got %s, want n-1-42", id)
+	}
+}
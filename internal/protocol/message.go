@@ -8,6 +8,8 @@ const (
 	MsgSnapshot   MsgType = "SNAPSHOT"
 	MsgStateQuery MsgType = "STATE_QUERY"
 	MsgHeartbeat  MsgType = "HEARTBEAT"
+	MsgReject     MsgType = "REJECT" // authority NACKs the proposal in Action, addressed To its proposer
+	MsgHello      MsgType = "HELLO"  // transport handshake: the first frame each side of a connection sends, From its NodeID
 )
 
 type NetMessage struct {
@@ -19,4 +21,33 @@ type NetMessage struct {
 	Seq     uint64         `json:"seq"`
 	Action  *Action        `json:"action,omitempty"`
 	State   *TableSnapshot `json:"state,omitempty"`
+
+	// TurnDeadline is when the current actor must act, in unix ms on the
+	// authority's clock (0 = no turn timer). Set on commits and heartbeats.
+	TurnDeadline int64 `json:"turn_deadline,omitempty"`
+
+	// StateHash is the authority's engine Hash as of Seq, on commits and
+	// heartbeats; a follower at the same seq with a different hash has diverged.
+	StateHash uint64 `json:"state_hash,omitempty"`
+
+	// To addresses a message to one node (a reject's proposer, a snapshot's
+	// requester); empty broadcasts. Reason says why a proposal was rejected.
+	To     NodeID `json:"to,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// Fallback is the State to send in place of State should a message
+	// addressed To one node have to be broadcast because that node cannot be
+	// reached directly; a snapshot carrying the node's hole cards falls back
+	// to the hole-free one. Local to this process: it is never encoded.
+	Fallback *TableSnapshot `json:"-"`
+}
+
+// Broadcastable returns msg as it may be sent to every node: with State
+// swapped for Fallback when one is set.
+func (msg NetMessage) Broadcastable() NetMessage {
+	if msg.Fallback != nil {
+		msg.State = msg.Fallback
+	}
+	msg.Fallback = nil
+	return msg
 }
@@ -13,6 +13,18 @@ type TableSnapshot struct {
 	Epoch     Epoch             `json:"epoch"`
 	Authority NodeID            `json:"authority"`
 
+	// table-level seating state
+	Bans      []string `json:"bans,omitempty"`
+	Waiting   []string `json:"waiting,omitempty"`
+	Observers []string `json:"observers,omitempty"`
+	Level     int      `json:"level,omitempty"` // current index into Cfg.BlindSchedule
+
+	// commit-reveal shuffle round (Cfg.CommitReveal)
+	SeedRound   uint64            `json:"seed_round,omitempty"`
+	SeedCommits map[string]string `json:"seed_commits,omitempty"`
+	SeedReveals map[string]int64  `json:"seed_reveals,omitempty"`
+	SeedBarred  []string          `json:"seed_barred,omitempty"`
+
 	// engine snapshot payload as JSON to avoid protocol↔engine import cycles.
 	EngineJSON json.RawMessage `json:"engine,omitempty"`
 }
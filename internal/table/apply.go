@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"sort"
+	"time"
 
 	"p2poker/internal/engine"
 	"p2poker/internal/protocol"
@@ -24,17 +26,24 @@ func dealerTag(s *engine.State, pid string) string {
 	if pid == "" || len(s.Order) == 0 {
 		return ""
 	}
-	if pid == s.Order[s.DealerIdx] {
+	if pid == s.Dealer() {
 		return " (dealer)"
 	}
 	return ""
 }
 
-func (t *Table) apply(a protocol.Action) {
+// apply executes a committed action against the engine. The returned error
+// means the local engine refused it — on a follower that signals divergence.
+func (t *Table) apply(a protocol.Action) error {
 	var err error
 	announceTurn := false
 	announceStart := false
 	announcePhase := false
+	inHand := t.eng.HandActive // counted in handActions once it applies
+	var paid int64             // chips a betting action puts in, for PLAYER_ACTED
+	if st, ok := t.eng.Seats[a.PlayerID]; ok {
+		paid = -st.TotalCommitted
+	}
 
 	switch a.Type {
 	case protocol.ActCreateTable:
@@ -43,38 +52,171 @@ func (t *Table) apply(a protocol.Action) {
 	case protocol.ActJoin:
 		// idempotent join: ignore if already seated
 		if _, ok := t.eng.Seats[a.PlayerID]; ok {
-			return
+			return nil
+		}
+		if err = t.seedBarErr(a.PlayerID); err != nil {
+			break
+		}
+		if err = t.joinErr(protocol.NodeID(a.PlayerID)); err != nil {
+			if errors.Is(err, engine.ErrTableFull) && !contains(t.waiting, a.PlayerID) {
+				t.waiting = append(t.waiting, a.PlayerID)
+				log.Printf("table %s: %s added to waiting list (position %d)", t.id, a.PlayerID, len(t.waiting))
+				return nil
+			}
+			break
 		}
-		err = t.eng.Sit(a.PlayerID, t.cfg.MinBuyin)
+		// Amount carries the buy-in the player chose (0 = the minimum), or
+		// the stack of a player moved from another table, which keeps it
+		buyin := t.cfg.MinBuyin
+		if a.Amount > 0 {
+			buyin = a.Amount
+		}
+		if _, moved := a.Meta["moved_from"]; moved {
+			err = t.eng.SitStack(a.PlayerID, buyin)
+		} else {
+			err = t.eng.Sit(a.PlayerID, buyin)
+		}
+		if err == nil {
+			t.chipsIn += buyin
+			removeStr(&t.observers, a.PlayerID)
+		}
+
+	case protocol.ActObserve:
+		err = t.observe(a.PlayerID)
 
 	case protocol.ActLeave:
-		t.eng.Leave(a.PlayerID)
+		if _, moving := a.Meta["moved_to"]; moving {
+			if err = t.moveErr(a); err != nil {
+				break
+			}
+		}
+		t.leave(a.PlayerID)
 		announceTurn = true
 
 	case protocol.ActKick:
 		if a.Meta != nil {
 			if tv, ok := a.Meta["target"]; ok {
 				if target, ok := tv.(string); ok {
-					t.eng.Leave(target)
+					t.leave(target)
+					t.bans[target] = struct{}{}
 					announceTurn = true
 				}
 			}
 		}
 
+	case protocol.ActCloseTable:
+		// Final stacks for the record; the table is torn down after broadcast.
+		for _, pid := range t.eng.Order {
+			if st, ok := t.eng.Seats[pid]; ok {
+				log.Printf("table %s: final stack %s=%d", t.id, pid, st.Stack)
+			}
+		}
+		log.Printf("table %s: closed by %s", t.id, a.PlayerID)
+		t.closing = true
+		return nil
+
+	case protocol.ActReset:
+		keep, _ := a.Meta["keep_seats"].(bool)
+		if err = t.reset(keep); err == nil {
+			log.Printf("table %s: reset by %s (keep seats=%v)", t.id, a.PlayerID, keep)
+		}
+
 	case protocol.ActStartHand:
-		seed := seedFromActionID(a.ID)
+		seed := handSeed(a)
+		if t.cfg.CommitReveal {
+			if seed, err = t.dealSeed(); err != nil {
+				break
+			}
+		}
 		r := rand.New(rand.NewSource(seed))
+		t.useLevel()
 		err = t.eng.StartHand(r)
 		announceStart = err == nil
 		announceTurn = err == nil
 
 		if err == nil {
+			if t.cfg.CommitReveal {
+				t.clearSeeds()
+				t.seedBarred = nil
+			}
+			t.handSeed = seed
+			t.handActions = 0
+			t.handPlayers = t.handPlayers[:0]
+			for _, pid := range t.eng.Order {
+				if t.eng.Seats[pid].InHand {
+					t.handPlayers = append(t.handPlayers, pid)
+				}
+			}
+
 			// Local-only: show my hole cards (not broadcast; every node prints its own)
-			if hc, ok := t.eng.Holes[string(t.self)]; ok && len(hc) == 2 {
-				log.Printf("table %s: your hole cards: %s %s", t.id, hc[0].String(), hc[1].String())
+			if hc, ok := t.eng.Holes[string(t.self)]; ok && len(hc) > 0 {
+				t.logf("table %s: your hole cards: %s", t.id, engine.FormatCards(hc))
+			}
+			if t.cfg.OpenCards {
+				for _, pid := range t.eng.Order {
+					if hc := t.eng.Holes[pid]; len(hc) > 0 {
+						t.logf("table %s: open cards: %s has %s", t.id, pid, engine.FormatCards(hc))
+					}
+				}
+			}
+		}
+
+	case protocol.ActRebuy:
+		amt := a.Amount
+		if amt == 0 {
+			amt = t.cfg.MinBuyin
+		}
+		var before int64
+		if st, ok := t.eng.Seats[a.PlayerID]; ok {
+			before = st.Stack
+		}
+		if err = t.eng.AddChips(a.PlayerID, amt); err == nil {
+			added := t.eng.Seats[a.PlayerID].Stack - before
+			t.chipsIn += added
+			if added < amt {
+				log.Printf("table %s: %s rebought %d (capped at the max buy-in %d)", t.id, a.PlayerID, added, t.eng.MaxBuyin)
+			} else {
+				log.Printf("table %s: %s rebought %d", t.id, a.PlayerID, added)
 			}
 		}
 
+	case protocol.ActBlindLevel:
+		err = t.setLevel(int(a.Amount))
+
+	case protocol.ActShow, protocol.ActMuck:
+		err = t.eng.SetReveal(a.PlayerID, a.Type == protocol.ActShow)
+
+	case protocol.ActSeedCommit:
+		err = t.commitSeed(a)
+
+	case protocol.ActSeedReveal:
+		err = t.revealSeed(a)
+
+	case protocol.ActSitOut, protocol.ActSitIn:
+		out := a.Type == protocol.ActSitOut
+		if !out {
+			if err = t.seedBarErr(a.PlayerID); err != nil {
+				break
+			}
+		}
+		if err = t.eng.SetSittingOut(a.PlayerID, out); err == nil {
+			if a.Meta["reason"] == reasonSeedForfeit {
+				t.forfeitSeed(a.PlayerID)
+			}
+			if reason, ok := a.Meta["reason"].(string); ok {
+				log.Printf("table %s: %s sitting out=%v (%s)", t.id, a.PlayerID, out, reason)
+			} else {
+				log.Printf("table %s: %s sitting out=%v", t.id, a.PlayerID, out)
+			}
+		}
+
+	case protocol.ActAutoCheck:
+		on := true
+		if v, ok := a.Meta["on"].(bool); ok {
+			on = v
+		}
+		err = t.eng.SetAutoCheck(a.PlayerID, on)
+
 	case protocol.ActCheck:
 		err = t.eng.Check(a.PlayerID)
 		announceTurn = err == nil
@@ -85,8 +227,11 @@ func (t *Table) apply(a protocol.Action) {
 
 	case protocol.ActCall:
 		err = t.eng.Call(a.PlayerID)
+		announceTurn = err == nil
 
 	case protocol.ActRaise:
+		// errors return before any announcement, so flag unconditionally
+		announceTurn = true
 		st, ok := t.eng.Seats[a.PlayerID]
 		if !ok {
 			err = errors.New("unknown player")
@@ -121,6 +266,7 @@ func (t *Table) apply(a protocol.Action) {
 	case protocol.ActBet:
 		if t.eng.CurrentBet == 0 {
 			err = t.eng.Bet(a.PlayerID, a.Amount)
+			announceTurn = err == nil
 		} else {
 			ra := protocol.Action{
 				ID:       a.ID,
@@ -129,23 +275,22 @@ func (t *Table) apply(a protocol.Action) {
 				Amount:   a.Amount,
 				Meta:     a.Meta,
 			}
-			t.apply(ra)
-			return
+			return t.apply(ra)
 		}
 
 	case protocol.ActAdvance:
+		before := len(t.eng.Board)
 		t.eng.AdvancePhase()
 		announcePhase = true
 		announceTurn = true
+		if dealt := t.eng.Board[before:]; len(dealt) > 0 {
+			t.emit(TableEvent{Kind: EvBoardChanged, Phase: t.eng.Phase.String(), Pot: t.eng.Pot,
+				Cards: append([]engine.Card{}, dealt...), Board: append([]engine.Card{}, t.eng.Board...)})
+		}
 
 		// If we just moved into showdown, resolve immediately (authority only)
 		if t.authority && (&t.eng).Phase == engine.PhaseShowdown {
-			sh := protocol.Action{
-				ID:       protocol.RandActionID(),
-				Type:     protocol.ActShowdown,
-				PlayerID: string(t.self),
-			}
-			t.commitAndBroadcast(sh)
+			t.followup(protocol.ActShowdown, string(t.self))
 			// No need to announceTurn after showdown.
 			announceTurn = false
 		}
@@ -154,62 +299,209 @@ func (t *Table) apply(a protocol.Action) {
 		// Resolve payouts & end hand
 		sum := (&t.eng).ResolveShowdown()
 		if len(sum.Winners) == 0 {
-			log.Printf("table %s: showdown: no eligible winners; pot carried was 0", t.id)
+			t.logf("table %s: showdown: no eligible winners; pot carried was 0", t.id)
 		} else {
 			// Log winners (could be multiple on a tie)
 			for _, w := range sum.Winners {
-				// Pretty print 5-card hand
-				cards := fmt.Sprintf("%s %s %s %s %s", w.Cards[0].String(), w.Cards[1].String(), w.Cards[2].String(), w.Cards[3].String(), w.Cards[4].String())
-				log.Printf("table %s: winner %s — %s [%v] +%d",
-					t.id, w.Player, w.Value.Cat.String(), cards, sum.PayoutPer)
+				t.logf("table %s: winner %s — %s [%s] +%d",
+					t.id, w.Player, w.Value.Cat.String(), engine.FormatCards(w.Cards), w.Won)
 			}
 		}
+		t.handOver(sum)
 	}
 
 	if err != nil {
 		log.Printf("engine apply error: action=%s player=%s err=%v", a.Type, a.PlayerID, err)
-		return
+		return err
+	}
+	t.restartSeeds()
+	if inHand && a.Type != protocol.ActSeedCommit && a.Type != protocol.ActSeedReveal {
+		t.handActions++ // seeds for the next deal are not part of this hand
+	}
+	switch a.Type {
+	case protocol.ActCheck, protocol.ActCall, protocol.ActBet, protocol.ActRaise, protocol.ActFold:
+		if st, ok := t.eng.Seats[a.PlayerID]; ok {
+			paid += st.TotalCommitted
+		}
+		t.emit(TableEvent{Kind: EvPlayerActed, Phase: t.eng.Phase.String(), Pot: t.eng.Pot, Player: a.PlayerID, Action: a.Type, Amount: paid})
+	}
+
+	// Everyone else folded: the hand ends here, with no more cards dealt.
+	if pid, ok := t.eng.OnlyOneInHand(); ok {
+		sum := t.eng.AwardUncontested(pid)
+		t.logf("table %s: %s wins %d uncontested", t.id, pid, sum.TotalPayout)
+		t.handOver(sum)
+		announceTurn = false
+		announcePhase = false
+	}
+
+	if t.authority {
+		t.refreshDeadline(announceStart)
 	}
 
 	if announceStart {
 		cur := t.eng.CurrentPlayer()
 		dealer := dealerOf(&t.eng)
-		log.Printf("table %s: hand started (SB=%d, BB=%d), dealer=%s%s, turn=%s%s%s",
-			t.id, t.cfg.SmallBlind, t.cfg.BigBlind,
+		t.logf("table %s: hand started (SB=%d, BB=%d), dealer=%s%s, turn=%s%s%s",
+			t.id, t.eng.SmallBlind, t.eng.BigBlind,
 			dealer, dealerTag(&t.eng, dealer),
 			cur, allInTag(&t.eng, cur), dealerTag(&t.eng, cur),
 		)
+		ev := TableEvent{Kind: EvHandStarted, Phase: t.eng.Phase.String(), Pot: t.eng.Pot, Dealer: dealer, Turn: cur, Deadline: t.turnDeadline}
+		if t.cfg.OpenCards {
+			ev.Holes = t.openHoles()
+		}
+		t.emit(ev)
 	}
 
 	if announcePhase {
 		cur := t.eng.CurrentPlayer()
-		log.Printf("table %s: phase advanced to %s, turn=%s%s%s",
+		t.logf("table %s: phase advanced to %s, turn=%s%s%s",
 			t.id, (&t.eng).Phase.String(),
 			cur, allInTag(&t.eng, cur), dealerTag(&t.eng, cur),
 		)
+		t.emit(TableEvent{Kind: EvPhaseAdvanced, Phase: t.eng.Phase.String(), Pot: t.eng.Pot, Dealer: dealerOf(&t.eng), Turn: cur, Deadline: t.turnDeadline})
 	}
 
 	if announceTurn {
 		cur := t.eng.CurrentPlayer()
-		log.Printf("table %s: phase=%s pot=%d turn=%s%s%s",
+		t.logf("table %s: phase=%s pot=%d turn=%s%s%s%s",
 			t.id, (&t.eng).Phase.String(), (&t.eng).Pot,
-			cur, allInTag(&t.eng, cur), dealerTag(&t.eng, cur),
+			cur, allInTag(&t.eng, cur), dealerTag(&t.eng, cur), deadlineTag(t.turnDeadline),
 		)
+		t.emit(TableEvent{Kind: EvTurnChanged, Phase: t.eng.Phase.String(), Pot: t.eng.Pot, Dealer: dealerOf(&t.eng), Turn: cur, Deadline: t.turnDeadline})
 	}
 
-	if t.authority && t.eng.HandActive && t.eng.RoundClosed() && a.Type != protocol.ActAdvance {
-		adv := protocol.Action{
-			ID:       protocol.RandActionID(),
-			Type:     protocol.ActAdvance,
-			PlayerID: string(t.self),
+	if t.authority && t.eng.HandActive {
+		if t.eng.RoundClosed() {
+			// all-in run-out: deal street after street straight to showdown
+			if a.Type != protocol.ActAdvance || t.eng.NoMoreBetting() {
+				t.followup(protocol.ActAdvance, string(t.self))
+			}
+		} else if pid, ok := t.eng.AutoCheckDue(); ok {
+			t.followup(protocol.ActCheck, pid)
 		}
-		t.commitAndBroadcast(adv)
 	}
+	if t.authority && a.Type != protocol.ActStartHand {
+		t.maybeAutoStart()
+	}
+	return nil
 }
 
-func dealerOf(s *engine.State) string {
-	if len(s.Order) == 0 {
+// maybeAutoStart deals the next hand when the table runs itself (MinPlayers
+// set) and enough players have chips; otherwise the table sits paused until a
+// join brings it back to strength. Authority only.
+func (t *Table) maybeAutoStart() {
+	if t.cfg.MinPlayers == 0 || t.closing || t.eng.HandActive {
+		return
+	}
+	for _, f := range t.followups {
+		if f.Type == protocol.ActStartHand {
+			return
+		}
+	}
+	if t.eng.CanStart() {
+		if t.paused {
+			log.Printf("table %s: resuming, %d players seated", t.id, len(t.eng.Order))
+		}
+		t.paused = false
+		t.followup(protocol.ActStartHand, string(t.self))
+		return
+	}
+	if !t.paused {
+		log.Printf("table %s: paused, waiting for %d players", t.id, t.cfg.MinPlayers)
+		t.paused = true
+	}
+}
+
+// leave removes a player; their stack walks away with them (chips already in
+// the pot stay there).
+func (t *Table) leave(pid string) {
+	removeStr(&t.waiting, pid)
+	removeStr(&t.observers, pid)
+	st, ok := t.eng.Seats[pid]
+	if !ok {
+		return
+	}
+	t.chipsIn -= st.Stack
+	t.eng.Leave(pid)
+	t.seatFromWaitlist()
+}
+
+// checkChips verifies chip conservation: everything on the table must equal
+// what was bought in minus what left. Any drift points at a payout bug.
+func (t *Table) checkChips() error {
+	if total := t.eng.TotalChips(); total != t.chipsIn {
+		return fmt.Errorf("chip accounting drift: on table=%d, bought in=%d (diff %+d)", total, t.chipsIn, total-t.chipsIn)
+	}
+	return nil
+}
+
+// handOver reports a finished hand: the showdown event, busts, and where each
+// pot went.
+func (t *Table) handOver(sum engine.ShowdownSummary) {
+	if sum.UncalledReturn > 0 {
+		t.logf("table %s: uncalled %d returned to %s", t.id, sum.UncalledReturn, sum.UncalledTo)
+	}
+	// only cards a player showed are logged; mucked ones stay private
+	for _, pid := range t.eng.Order {
+		if show, ok := sum.Revealed[pid]; ok && show {
+			t.logf("table %s: %s shows %s", t.id, pid, engine.FormatCards(sum.Shown[pid]))
+		} else if ok {
+			t.logf("table %s: %s mucks", t.id, pid)
+		}
+	}
+	t.emit(TableEvent{Kind: EvShowdown, Phase: t.eng.Phase.String(), Showdown: &sum})
+	t.announceBusts()
+	for i, p := range sum.Pots {
+		if len(p.LowWinners) > 0 {
+			t.logf("table %s: pot %d: %d split high %v / low %v", t.id, i, p.Amount, p.Winners, p.LowWinners)
+			continue
+		}
+		t.logf("table %s: pot %d: %d to %v", t.id, i, p.Amount, p.Winners)
+	}
+	if cerr := t.checkChips(); cerr != nil {
+		log.Printf("table %s: %v", t.id, cerr)
+	}
+	if t.cfg.HandLog == HandLogSummary {
+		t.logSummary(sum)
+	}
+	// Events never carry the seed before the hand is over. The committed
+	// START_HAND does: nodes replay the deal from it.
+	if t.cfg.RevealSeed {
+		t.emit(TableEvent{Kind: EvSeedRevealed, Seed: t.handSeed})
+	}
+}
+
+// announceBusts emits PLAYER_BUSTED for everyone who ended the hand with no
+// chips. Several busting in one hand are emitted smallest starting stack first,
+// i.e. in finishing order from the bottom.
+func (t *Table) announceBusts() {
+	var busted []*engine.Seat
+	for _, pid := range t.eng.Order {
+		if st := t.eng.Seats[pid]; st.Stack == 0 && st.TotalCommitted > 0 {
+			busted = append(busted, st)
+		}
+	}
+	sort.SliceStable(busted, func(i, j int) bool { return busted[i].TotalCommitted < busted[j].TotalCommitted })
+	players := make([]string, 0, len(busted))
+	for _, st := range busted {
+		log.Printf("table %s: %s busted", t.id, st.Player)
+		t.emit(TableEvent{Kind: EvPlayerBusted, Player: st.Player})
+		players = append(players, st.Player)
+	}
+	if t.onBust != nil && len(players) > 0 {
+		t.onBust(players)
+	}
+}
+
+func deadlineTag(d time.Time) string {
+	if d.IsZero() {
 		return ""
 	}
-	return s.Order[s.DealerIdx]
+	return fmt.Sprintf(" (%s to act)", time.Until(d).Round(time.Second))
+}
+
+func dealerOf(s *engine.State) string {
+	return s.Dealer() // "" under a dead button
 }
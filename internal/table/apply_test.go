@@ -0,0 +1,101 @@
+package table
+
+import (
+	"slices"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestHandEndsWhenEveryoneElseFolds(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c", "d")
+	h.must(protocol.ActStartHand, "me", 0)
+	folded := map[string]bool{}
+	for range 3 {
+		folded[h.actTurn(protocol.ActFold, 0)] = true
+	}
+
+	var active bool
+	var board int
+	var phase engine.Phase
+	h.on(func(tb *Table) {
+		active, board, phase = tb.eng.HandActive, len(tb.eng.Board), tb.eng.Phase
+	})
+	if active || board != 0 || phase != engine.PhasePreflop {
+		t.Fatalf("after three folds: hand active %v, %d board cards, %s; want it over preflop", active, board, phase)
+	}
+	for _, m := range h.sentOf(protocol.MsgCommit) {
+		if m.Action.Type == protocol.ActAdvance {
+			t.Fatalf("committed %s after the hand was decided", m.Action.Type)
+		}
+	}
+	// the big blind takes the blinds: its own 2 back and the small blind's 1
+	for _, p := range []string{"a", "b", "c", "d"} {
+		if !folded[p] {
+			if st := h.seat(p); st.Stack != 101 {
+				t.Fatalf("winner %s has %d, want 101", p, st.Stack)
+			}
+		}
+	}
+}
+
+func TestAllInPreflopRunsOutTheBoard(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	boards := h.tb.Subscribe(EvBoardChanged)
+	showdowns := h.tb.Subscribe(EvShowdown)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActRaise, 100) // all in
+	h.actTurn(protocol.ActCall, 0)
+
+	var dealt []int
+	for _, ev := range drainEvents(boards) {
+		dealt = append(dealt, len(ev.Cards))
+	}
+	if !slices.Equal(dealt, []int{3, 1, 1}) {
+		t.Fatalf("dealt %v after the shove was called, want the flop, turn and river", dealt)
+	}
+	advances := 0
+	for _, m := range h.sentOf(protocol.MsgCommit) {
+		if m.Action.Type == protocol.ActAdvance {
+			advances++
+		}
+	}
+	if advances != 4 { // flop, turn, river, showdown
+		t.Fatalf("committed %d ADVANCE_PHASEs, want 4", advances)
+	}
+	evs := drainEvents(showdowns)
+	if len(evs) != 1 || evs[0].Showdown.TotalPayout != 200 {
+		t.Fatalf("showdown events %+v, want one paying out 200", evs)
+	}
+	if a, b := h.seat("a").Stack, h.seat("b").Stack; a+b != 200 || (a != 200 && b != 200 && a != b) {
+		t.Fatalf("stacks %d / %d after the showdown", a, b)
+	}
+}
+
+func TestSittingOutPlayerIsDealtBackInAfterSittingIn(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	h.must(protocol.ActSitOut, "c", 0)
+
+	dealt := func() (in bool, holes int) {
+		h.on(func(tb *Table) { in, holes = tb.eng.Seats["c"].InHand, len(tb.eng.Holes["c"]) })
+		return
+	}
+	h.must(protocol.ActStartHand, "me", 0)
+	if in, holes := dealt(); in || holes != 0 {
+		t.Fatalf("sitting out: c in hand %v with %d cards", in, holes)
+	}
+	h.actTurn(protocol.ActFold, 0)
+	if st := h.seat("c"); st.Stack != 100 || !st.SittingOut {
+		t.Fatalf("c after the hand it sat out: %+v", st)
+	}
+
+	h.must(protocol.ActSitIn, "c", 0)
+	h.must(protocol.ActStartHand, "me", 0)
+	if in, holes := dealt(); !in || holes != 2 {
+		t.Fatalf("sat back in: c in hand %v with %d cards, want dealt 2", in, holes)
+	}
+}
@@ -0,0 +1,60 @@
+package table
+
+import (
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func (h *harness) phase() engine.Phase {
+	var p engine.Phase
+	h.on(func(tb *Table) { p = tb.eng.Phase })
+	return p
+}
+
+func (h *harness) current() string {
+	var p string
+	h.on(func(tb *Table) { p = tb.eng.CurrentPlayer() })
+	return p
+}
+
+func TestAutoCheckSkipsABetStreetOnly(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	h.must(protocol.ActAutoCheck, "a", 0)
+	h.must(protocol.ActAutoCheck, "b", 0)
+	h.must(protocol.ActStartHand, "me", 0)
+	for h.phase() == engine.PhasePreflop {
+		if p := h.current(); p == "c" || h.seat(p).Committed < 2 {
+			h.actTurn(protocol.ActCall, 0)
+		} else {
+			h.actTurn(protocol.ActCheck, 0)
+		}
+	}
+
+	// flop: a and b check without being asked, so the action is on c
+	if h.phase() != engine.PhaseFlop || h.current() != "c" {
+		t.Fatalf("on the flop %v with %q to act, want c", h.phase(), h.current())
+	}
+	h.must(protocol.ActBet, "c", 4)
+	// the bet is theirs to answer: nobody is checked (or folded) for
+	for i := 0; i < 2; i++ {
+		p := h.current()
+		if p != "a" && p != "b" {
+			t.Fatalf("%q to act facing the bet", p)
+		}
+		if st := h.seat(p); st.Folded || st.Committed != 0 {
+			t.Fatalf("%s acted for on a bet street: %+v", p, st)
+		}
+		h.actTurn(protocol.ActCall, 0)
+	}
+
+	// turn: the bet overrode the opt-in for the flop only
+	if h.phase() != engine.PhaseTurn || h.current() != "c" {
+		t.Fatalf("on the turn %v with %q to act, want c", h.phase(), h.current())
+	}
+	if !h.seat("a").AutoCheck || !h.seat("b").AutoCheck {
+		t.Fatal("a bet cleared the auto-check opt-ins")
+	}
+}
@@ -0,0 +1,28 @@
+package table
+
+import (
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+func TestTableWaitsForMinPlayersThenDealsItself(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinPlayers = 3
+	h := newHarness(t, cfg)
+	h.join("a", "b")
+	if err := h.do(protocol.ActStartHand, "me", 0); err == nil {
+		t.Fatal("dealt to two players at a three-player minimum")
+	}
+	var active, paused bool
+	h.on(func(tb *Table) { active, paused = tb.eng.HandActive, tb.paused })
+	if active || !paused {
+		t.Fatalf("with two seated: hand active %v, paused %v; want a paused table", active, paused)
+	}
+
+	h.join("c")
+	h.on(func(tb *Table) { active, paused = tb.eng.HandActive, tb.paused })
+	if !active || paused {
+		t.Fatalf("with three seated: hand active %v, paused %v; want it dealt", active, paused)
+	}
+}
@@ -0,0 +1,40 @@
+package table
+
+import (
+	"log"
+
+	"p2poker/internal/protocol"
+)
+
+// PlayerDisconnected sits a seated player out when their connection drops, so
+// the table deals around them instead of timing out their turns hand after
+// hand. Authority only; followers ignore it.
+func (t *Table) PlayerDisconnected(player string) {
+	t.exec(func() {
+		st, ok := t.eng.Seats[player]
+		if !t.authority || !ok || st.SittingOut {
+			return
+		}
+		t.dropped[player] = struct{}{}
+		log.Printf("table %s: %s disconnected; sitting them out", t.id, player)
+		a := t.localAction(protocol.ActSitOut, player, 0)
+		a.Meta = map[string]any{"reason": "disconnected"}
+		t.commitAndBroadcast(a)
+	})
+}
+
+// PlayerReconnected sits back in a player PlayerDisconnected sat out. Players
+// who sat out on their own stay out.
+func (t *Table) PlayerReconnected(player string) {
+	t.exec(func() {
+		if _, ok := t.dropped[player]; !ok || !t.authority {
+			return
+		}
+		delete(t.dropped, player)
+		if _, seated := t.eng.Seats[player]; !seated {
+			return
+		}
+		log.Printf("table %s: %s reconnected; sitting them back in", t.id, player)
+		t.commitAndBroadcast(t.localAction(protocol.ActSitIn, player, 0))
+	})
+}
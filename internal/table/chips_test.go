@@ -0,0 +1,79 @@
+package table
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+func TestChipsAreConservedOverAHand(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActRaise, 4)
+	h.actTurn(protocol.ActFold, 0)
+	h.actTurn(protocol.ActFold, 0)
+
+	var (
+		active bool
+		total  int64
+		drift  error
+	)
+	h.on(func(tb *Table) {
+		active, total, drift = tb.eng.HandActive, tb.eng.TotalChips(), tb.checkChips()
+	})
+	if active {
+		t.Fatal("hand still running")
+	}
+	if total != 300 {
+		t.Errorf("%d chips on the table, want the 300 bought in", total)
+	}
+	if drift != nil {
+		t.Error(drift)
+	}
+}
+
+// lockedBuffer collects log output written from the table loop.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (w *lockedBuffer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *lockedBuffer) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+func TestChipDriftIsDetected(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	var drift error
+	h.on(func(tb *Table) {
+		tb.eng.Seats["a"].Stack += 5 // chips from nowhere
+		drift = tb.checkChips()
+	})
+	if drift == nil || !strings.Contains(drift.Error(), "+5") {
+		t.Errorf("drift of +5 reported as %v", drift)
+	}
+
+	var buf lockedBuffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActFold, 0)
+	if !strings.Contains(buf.String(), "chip accounting drift") {
+		t.Fatalf("the hand ended without reporting the drift:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,40 @@
+package table
+
+import "time"
+
+// deadlineTimer waits for a deadline on one reusable time.Timer, re-armed
+// only when the deadline moves, rather than a fresh time.After on every pass
+// of the Run loop.
+type deadlineTimer struct {
+	timer *time.Timer
+	at    time.Time // the deadline armed; zero when idle or spent
+}
+
+// wait returns a channel that fires at the deadline at (counted from now), or
+// nil, which never fires, for a zero deadline.
+func (d *deadlineTimer) wait(at, now time.Time) <-chan time.Time {
+	if at.IsZero() {
+		d.stop()
+		return nil
+	}
+	if !at.Equal(d.at) {
+		if d.timer == nil {
+			d.timer = time.NewTimer(at.Sub(now))
+		} else {
+			d.timer.Reset(at.Sub(now))
+		}
+		d.at = at
+	}
+	return d.timer.C
+}
+
+// fired marks the armed deadline spent: if it is still pending on the next
+// wait (the handler found it early, by the table's clock), it is armed again.
+func (d *deadlineTimer) fired() { d.at = time.Time{} }
+
+func (d *deadlineTimer) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.at = time.Time{}
+}
@@ -0,0 +1,66 @@
+package table
+
+import "p2poker/internal/protocol"
+
+// maxLogLen bounds the in-memory action log. Past it the oldest half is
+// dropped and their ids pruned from dedup; logBase records the last seq
+// discarded (or covered by an installed snapshot).
+const maxLogLen = 4096
+
+// record appends a just-applied action (at t.seq) to the log and dedup set,
+// compacting both once the log outgrows maxLogLen, and to the WAL if open.
+func (t *Table) record(a protocol.Action) {
+	t.log = append(t.log, a)
+	t.dedup[a.ID] = t.seq
+	t.walCommit(a)
+	t.settle(a.ID, nil)
+	if len(t.log) > maxLogLen {
+		drop := len(t.log) / 2
+		t.log = append(t.log[:0:0], t.log[drop:]...)
+		t.compactTo(t.seq - uint64(len(t.log)))
+	}
+}
+
+// compactTo forgets dedup entries at or below seq. A proposal made against a
+// seq that old is refused by seq instead (see behind), so pruning never lets
+// it apply twice.
+func (t *Table) compactTo(seq uint64) {
+	if seq <= t.logBase {
+		return
+	}
+	t.logBase = seq
+	for id, s := range t.dedup {
+		if s <= seq {
+			delete(t.dedup, id)
+		}
+	}
+}
+
+// duplicate reports whether an action with this id was already applied.
+func (t *Table) duplicate(id string) bool {
+	_, ok := t.dedup[id]
+	return ok
+}
+
+// behind reports whether a proposal was made against a seq the log has since
+// compacted away: possibly a peer re-sending, after reconnecting, an action
+// whose id dedup no longer holds. It is refused with reasonStale rather than
+// dropped, so the proposer learns to catch up first. Seq 0 is a node that has
+// no state yet (just attached, see cluster.Node.DiscoverAndAttach), which
+// cannot be re-sending anything it saw applied.
+func (t *Table) behind(seq uint64) bool {
+	return seq > 0 && seq < t.logBase
+}
+
+// collides reports whether a reuses the id of a different committed action:
+// an id collision (say, a node whose counter restarted) rather than a re-send
+// of the same action, which dedup rightly swallows. The seed the authority
+// stamps on a START_HAND is not part of the comparison.
+func (t *Table) collides(a protocol.Action) bool {
+	seq, ok := t.dedup[a.ID]
+	if !ok || seq <= t.logBase || seq-t.logBase > uint64(len(t.log)) {
+		return false
+	}
+	c := t.log[seq-t.logBase-1]
+	return c.Type != a.Type || c.PlayerID != a.PlayerID || c.Amount != a.Amount
+}
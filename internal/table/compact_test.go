@@ -0,0 +1,116 @@
+package table
+
+import (
+	"slices"
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+// compacted seats a and b, then forgets the log up to the current seq, as
+// record does once the log outgrows maxLogLen.
+func compacted(t *testing.T) (*harness, protocol.Action) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	rebuy := protocol.Action{ID: "peer-rebuy", Type: protocol.ActRebuy, PlayerID: "a", Amount: 50}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Seq: 3, Action: &rebuy})
+	h.must(protocol.ActSitOut, "b", 0)
+	h.must(protocol.ActSitIn, "b", 0)
+	h.on(func(tb *Table) { tb.compactTo(tb.seq) })
+	return h, rebuy
+}
+
+func TestResentActionFromBeforeCompactionIsNotReapplied(t *testing.T) {
+	h, rebuy := compacted(t)
+	if got := h.seat("a").Stack; got != 150 {
+		t.Fatalf("rebuy not applied once: stack %d", got)
+	}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Seq: 3, Action: &rebuy})
+	if got := h.seat("a").Stack; got != 150 {
+		t.Fatalf("re-sent rebuy applied again: stack %d", got)
+	}
+	rejects := h.sentOf(protocol.MsgReject)
+	if len(rejects) != 1 || rejects[0].Reason != reasonStale || rejects[0].To != "a" {
+		t.Fatalf("want a stale REJECT to a, got %+v", rejects)
+	}
+}
+
+func TestDuplicateStillInDedupIsDroppedQuietly(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a")
+	rebuy := protocol.Action{ID: "peer-rebuy", Type: protocol.ActRebuy, PlayerID: "a", Amount: 50}
+	for range 2 {
+		h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Seq: 2, Action: &rebuy})
+	}
+	if got := h.seat("a").Stack; got != 150 {
+		t.Fatalf("stack %d, want one rebuy", got)
+	}
+	if r := h.sentOf(protocol.MsgReject); len(r) != 0 {
+		t.Fatalf("duplicate was rejected: %+v", r)
+	}
+}
+
+func TestNewNodeCanJoinAfterCompaction(t *testing.T) {
+	h, _ := compacted(t)
+	join := protocol.Action{ID: "new-1", Type: protocol.ActJoin, PlayerID: "new"}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "new", Seq: 0, Action: &join})
+	if h.seat("new") == nil {
+		t.Fatalf("fresh node's JOIN (seq 0) dropped after compaction; sent %+v", h.sentOf(protocol.MsgReject))
+	}
+}
+
+func TestStaleRejectMakesTheFollowerResync(t *testing.T) {
+	h := newHarnessAs(t, testConfig(), "f", false)
+	h.on(func(tb *Table) { tb.authorityID = "auth" })
+	a := protocol.Action{ID: "f-1", Type: protocol.ActRebuy, PlayerID: "f"}
+	h.recv(protocol.NetMessage{Type: protocol.MsgReject, From: "auth", To: "f", Action: &a, Reason: reasonStale})
+	if q := h.sentOf(protocol.MsgStateQuery); len(q) != 1 {
+		t.Fatalf("want a state query after a stale reject, got %d", len(q))
+	}
+	if p := h.sentOf(protocol.MsgPropose); len(p) != 0 {
+		t.Fatalf("stale proposal re-sent automatically: %+v", p)
+	}
+}
+
+func TestReusedActionIDIsRejectedAndReproposed(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a")
+	rebuy := protocol.Action{ID: "a-1", Type: protocol.ActRebuy, PlayerID: "a", Amount: 50}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Seq: 1, Action: &rebuy})
+	again := protocol.Action{ID: "a-1", Type: protocol.ActRebuy, PlayerID: "a", Amount: 20} // same id, new action
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Seq: 2, Action: &again})
+	if got := h.seat("a").Stack; got != 150 {
+		t.Fatalf("stack %d, want only the first rebuy", got)
+	}
+	rejects := h.sentOf(protocol.MsgReject)
+	if len(rejects) != 1 || rejects[0].Reason != reasonCollision || rejects[0].To != "a" || rejects[0].Action.Amount != 20 {
+		t.Fatalf("want a collision REJECT of the second rebuy to a, got %+v", rejects)
+	}
+
+	// the proposer, its id counter restarted, retries until an id is free
+	f := newHarnessAs(t, testConfig(), "a", false)
+	f.on(func(tb *Table) {
+		tb.authorityID = "me"
+		tb.ids.SetOrigin("a") // a-1, a-2, ...
+	})
+	f.recv(rejects[0])
+	var ids []string
+	for i := 0; len(h.sentOf(protocol.MsgReject)) > i; i++ {
+		props := f.sentOf(protocol.MsgPropose)
+		if len(props) != i+1 || i > 2 {
+			t.Fatalf("after %d rejects: proposed %d times", i+1, len(props))
+		}
+		p := props[i]
+		ids = append(ids, p.Action.ID)
+		h.recv(p)
+		if r := h.sentOf(protocol.MsgReject); len(r) > i+1 {
+			f.recv(r[i+1])
+		}
+	}
+	if !slices.Equal(ids, []string{"a-1", "a-2"}) {
+		t.Fatalf("re-proposed as %v, want a-1 (taken) then a-2", ids)
+	}
+	if got := h.seat("a").Stack; got != 170 {
+		t.Fatalf("stack %d after the re-proposal, want 170", got)
+	}
+}
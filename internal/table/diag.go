@@ -0,0 +1,42 @@
+package table
+
+import (
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// recentLogSize is how many trailing committed actions a Diag carries.
+const recentLogSize = 20
+
+// Diag is a read-only diagnostic view of one table.
+type Diag struct {
+	ID          protocol.TableID  `json:"id"`
+	Seq         uint64            `json:"seq"`
+	Epoch       protocol.Epoch    `json:"epoch"`
+	Authority   protocol.NodeID   `json:"authority"`
+	IsAuthority bool              `json:"is_authority"`
+	Summary     engine.Summary    `json:"summary"`
+	RecentLog   []protocol.Action `json:"recent_log"`
+}
+
+// Diagnostics gathers a Diag on the table loop, so it never races with apply.
+// ok is false if the table has been stopped.
+func (t *Table) Diagnostics() (d Diag, ok bool) {
+	t.exec(func() {
+		from := 0
+		if len(t.log) > recentLogSize {
+			from = len(t.log) - recentLogSize
+		}
+		d = Diag{
+			ID:          t.id,
+			Seq:         t.seq,
+			Epoch:       t.epoch,
+			Authority:   t.authorityID,
+			IsAuthority: t.authority,
+			Summary:     t.eng.Summary(),
+			RecentLog:   append([]protocol.Action{}, t.log[from:]...),
+		}
+		ok = true
+	})
+	return d, ok
+}
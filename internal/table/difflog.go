@@ -0,0 +1,67 @@
+package table
+
+import (
+	"bytes"
+
+	"p2poker/internal/protocol"
+)
+
+// LogExport is a table's retained action log, as exported for offline
+// comparison. Actions[i] was committed at seq Base+i+1 (earlier entries may
+// have been compacted away).
+type LogExport struct {
+	Table   protocol.TableID
+	Node    protocol.NodeID
+	Base    uint64
+	Actions []protocol.Action
+}
+
+// Log exports the applied history this node still holds.
+func (t *Table) Log() LogExport {
+	var ex LogExport
+	t.exec(func() {
+		ex = LogExport{Table: t.id, Node: t.self, Base: t.logBase, Actions: append([]protocol.Action{}, t.log...)}
+	})
+	return ex
+}
+
+// DiffLogs compares two applied histories that both start at seq 1 and
+// returns the seq of the first entry that differs. ok is true when no entry
+// differs; a log that is a prefix of the other only lags and still agrees.
+func DiffLogs(a, b []protocol.Action) (firstDivergeSeq int, ok bool) {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if !sameAction(a[i], b[i]) {
+			return i + 1, false
+		}
+	}
+	return 0, true
+}
+
+// DiffExports aligns two exports on seq (skipping what either has compacted)
+// and reports the first seq at which they disagree.
+func DiffExports(a, b LogExport) (seq uint64, ok bool) {
+	base := max(a.Base, b.Base)
+	as, bs := trimTo(a, base), trimTo(b, base)
+	i, ok := DiffLogs(as, bs)
+	if ok {
+		return 0, true
+	}
+	return base + uint64(i), false
+}
+
+func trimTo(ex LogExport, base uint64) []protocol.Action {
+	skip := base - ex.Base
+	if skip >= uint64(len(ex.Actions)) {
+		return nil
+	}
+	return ex.Actions[skip:]
+}
+
+// sameAction compares canonical encodings, so Meta maps and numbers that went
+// through a JSON round-trip on one node compare equal to the in-process form.
+func sameAction(a, b protocol.Action) bool {
+	ca, errA := protocol.Canonical(a)
+	cb, errB := protocol.Canonical(b)
+	return errA == nil && errB == nil && bytes.Equal(ca, cb)
+}
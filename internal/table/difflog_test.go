@@ -0,0 +1,47 @@
+package table
+
+import (
+	"encoding/json"
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+func TestDiffLogsFindsTheFirstDivergence(t *testing.T) {
+	shared := []protocol.Action{
+		{ID: "1", Type: protocol.ActJoin, PlayerID: "a"},
+		{ID: "2", Type: protocol.ActJoin, PlayerID: "b"},
+		{ID: "3", Type: protocol.ActStartHand, PlayerID: "me", Meta: map[string]any{"seed": 7}},
+	}
+	a := append(append([]protocol.Action{}, shared...),
+		protocol.Action{ID: "4", Type: protocol.ActCall, PlayerID: "a"},
+		protocol.Action{ID: "5", Type: protocol.ActCheck, PlayerID: "b"})
+	b := append(append([]protocol.Action{}, shared...),
+		protocol.Action{ID: "4", Type: protocol.ActFold, PlayerID: "a"})
+
+	if seq, ok := DiffLogs(a, b); ok || seq != 4 {
+		t.Fatalf("diverged at seq %d (ok %v), want 4", seq, ok)
+	}
+	if seq, ok := DiffLogs(a, a[:4]); !ok || seq != 0 {
+		t.Fatalf("a lagging copy diverged at seq %d", seq)
+	}
+
+	// the same history after a trip over the wire still agrees
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wire []protocol.Action
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatal(err)
+	}
+	if seq, ok := DiffLogs(a, wire); !ok {
+		t.Fatalf("decoded copy diverged at seq %d", seq)
+	}
+
+	// exports line up on seq when one node has compacted its first two entries
+	seq, ok := DiffExports(LogExport{Actions: a}, LogExport{Base: 2, Actions: b[2:]})
+	if ok || seq != 4 {
+		t.Fatalf("exports diverged at seq %d (ok %v), want 4", seq, ok)
+	}
+}
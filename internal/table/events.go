@@ -0,0 +1,118 @@
+package table
+
+import (
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// EventKind classifies a TableEvent.
+type EventKind string
+
+const (
+	EvHandStarted      EventKind = "HAND_STARTED"
+	EvPlayerActed      EventKind = "PLAYER_ACTED" // Player took Action, putting Amount chips in
+	EvPhaseAdvanced    EventKind = "PHASE_ADVANCED"
+	EvTurnChanged      EventKind = "TURN_CHANGED"
+	EvShowdown         EventKind = "SHOWDOWN"
+	EvPlayerBusted     EventKind = "PLAYER_BUSTED"     // Player lost their last chip
+	EvRabbitHunt       EventKind = "RABBIT_HUNT"       // Cards: the undealt board, display only
+	EvBoardChanged     EventKind = "BOARD_CHANGED"     // Cards: just dealt (flop 3, turn 1, river 1); Board: all of it
+	EvSeedRevealed     EventKind = "SEED_REVEALED"     // Seed: the finished hand's shuffle seed (RevealSeed tables)
+	EvAuthorityChanged EventKind = "AUTHORITY_CHANGED" // Player: the node now committing for the table
+)
+
+// TableEvent is a structured notification for UIs, emitted as commits are
+// applied. Fields that don't apply to Kind are left zero.
+type TableEvent struct {
+	Kind     EventKind
+	Table    protocol.TableID
+	Seq      uint64
+	Phase    string
+	Pot      int64
+	Dealer   string
+	Turn     string
+	Deadline time.Time           // when Turn must act, per the authority's clock (zero = no turn timer)
+	Player   string              // subject of player-specific events
+	Action   protocol.ActionType `json:",omitempty"` // PLAYER_ACTED: CHECK, CALL, BET, RAISE or FOLD
+	Amount   int64               `json:",omitempty"` // PLAYER_ACTED: chips the action put in
+	Cards    []engine.Card
+	Board    []engine.Card                     `json:",omitempty"` // BOARD_CHANGED: the full board after the deal
+	Holes    map[engine.PlayerID][]engine.Card `json:",omitempty"` // HAND_STARTED at OpenCards tables
+	Seed     int64                             `json:",omitempty"` // SEED_REVEALED: see engine.DeckFromSeed
+
+	Showdown *engine.ShowdownSummary `json:",omitempty"`
+}
+
+// Events returns the table's event stream. The channel is buffered and never
+// blocks the table loop: events are dropped while it is full.
+func (t *Table) Events() <-chan TableEvent { return t.events }
+
+// subscriptionBuffer is each subscriber's own buffer.
+const subscriptionBuffer = 64
+
+type subscription struct {
+	kind EventKind // "" = every kind
+	ch   chan TableEvent
+}
+
+// Subscribe returns a new stream carrying only events of kind (every kind when
+// kind is ""). Each subscriber has its own buffer; a slow one misses events
+// rather than holding up the table or other subscribers. The channel is closed
+// when the table stops.
+func (t *Table) Subscribe(kind EventKind) <-chan TableEvent {
+	ch := make(chan TableEvent, subscriptionBuffer)
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	if t.ended {
+		close(ch)
+		return ch
+	}
+	t.subs = append(t.subs, subscription{kind: kind, ch: ch})
+	return ch
+}
+
+// Unsubscribe ends a stream Subscribe returned, closing it. Streams of a
+// stopped table are already closed.
+func (t *Table) Unsubscribe(evs <-chan TableEvent) {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	for i, s := range t.subs {
+		if s.ch == evs {
+			close(s.ch)
+			t.subs = append(t.subs[:i], t.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *Table) closeSubscriptions() {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	for _, s := range t.subs {
+		close(s.ch)
+	}
+	t.subs = nil
+	t.ended = true
+}
+
+func (t *Table) emit(ev TableEvent) {
+	ev.Table = t.id
+	ev.Seq = t.seq
+	select {
+	case t.events <- ev:
+	default:
+	}
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	for _, s := range t.subs {
+		if s.kind != "" && s.kind != ev.Kind {
+			continue
+		}
+		select {
+		case s.ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package table
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// drainEvents returns what evs holds now.
+func drainEvents(evs <-chan TableEvent) []TableEvent {
+	var out []TableEvent
+	for {
+		select {
+		case ev, ok := <-evs:
+			if !ok {
+				return out
+			}
+			out = append(out, ev)
+		default:
+			return out
+		}
+	}
+}
+
+func TestAHandEmitsItsEventsInOrder(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	evs := h.tb.Subscribe("")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	for h.phase() != engine.PhaseShowdown && h.current() != "" {
+		h.actTurn(protocol.ActCheck, 0)
+	}
+
+	var kinds []EventKind
+	acted := 0
+	for _, ev := range drainEvents(evs) {
+		switch ev.Kind {
+		case EvHandStarted, EvPhaseAdvanced, EvShowdown:
+			kinds = append(kinds, ev.Kind)
+		case EvPlayerActed:
+			acted++
+		}
+	}
+	want := []EventKind{EvHandStarted, EvPhaseAdvanced, EvPhaseAdvanced, EvPhaseAdvanced, EvPhaseAdvanced, EvShowdown}
+	if !slices.Equal(kinds, want) {
+		t.Fatalf("events %v, want %v", kinds, want)
+	}
+	if acted != 8 { // a call and a check preflop, two checks on each later street
+		t.Fatalf("%d PLAYER_ACTED events, want 8", acted)
+	}
+}
+
+func TestUnsubscribeEndsTheStream(t *testing.T) {
+	h := newHarness(t, testConfig())
+	evs := h.tb.Subscribe("")
+	kept := h.tb.Subscribe(EvHandStarted)
+	h.tb.Unsubscribe(evs)
+	if _, ok := <-evs; ok {
+		t.Fatal("stream still open after Unsubscribe")
+	}
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	if got := drainEvents(kept); len(got) != 1 || got[0].Kind != EvHandStarted {
+		t.Fatalf("other subscriber got %+v", got)
+	}
+	h.tb.Unsubscribe(evs) // already gone: a no-op
+}
+
+func TestBoardEventsCarryTheNewCards(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	boards := h.tb.Subscribe(EvBoardChanged)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	for h.phase() != engine.PhaseShowdown && h.current() != "" {
+		h.actTurn(protocol.ActCheck, 0)
+	}
+
+	evs := drainEvents(boards)
+	if len(evs) != 3 {
+		t.Fatalf("%d BOARD_CHANGED events, want flop, turn and river", len(evs))
+	}
+	var board []engine.Card
+	for i, n := range []int{3, 1, 1} {
+		board = append(board, evs[i].Cards...)
+		if len(evs[i].Cards) != n || !slices.Equal(evs[i].Board, board) {
+			t.Fatalf("deal %d: new %v, board %v; want %d new cards on %v", i, evs[i].Cards, evs[i].Board, n, board)
+		}
+	}
+}
+
+func TestCallsBetsAndRaisesMoveTheTurn(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	turns := h.tb.Subscribe(EvTurnChanged)
+	h.must(protocol.ActStartHand, "me", 0)
+	drainEvents(turns)
+
+	for _, step := range []struct {
+		typ    protocol.ActionType
+		amount int64
+	}{
+		{protocol.ActCall, 0},
+		{protocol.ActRaise, 4},
+		{protocol.ActCall, 0},
+		{protocol.ActCall, 0}, // closes preflop: a second TURN_CHANGED follows the flop
+		{protocol.ActBet, 5},
+	} {
+		p := h.actTurn(step.typ, step.amount)
+		next := h.current()
+		evs := drainEvents(turns)
+		if len(evs) == 0 || evs[len(evs)-1].Turn != next {
+			t.Fatalf("after %s's %s: %+v, want a TURN_CHANGED to %s", p, step.typ, evs, next)
+		}
+	}
+}
+
+func TestShowdownEventDetailsEachSidePot(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.must(protocol.ActJoin, "a", 100)
+	h.must(protocol.ActJoin, "b", 150)
+	h.must(protocol.ActJoin, "c", 200)
+	showdowns := h.tb.Subscribe(EvShowdown)
+	h.must(protocol.ActStartHand, "me", 0)
+	for active := true; active; h.on(func(tb *Table) { active = tb.eng.HandActive }) {
+		st := h.seat(h.current())
+		h.actTurn(protocol.ActRaise, st.Committed+st.Stack) // all in, or a call when it cannot raise
+	}
+
+	evs := drainEvents(showdowns)
+	if len(evs) != 1 || evs[0].Showdown == nil {
+		t.Fatalf("showdown events %+v", evs)
+	}
+	sum := evs[0].Showdown
+	if sum.UncalledReturn != 50 || sum.UncalledTo != "c" {
+		t.Errorf("returned %d to %s, want c's uncalled 50", sum.UncalledReturn, sum.UncalledTo)
+	}
+	want := []struct {
+		amount int64
+		in     []string
+	}{
+		{300, []string{"a", "b", "c"}}, // a's 100 from everyone
+		{100, []string{"b", "c"}},      // b's next 50, matched by c
+	}
+	if len(sum.Pots) != len(want) {
+		t.Fatalf("%d pots, want a main and a side pot: %+v", len(sum.Pots), sum.Pots)
+	}
+
+	// the winners must be the best hands among each pot's players
+	var board []engine.Card
+	var holes map[engine.PlayerID][]engine.Card
+	h.on(func(tb *Table) { board, holes = slices.Clone(tb.eng.Board), maps.Clone(tb.eng.Holes) })
+	if len(board) != 5 {
+		t.Fatalf("board %v after the run-out", board)
+	}
+	for i, w := range want {
+		p := sum.Pots[i]
+		if p.Amount != w.amount || !slices.Equal(p.Contributors, w.in) || !slices.Equal(p.Eligible, w.in) {
+			t.Fatalf("pot %d: %d from %v, eligible %v; want %d from and to %v", i, p.Amount, p.Contributors, p.Eligible, w.amount, w.in)
+		}
+		var best []string
+		var top engine.HandValue
+		for _, pid := range w.in {
+			v, _ := engine.EvaluatorFor("").Best(board, holes[pid])
+			switch {
+			case best == nil || top.Less(v):
+				best, top = []string{pid}, v
+			case !v.Less(top):
+				best = append(best, pid)
+			}
+		}
+		if !slices.Equal(p.Winners, best) {
+			t.Errorf("pot %d won by %v, want %v", i, p.Winners, best)
+		}
+	}
+}
+
+func TestSubscribersGetOnlyTheirKinds(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	starts := h.tb.Subscribe(EvHandStarted)
+	results := h.tb.Subscribe(EvShowdown)
+	for hand := 0; hand < 2; hand++ {
+		h.must(protocol.ActStartHand, "me", 0)
+		h.actTurn(protocol.ActFold, 0)
+	}
+
+	for kind, evs := range map[EventKind]<-chan TableEvent{EvHandStarted: starts, EvShowdown: results} {
+		got := drainEvents(evs)
+		if len(got) != 2 {
+			t.Errorf("%s subscriber got %d events, want one per hand", kind, len(got))
+		}
+		for _, ev := range got {
+			if ev.Kind != kind {
+				t.Errorf("%s subscriber got a %s", kind, ev.Kind)
+			}
+		}
+	}
+}
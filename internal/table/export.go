@@ -0,0 +1,59 @@
+package table
+
+import (
+	"errors"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// Export is a table's complete local state — every hole card and the rest of
+// the deck included — for stopping a node and resuming it elsewhere. It holds
+// secrets: persist it locally, never send it to peers.
+type Export struct {
+	ID        protocol.TableID
+	Authority bool
+	Snapshot  protocol.TableSnapshot // engine payload is a FullSnapshot
+	Deck      []engine.Card
+	Log       LogExport
+	ChipsIn   int64
+	Paused    bool
+}
+
+// Export captures the table's state between commits.
+func (t *Table) Export() Export {
+	var ex Export
+	t.exec(func() {
+		ex = Export{
+			ID:        t.id,
+			Authority: t.authority,
+			Snapshot:  t.buildSnapshot(t.eng.FullSnapshot()),
+			Deck:      append([]engine.Card{}, t.eng.Deck...),
+			Log:       LogExport{Table: t.id, Node: t.self, Base: t.logBase, Actions: append([]protocol.Action{}, t.log...)},
+			ChipsIn:   t.chipsIn,
+			Paused:    t.paused,
+		}
+	})
+	return ex
+}
+
+// Restore loads an Export into a table built with New for the same id. Call
+// it before Run.
+func (t *Table) Restore(ex Export) error {
+	if ex.ID != t.id {
+		return errors.New("restore: export is for another table")
+	}
+	if err := t.installSnapshot(ex.Snapshot); err != nil {
+		return err
+	}
+	t.eng.Deck = append([]engine.Card{}, ex.Deck...)
+	t.chipsIn = ex.ChipsIn
+	t.paused = ex.Paused
+	t.log = append(t.log[:0], ex.Log.Actions...)
+	t.logBase = ex.Log.Base
+	t.dedup = make(map[string]uint64, len(t.log))
+	for i, a := range t.log {
+		t.dedup[a.ID] = t.logBase + uint64(i) + 1
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package table
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"p2poker/internal/engine"
+)
+
+// Hand logging (TableConfig.HandLog).
+const (
+	HandLogVerbose = "verbose" // default: a line per action, turn and street
+	HandLogSummary = "summary" // one line per hand, written when it ends
+)
+
+// logf logs per-action detail, which summary mode folds into the single
+// end-of-hand line (see logSummary).
+func (t *Table) logf(format string, args ...any) {
+	if t.cfg.HandLog == HandLogSummary {
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// logSummary writes the one line summary mode keeps of a hand: who played,
+// the pot, who won what, the board and how many actions it took.
+func (t *Table) logSummary(sum engine.ShowdownSummary) {
+	winners := make([]string, 0, len(sum.Winners))
+	for _, w := range sum.Winners {
+		winners = append(winners, fmt.Sprintf("%s+%d", w.Player, w.Won))
+	}
+	log.Printf("table %s: hand over: players=[%s] pot=%d winners=[%s] board=[%s] actions=%d",
+		t.id, strings.Join(t.handPlayers, " "), sum.TotalPayout,
+		strings.Join(winners, " "), engine.FormatCards(t.eng.Board), t.handActions)
+}
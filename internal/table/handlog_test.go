@@ -0,0 +1,54 @@
+package table
+
+import (
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+// handLines plays a limped heads-up hand to showdown in the given HandLog
+// mode, with this node in it, and returns what was logged from the deal to
+// the end.
+func handLines(t *testing.T, mode string) []string {
+	t.Helper()
+	cfg := testConfig()
+	cfg.HandLog = mode
+	h := newHarness(t, cfg)
+	h.join("me", "a")
+
+	var buf lockedBuffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	for active := true; active; h.on(func(tb *Table) { active = tb.eng.HandActive }) {
+		h.actTurn(protocol.ActCheck, 0)
+	}
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestSummaryModeLogsOneLinePerHand(t *testing.T) {
+	lines := handLines(t, HandLogSummary)
+	if len(lines) != 1 || !strings.Contains(lines[0], "hand over: players=[") ||
+		!strings.Contains(lines[0], "pot=4") || !strings.Contains(lines[0], "actions=") {
+		t.Fatalf("summary mode logged:\n%s", strings.Join(lines, "\n"))
+	}
+
+	lines = handLines(t, HandLogVerbose)
+	if len(lines) < 10 {
+		t.Fatalf("verbose mode logged only:\n%s", strings.Join(lines, "\n"))
+	}
+	for _, want := range []string{"hand started", "your hole cards", "turn="} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Fatalf("verbose mode never logged %q:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+	for _, l := range lines {
+		if strings.Contains(l, "hand over: players=") {
+			t.Fatalf("verbose mode wrote the summary line: %s", l)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package table
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+	"p2poker/pkg/types"
+)
+
+// harness runs one Table on its own loop, as the authority unless asked
+// otherwise, and records everything it sends.
+type harness struct {
+	t    *testing.T
+	tb   *Table
+	net  chan protocol.NetMessage
+	sent []protocol.NetMessage
+
+	relayed int // commits already passed on by relay
+}
+
+func newHarness(t *testing.T, cfg types.TableConfig) *harness {
+	return newHarnessAs(t, cfg, "me", true)
+}
+
+func newHarnessAs(t *testing.T, cfg types.TableConfig, self protocol.NodeID, authority bool) *harness {
+	t.Helper()
+	in := make(chan protocol.NetMessage, 256)
+	out := make(chan protocol.NetMessage, 1<<16)
+	h := &harness{t: t, net: out}
+	h.tb = New("t1", self, cfg, authority, 0, &protocol.Lamport{}, protocol.NewIDGen(rand.NewSource(1)), in, out)
+	go h.tb.Run()
+	t.Cleanup(func() {
+		h.tb.Stop()
+		h.tb.exec(func() {}) // returns once the loop has seen stop
+	})
+	return h
+}
+
+// fakeClock stands in for time.Now as a table's clock (Table.now).
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+// useFakeClock puts the table on a fake clock, starting at an arbitrary time.
+func (h *harness) useFakeClock() *fakeClock {
+	c := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	h.on(func(tb *Table) { tb.now = c.now })
+	return c
+}
+
+// testConfig is a plain 1/2 hold'em table.
+func testConfig() types.TableConfig {
+	return types.TableConfig{Name: "test", SmallBlind: 1, BigBlind: 2, MinBuyin: 100}
+}
+
+// do proposes an action from this node and waits for the loop to handle it.
+func (h *harness) do(typ protocol.ActionType, player string, amount int64) error {
+	h.t.Helper()
+	return h.tb.ProposeSync(protocol.Action{ID: h.tb.ids.ActionID(), Type: typ, PlayerID: player, Amount: amount})
+}
+
+// must is do, failing the test on an error.
+func (h *harness) must(typ protocol.ActionType, player string, amount int64) {
+	h.t.Helper()
+	if err := h.do(typ, player, amount); err != nil {
+		h.t.Fatalf("%s %s %d: %v", typ, player, amount, err)
+	}
+}
+
+// join seats players at the minimum buy-in.
+func (h *harness) join(players ...string) {
+	h.t.Helper()
+	for _, p := range players {
+		h.must(protocol.ActJoin, p, 0)
+	}
+}
+
+// recv hands msg to the table as if it came off the network, and waits for
+// it to be handled.
+func (h *harness) recv(msg protocol.NetMessage) {
+	if msg.Table == "" {
+		msg.Table = h.tb.id
+	}
+	h.tb.exec(func() { h.tb.onNet(msg) })
+}
+
+// seat copies player's engine seat (nil if not seated).
+func (h *harness) seat(player string) *engine.Seat {
+	var st *engine.Seat
+	h.on(func(tb *Table) {
+		if s, ok := tb.eng.Seats[player]; ok {
+			c := *s
+			st = &c
+		}
+	})
+	return st
+}
+
+// on runs fn on the table loop.
+func (h *harness) on(fn func(tb *Table)) {
+	h.tb.exec(func() { fn(h.tb) })
+}
+
+// sentOf returns what the table has sent so far of the given type ("" = all).
+func (h *harness) sentOf(typ protocol.MsgType) []protocol.NetMessage {
+	h.tb.exec(func() {}) // the loop sends synchronously: all of it is queued by now
+	for len(h.net) > 0 {
+		h.sent = append(h.sent, <-h.net)
+	}
+	var msgs []protocol.NetMessage
+	for _, m := range h.sent {
+		if typ == "" || m.Type == typ {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs
+}
+
+// actTurn plays typ for whoever is to act.
+func (h *harness) actTurn(typ protocol.ActionType, amount int64) string {
+	h.t.Helper()
+	var p string
+	h.on(func(tb *Table) { p = tb.eng.CurrentPlayer() })
+	h.must(typ, p, amount)
+	return p
+}
+
+// act proposes a, under a fresh id, and waits for the loop to handle it.
+func (h *harness) act(a protocol.Action) error {
+	a.ID = h.tb.ids.ActionID()
+	return h.tb.ProposeSync(a)
+}
+
+// relay hands every commit h has sent since the last relay to f, in order.
+func (h *harness) relay(f *harness) {
+	commits := h.sentOf(protocol.MsgCommit)
+	for _, m := range commits[h.relayed:] {
+		f.recv(m)
+	}
+	h.relayed = len(commits)
+}
@@ -0,0 +1,36 @@
+package table
+
+import (
+	"log"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// ActionInterceptor is a house-rules hook run by the authority on every
+// proposed action before it is committed. It may return the action unchanged,
+// return a rewritten one (e.g. CHECK turned into FOLD) or reject it with an
+// error, in which case nothing is committed and the proposer gets the error
+// (a peer as a REJECT). state is read-only.
+type ActionInterceptor func(state *engine.State, a protocol.Action) (protocol.Action, error)
+
+// Intercept appends ic to the table's interceptor chain; interceptors run in
+// the order they were added. Safe to call while the table is running.
+func (t *Table) Intercept(ic ActionInterceptor) {
+	t.exec(func() { t.interceptors = append(t.interceptors, ic) })
+}
+
+// intercept passes a proposal through the interceptor chain, returning the
+// first interceptor's error if one rejects it. Authority follow-ups
+// (auto-advance, showdown, ...) are internal and never intercepted.
+func (t *Table) intercept(a protocol.Action) (protocol.Action, error) {
+	for _, ic := range t.interceptors {
+		out, err := ic(&t.eng, a)
+		if err != nil {
+			log.Printf("table %s: %s by %s rejected by house rule: %v", t.id, a.Type, a.PlayerID, err)
+			return a, err
+		}
+		a = out
+	}
+	return a, nil
+}
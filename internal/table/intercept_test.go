@@ -0,0 +1,67 @@
+package table
+
+import (
+	"errors"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+var errNoPreflopCheck = errors.New("no checking preflop")
+
+// noPreflopCheck is a house rule: a preflop CHECK becomes a FOLD, or, with
+// reject, is refused.
+func noPreflopCheck(reject bool) ActionInterceptor {
+	return func(s *engine.State, a protocol.Action) (protocol.Action, error) {
+		if a.Type != protocol.ActCheck || s.Phase != engine.PhasePreflop {
+			return a, nil
+		}
+		if reject {
+			return a, errNoPreflopCheck
+		}
+		a.Type = protocol.ActFold
+		return a, nil
+	}
+}
+
+func TestInterceptorTurnsAPreflopCheckIntoAFold(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.tb.Intercept(noPreflopCheck(false))
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	bb := h.actTurn(protocol.ActCheck, 0)
+
+	if st := h.seat(bb); st.Stack != 98 {
+		t.Fatalf("big blind %s has %d after the check, want 98: it should have folded", bb, st.Stack)
+	}
+	var last protocol.Action
+	h.on(func(tb *Table) { last = tb.log[len(tb.log)-1] })
+	if last.PlayerID != bb || last.Type != protocol.ActFold {
+		t.Fatalf("last commit %s by %s, want the big blind's FOLD", last.Type, last.PlayerID)
+	}
+}
+
+func TestInterceptorRejectsAPreflopCheck(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.tb.Intercept(noPreflopCheck(true))
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	bb := h.current()
+	if err := h.do(protocol.ActCheck, bb, 0); !errors.Is(err, errNoPreflopCheck) {
+		t.Fatalf("preflop check: %v, want the house rule's error", err)
+	}
+	if h.current() != bb || h.phase() != engine.PhasePreflop {
+		t.Fatalf("%s to act on the %v after a refused check, want %s still preflop", h.current(), h.phase(), bb)
+	}
+
+	// a peer's check is refused with a REJECT naming the rule
+	a := protocol.Action{ID: "peer-check", Type: protocol.ActCheck, PlayerID: bb}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: protocol.NodeID(bb), Action: &a})
+	rejects := h.sentOf(protocol.MsgReject)
+	if len(rejects) != 1 || rejects[0].Reason != errNoPreflopCheck.Error() {
+		t.Fatalf("rejects %+v, want one for the house rule", rejects)
+	}
+}
@@ -0,0 +1,165 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+	"p2poker/pkg/types"
+)
+
+// Join policies (TableConfig.JoinPolicy).
+const (
+	JoinOpen         = "open"          // default: join any time (sits out until the next hand)
+	JoinBetweenHands = "between-hands" // joins rejected while a hand is running
+	JoinClosed       = "closed"        // no new players
+)
+
+const defaultMaxSeats = 9
+
+// seatLimit is the configured seat count, capped at what one deck can deal.
+func seatLimit(cfg types.TableConfig) int {
+	n := defaultMaxSeats
+	if cfg.MaxSeats > 0 {
+		n = cfg.MaxSeats
+	}
+	return min(n, engine.MaxPlayers(cfg.Variant, cfg.BurnCards))
+}
+
+func (t *Table) maxSeats() int { return seatLimit(t.cfg) }
+
+// defaultMaxBuyinBBs is the buy-in cap, in big blinds, when MaxBuyin is unset.
+const defaultMaxBuyinBBs = 100
+
+// buyinCap is the configured max buy-in, or 100 big blinds (never below the
+// minimum buy-in) when it is unset. Only a table with neither blinds nor a
+// minimum is uncapped.
+func buyinCap(cfg types.TableConfig) int64 {
+	if cfg.MaxBuyin > 0 {
+		return cfg.MaxBuyin
+	}
+	return max(defaultMaxBuyinBBs*cfg.BigBlind, cfg.MinBuyin)
+}
+
+// fromPeer strips from a peer's proposal what only this node may put there.
+// moved_from seats a JOIN with the stack it names, past the buy-in band, so
+// it is honoured only on table moves the authority proposes itself (see
+// cluster.Node.MovePlayer); from anyone else it would mint chips.
+func fromPeer(a protocol.Action) protocol.Action {
+	if _, ok := a.Meta["moved_from"]; !ok {
+		return a
+	}
+	meta := make(map[string]any, len(a.Meta))
+	for k, v := range a.Meta {
+		if k != "moved_from" {
+			meta[k] = v
+		}
+	}
+	a.Meta = meta
+	return a
+}
+
+// JoinStatus reports whether node could join right now and, if not, why.
+func (t *Table) JoinStatus(node protocol.NodeID) (ok bool, reason string) {
+	if err := t.joinErr(node); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// CheckJoin is JoinStatus as an error, read on the table loop.
+func (t *Table) CheckJoin(node protocol.NodeID) error {
+	var err error
+	t.exec(func() { err = t.joinErr(node) })
+	return err
+}
+
+// joinErr is the single predicate the JOIN apply path gates on. A full table
+// is engine.ErrTableFull (wrapped), which apply answers with the waiting list.
+func (t *Table) joinErr(node protocol.NodeID) error {
+	pid := string(node)
+	if _, seated := t.eng.Seats[pid]; seated {
+		return engine.ErrAlreadySeated
+	}
+	if _, banned := t.bans[pid]; banned {
+		return errors.New("banned from this table")
+	}
+	switch t.cfg.JoinPolicy {
+	case JoinClosed:
+		return errors.New("table closed to new players")
+	case JoinBetweenHands:
+		if t.eng.HandActive {
+			return errors.New("hand in progress; joins open between hands")
+		}
+	}
+	if len(t.eng.Order) >= t.maxSeats() {
+		for i, w := range t.waiting {
+			if w == pid {
+				return fmt.Errorf("%w; on waiting list (position %d)", engine.ErrTableFull, i+1)
+			}
+		}
+		return fmt.Errorf("%w (%d/%d seats, %d waiting)", engine.ErrTableFull, len(t.eng.Order), t.maxSeats(), len(t.waiting))
+	}
+	return nil
+}
+
+// observe registers pid as a spectator. Observers follow every commit like
+// any replica but are never seated in the engine, so they are not dealt in
+// and never count toward a betting round; a JOIN seats them and ends it.
+func (t *Table) observe(pid string) error {
+	if _, seated := t.eng.Seats[pid]; seated {
+		return engine.ErrAlreadySeated
+	}
+	if _, banned := t.bans[pid]; banned {
+		return errors.New("banned from this table")
+	}
+	if !contains(t.observers, pid) {
+		t.observers = append(t.observers, pid)
+		log.Printf("table %s: %s is observing", t.id, pid)
+	}
+	return nil
+}
+
+// moveErr vets the LEAVE of a player being moved to another table (see
+// cluster.Node.MovePlayer): they must not be live in a hand, and their stack
+// must still be the Amount the move carries to the other table.
+func (t *Table) moveErr(a protocol.Action) error {
+	st, ok := t.eng.Seats[a.PlayerID]
+	switch {
+	case !ok:
+		return engine.ErrUnknownPlayer
+	case t.eng.HandActive && st.InHand && !st.Folded:
+		return fmt.Errorf("%s is live in a hand", a.PlayerID)
+	case st.Stack != a.Amount:
+		return fmt.Errorf("%s has %d, not the %d being moved", a.PlayerID, st.Stack, a.Amount)
+	}
+	return nil
+}
+
+// SeatOf copies player's seat, with whether they are live in the hand being
+// played; ok is false if they are not seated.
+func (t *Table) SeatOf(player string) (seat engine.Seat, live, ok bool) {
+	t.exec(func() {
+		var st *engine.Seat
+		if st, ok = t.eng.Seats[player]; ok {
+			seat = *st
+			live = t.eng.HandActive && st.InHand && !st.Folded
+		}
+	})
+	return seat, live, ok
+}
+
+// seatFromWaitlist fills free seats from the head of the waiting list.
+func (t *Table) seatFromWaitlist() {
+	for len(t.waiting) > 0 && len(t.eng.Order) < t.maxSeats() {
+		pid := t.waiting[0]
+		t.waiting = t.waiting[1:]
+		if err := t.eng.Sit(pid, t.cfg.MinBuyin); err != nil {
+			continue
+		}
+		t.chipsIn += t.cfg.MinBuyin
+		log.Printf("table %s: %s seated from waiting list", t.id, pid)
+	}
+}
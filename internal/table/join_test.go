@@ -0,0 +1,190 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestPeerJoinCannotClaimAMovedStack(t *testing.T) {
+	h := newHarness(t, testConfig())
+	a := protocol.Action{ID: "peer-1", Type: protocol.ActJoin, PlayerID: "peer", Amount: 1e12,
+		Meta: map[string]any{"moved_from": "elsewhere"}}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "peer", Action: &a})
+
+	if h.seat("peer") != nil {
+		t.Fatal("peer seated with a stack it named itself")
+	}
+	rejects := h.sentOf(protocol.MsgReject)
+	if len(rejects) != 1 || rejects[0].To != "peer" {
+		t.Fatalf("want one REJECT to peer, got %+v", rejects)
+	}
+}
+
+func TestMovedJoinFromTheAuthorityKeepsItsStack(t *testing.T) {
+	h := newHarness(t, testConfig())
+	err := h.tb.ProposeSync(protocol.Action{ID: "move-1", Type: protocol.ActJoin, PlayerID: "p", Amount: 5000,
+		Meta: map[string]any{"moved_from": "t0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := h.seat("p"); st == nil || st.Stack != 5000 {
+		t.Fatalf("moved player not seated with their stack: %+v", st)
+	}
+}
+
+func TestMaxBuyinDefaultsToOneHundredBigBlinds(t *testing.T) {
+	h := newHarness(t, testConfig()) // BB 2, min 100
+	var be *engine.BuyinError
+	if err := h.do(protocol.ActJoin, "big", 201); !errors.As(err, &be) || be.Max != 200 {
+		t.Fatalf("buy-in over 100 BB: got %v", err)
+	}
+	h.must(protocol.ActJoin, "ok", 200)
+
+	cfg := testConfig()
+	cfg.MinBuyin = 500 // above 100 BB: the minimum is the cap
+	if got := buyinCap(cfg); got != 500 {
+		t.Fatalf("cap below the minimum: %d", got)
+	}
+	cfg.MaxBuyin = 1000
+	if got := buyinCap(cfg); got != 1000 {
+		t.Fatalf("configured cap ignored: %d", got)
+	}
+}
+
+// joinStatus asks h whether node could join, and checks its own view agrees.
+func (h *harness) joinStatus(node protocol.NodeID) (ok bool, reason string) {
+	h.t.Helper()
+	var v View
+	h.on(func(tb *Table) {
+		ok, reason = tb.JoinStatus(node)
+		v = tb.ViewFor(node)
+	})
+	if v.Joinable != ok || v.JoinReason != reason {
+		h.t.Errorf("%s's view says joinable=%v (%q), JoinStatus %v (%q)", node, v.Joinable, v.JoinReason, ok, reason)
+	}
+	return ok, reason
+}
+
+func TestJoinStatusGivesTheReason(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxSeats = 2
+	h := newHarness(t, cfg)
+	if ok, reason := h.joinStatus("a"); !ok || reason != "" {
+		t.Fatalf("empty table: joinable=%v (%q)", ok, reason)
+	}
+	h.join("a", "b")
+
+	for _, tc := range []struct {
+		node protocol.NodeID
+		want string
+	}{
+		{"a", engine.ErrAlreadySeated.Error()},
+		{"c", engine.ErrTableFull.Error()},
+	} {
+		if ok, reason := h.joinStatus(tc.node); ok || !strings.Contains(reason, tc.want) {
+			t.Errorf("%s: joinable=%v (%q), want %q", tc.node, ok, reason, tc.want)
+		}
+	}
+	h.must(protocol.ActJoin, "c", 0) // to the waiting list
+	if _, reason := h.joinStatus("c"); !strings.Contains(reason, "waiting list (position 1)") {
+		t.Errorf("waiting player told %q", reason)
+	}
+
+	if err := h.act(protocol.Action{Type: protocol.ActKick, PlayerID: "me", Meta: map[string]any{"target": "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, reason := h.joinStatus("b"); ok || !strings.Contains(reason, "banned") {
+		t.Errorf("kicked player: joinable=%v (%q)", ok, reason)
+	}
+}
+
+func TestJoinStatusFollowsTheJoinPolicy(t *testing.T) {
+	cfg := testConfig()
+	cfg.JoinPolicy = JoinClosed
+	h := newHarness(t, cfg)
+	if ok, reason := h.joinStatus("a"); ok || !strings.Contains(reason, "closed") {
+		t.Errorf("closed table: joinable=%v (%q)", ok, reason)
+	}
+
+	cfg.JoinPolicy = JoinBetweenHands
+	h = newHarness(t, cfg)
+	h.join("a", "b")
+	if ok, _ := h.joinStatus("c"); !ok {
+		t.Fatal("between hands: c may not join")
+	}
+	h.must(protocol.ActStartHand, "me", 0)
+	if ok, reason := h.joinStatus("c"); ok || !strings.Contains(reason, "hand in progress") {
+		t.Errorf("mid-hand: joinable=%v (%q)", ok, reason)
+	}
+}
+
+func TestTenthPlayerAtANineMaxTableIsNotSeated(t *testing.T) {
+	s := engine.NewState(1, 2)
+	s.MaxSeats = 9
+	for i := range 10 {
+		err := s.SitStack(fmt.Sprintf("p%d", i), 100)
+		if want := i == 9; want != errors.Is(err, engine.ErrTableFull) {
+			t.Fatalf("engine seat %d: %v", i+1, err)
+		}
+	}
+
+	h := newHarness(t, testConfig()) // MaxSeats unset: 9
+	for i := range 10 {
+		p := fmt.Sprintf("p%d", i)
+		join := protocol.Action{ID: p + "-1", Type: protocol.ActJoin, PlayerID: p}
+		h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: protocol.NodeID(p), Action: &join})
+	}
+	var seated int
+	var waiting []string
+	h.on(func(tb *Table) { seated, waiting = len(tb.eng.Order), slices.Clone(tb.waiting) })
+	if seated != 9 || h.seat("p9") != nil || !slices.Equal(waiting, []string{"p9"}) {
+		t.Fatalf("%d seated, waiting %v; want nine and p9 waiting", seated, waiting)
+	}
+	if ok, reason := h.joinStatus("p10"); ok || !strings.Contains(reason, engine.ErrTableFull.Error()) {
+		t.Fatalf("an eleventh player: joinable=%v (%q)", ok, reason)
+	}
+}
+
+func TestObserverFollowsTheHandWithoutASeat(t *testing.T) {
+	h := newHarness(t, testConfig())
+	o := newHarnessAs(t, testConfig(), "o", false)
+	o.on(func(tb *Table) {
+		tb.authorityID = "me"
+		tb.ids.SetOrigin("o")
+	})
+	h.join("a", "b")
+	h.relay(o)
+	observe := protocol.Action{ID: "o-1", Type: protocol.ActObserve, PlayerID: "o"}
+	done, msg := proposeWaitFrom(t, o, observe, time.Minute)
+	h.recv(msg)
+	h.relay(o)
+	if err := verdictOf(t, done); err != nil {
+		t.Fatalf("OBSERVE: %v", err)
+	}
+
+	view := func() (v View, observing bool) {
+		o.on(func(tb *Table) { v, observing = tb.ViewFor("o"), contains(tb.observers, "o") })
+		return
+	}
+	h.must(protocol.ActStartHand, "me", 0)
+	h.relay(o)
+	if v, observing := view(); !observing || v.Phase != engine.PhasePreflop.String() || len(v.Holes) != 0 ||
+		!slices.Equal(v.Order, []engine.PlayerID{"a", "b"}) {
+		t.Fatalf("observer's view of the deal: observing %v, %s, holes %v, order %v", observing, v.Phase, v.Holes, v.Order)
+	}
+
+	// the street closes on the two seated players alone
+	h.actTurn(protocol.ActCall, 0)
+	h.actTurn(protocol.ActCheck, 0)
+	h.relay(o)
+	if v, _ := view(); v.Phase != engine.PhaseFlop.String() || len(v.Board) != 3 || h.seat("o") != nil {
+		t.Fatalf("observer's view after preflop closed: %s with board %v", v.Phase, v.Board)
+	}
+}
@@ -0,0 +1,77 @@
+package table
+
+import (
+	"fmt"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+// Blind levels (TableConfig.BlindSchedule) are timed by the authority. The
+// first level's clock starts with the first hand; when a level's Duration is
+// up the authority commits a BLIND_LEVEL, so every replica moves up at the
+// same seq. Blinds and ante change between hands only: a level reached
+// mid-hand takes effect at the next START_HAND. Followers restart the clock
+// when they apply the level, so a new authority carries on close to where
+// the old one was.
+
+// levelDeadline is when the current level ends; zero with no schedule, at
+// the last level, or before the first hand.
+func (t *Table) levelDeadline() time.Time {
+	sched := t.cfg.BlindSchedule
+	if t.levelSince.IsZero() || t.level+1 >= len(sched) || sched[t.level].Duration <= 0 {
+		return time.Time{}
+	}
+	return t.levelSince.Add(sched[t.level].Duration)
+}
+
+// onLevelUp (authority) commits the move to the next level once the current
+// one's time is up by the table's clock.
+func (t *Table) onLevelUp() {
+	if end := t.levelDeadline(); end.IsZero() || t.now().Before(end) {
+		return
+	}
+	t.commitAndBroadcast(t.localAction(protocol.ActBlindLevel, string(t.self), int64(t.level+1)))
+}
+
+// setLevel applies a committed BLIND_LEVEL.
+func (t *Table) setLevel(n int) error {
+	if n < 0 || n >= len(t.cfg.BlindSchedule) {
+		return fmt.Errorf("no blind level %d", n)
+	}
+	t.level = n
+	t.levelSince = t.now()
+	lv := t.cfg.BlindSchedule[n]
+	t.logf("table %s: blind level %d: %d/%d ante %d", t.id, lv.Level, lv.SB, lv.BB, lv.Ante)
+	if !t.eng.HandActive {
+		t.useLevel()
+	}
+	return nil
+}
+
+// useLevel puts the current level's blinds and ante on the engine, starting
+// the level clock if this is the first hand. START_HAND calls it before
+// dealing.
+func (t *Table) useLevel() {
+	if t.level >= len(t.cfg.BlindSchedule) {
+		return
+	}
+	if t.levelSince.IsZero() {
+		t.levelSince = t.now()
+	}
+	lv := t.cfg.BlindSchedule[t.level]
+	t.eng.SmallBlind, t.eng.BigBlind, t.eng.Ante = lv.SB, lv.BB, lv.Ante
+}
+
+// LevelRemaining reports the current blind level (an index into
+// TableConfig.BlindSchedule) and the time left in it (zero at the last level
+// or before play starts).
+func (t *Table) LevelRemaining() (level int, left time.Duration) {
+	t.exec(func() {
+		level = t.level
+		if end := t.levelDeadline(); !end.IsZero() {
+			left = max(end.Sub(t.now()), 0)
+		}
+	})
+	return level, left
+}
@@ -0,0 +1,69 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+	"p2poker/pkg/types"
+)
+
+func scheduleConfig() types.TableConfig {
+	cfg := testConfig()
+	cfg.BlindSchedule = []types.BlindLevel{
+		{Level: 1, SB: 1, BB: 2, Duration: 10 * time.Minute},
+		{Level: 2, SB: 2, BB: 4, Ante: 1, Duration: 10 * time.Minute},
+	}
+	return cfg
+}
+
+func TestBlindLevelRisesOnTheTablesClock(t *testing.T) {
+	h := newHarness(t, scheduleConfig())
+	clk := h.useFakeClock()
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+
+	clk.advance(9 * time.Minute)
+	h.on(func(tb *Table) { tb.onLevelUp() })
+	if level, left := h.tb.LevelRemaining(); level != 0 || left != time.Minute {
+		t.Fatalf("level %d with %v left, want level 0 with 1m", level, left)
+	}
+
+	clk.advance(time.Minute)
+	h.on(func(tb *Table) { tb.onLevelUp() })
+	if level, left := h.tb.LevelRemaining(); level != 1 || left != 0 {
+		t.Fatalf("level %d with %v left, want the last level", level, left)
+	}
+	var commits int
+	for _, m := range h.sentOf(protocol.MsgCommit) {
+		if m.Action.Type == protocol.ActBlindLevel {
+			commits++
+		}
+	}
+	if commits != 1 {
+		t.Fatalf("%d BLIND_LEVEL commits, want 1", commits)
+	}
+
+	// the new level waits for the next hand
+	var bb int64
+	h.on(func(tb *Table) { bb = tb.eng.BigBlind })
+	if bb != 2 {
+		t.Fatalf("blinds changed mid-hand: BB %d", bb)
+	}
+	h.actTurn(protocol.ActFold, 0)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.on(func(tb *Table) { bb = tb.eng.BigBlind })
+	if bb != 4 {
+		t.Fatalf("next hand BB %d, want 4", bb)
+	}
+}
+
+func TestPeerCannotProposeABlindLevel(t *testing.T) {
+	h := newHarness(t, scheduleConfig())
+	h.join("a", "b")
+	a := protocol.Action{ID: "peer-1", Type: protocol.ActBlindLevel, PlayerID: "a", Amount: 1}
+	h.recv(protocol.NetMessage{Type: protocol.MsgPropose, From: "a", Action: &a})
+	if level, _ := h.tb.LevelRemaining(); level != 0 {
+		t.Fatalf("peer moved the table to level %d", level)
+	}
+}
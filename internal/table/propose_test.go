@@ -0,0 +1,94 @@
+package table
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestProposeBatchAppliesInOrder(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.tb.ProposeBatch([]protocol.Action{
+		{ID: "b-1", Type: protocol.ActJoin, PlayerID: "p"},
+		{ID: "b-2", Type: protocol.ActRebuy, PlayerID: "p", Amount: 50}, // refused unless p is seated first
+	})
+
+	var got []protocol.ActionType
+	for deadline := time.Now().Add(2 * time.Second); len(got) < 2; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("committed %v, want the JOIN and the REBUY", got)
+		}
+		h.on(func(tb *Table) {
+			got = got[:0]
+			for _, a := range tb.log {
+				if a.ID == "b-1" || a.ID == "b-2" {
+					got = append(got, a.Type)
+				}
+			}
+		})
+	}
+	if got[0] != protocol.ActJoin || got[1] != protocol.ActRebuy {
+		t.Fatalf("committed %v, want JOIN then REBUY", got)
+	}
+	if st := h.seat("p"); st == nil || st.Stack != 150 {
+		t.Fatalf("p seated as %+v, want a stack of 150", st)
+	}
+}
+
+func TestOutOfTurnBetAdvancesNeitherSeq(t *testing.T) {
+	h := newHarness(t, testConfig())
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) {
+		tb.authorityID = "me"
+		tb.ids.SetOrigin("f")
+	})
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.relay(f)
+
+	seqs := func() (hs, fs uint64, hl, fl int) {
+		h.on(func(tb *Table) { hs, hl = tb.seq, len(tb.log) })
+		f.on(func(tb *Table) { fs, fl = tb.seq, len(tb.log) })
+		return
+	}
+	hs, fs, hl, fl := seqs()
+	if hs != fs {
+		t.Fatalf("before the bet: authority at seq %d, follower at %d", hs, fs)
+	}
+	var waiting string
+	h.on(func(tb *Table) {
+		for _, p := range []string{"a", "b"} {
+			if p != tb.eng.CurrentPlayer() {
+				waiting = p
+			}
+		}
+	})
+
+	// on the authority, the engine's error comes straight back
+	if err := h.do(protocol.ActBet, waiting, 6); !errors.Is(err, engine.ErrNotPlayersTurn) {
+		t.Fatalf("authority's out-of-turn bet: %v, want ErrNotPlayersTurn", err)
+	}
+	// from a follower, it comes back as a REJECT
+	bet := protocol.Action{ID: "f-1", Type: protocol.ActBet, PlayerID: waiting, Amount: 6}
+	done, msg := proposeWaitFrom(t, f, bet, time.Minute)
+	h.recv(msg)
+	for _, r := range h.sentOf(protocol.MsgReject) {
+		f.recv(r)
+	}
+	var rej *RejectedError
+	if err := verdictOf(t, done); !errors.As(err, &rej) || rej.Reason != engine.ErrNotPlayersTurn.Error() {
+		t.Fatalf("follower's out-of-turn bet: want a RejectedError (%v), got %v", engine.ErrNotPlayersTurn, err)
+	}
+	h.relay(f)
+
+	if hs2, fs2, hl2, fl2 := seqs(); hs2 != hs || fs2 != fs || hl2 != hl || fl2 != fl {
+		t.Fatalf("seq %d/%d and log %d/%d after the refused bets, want %d/%d and %d/%d",
+			hs2, fs2, hl2, fl2, hs, fs, hl, fl)
+	}
+	if c := h.sentOf(protocol.MsgCommit); c[len(c)-1].Seq != hs {
+		t.Fatalf("a refused bet was broadcast: last commit %+v", c[len(c)-1])
+	}
+}
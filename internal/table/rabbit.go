@@ -0,0 +1,29 @@
+package table
+
+import (
+	"log"
+
+	"p2poker/internal/engine"
+)
+
+// RabbitHunt reveals the board cards that would have come after a hand ended
+// early, for display only, and emits them as a RABBIT_HUNT event. Authority
+// only and only with AllowRabbitHunt; nil when there is nothing to reveal or
+// the deck is no longer known (e.g. after installing a snapshot).
+func (t *Table) RabbitHunt() []engine.Card {
+	var cards []engine.Card
+	t.exec(func() {
+		if !t.authority || !t.cfg.AllowRabbitHunt {
+			return
+		}
+		cs, err := t.eng.RabbitHunt()
+		if err != nil {
+			log.Printf("table %s: %v", t.id, err)
+			return
+		}
+		cards = cs
+		log.Printf("table %s: rabbit hunt: %s", t.id, engine.FormatCards(cs))
+		t.emit(TableEvent{Kind: EvRabbitHunt, Phase: t.eng.Phase.String(), Cards: cs})
+	})
+	return cards
+}
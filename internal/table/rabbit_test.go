@@ -0,0 +1,46 @@
+package table
+
+import (
+	"slices"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestRabbitHuntShowsTheUndealtBoard(t *testing.T) {
+	cfg := testConfig()
+	cfg.AllowRabbitHunt = true
+	h := newHarness(t, cfg)
+	h.join("a", "b")
+	hunts := h.tb.Subscribe(EvRabbitHunt)
+	h.must(protocol.ActStartHand, "me", 0)
+	if cs := h.tb.RabbitHunt(); cs != nil {
+		t.Fatalf("hunted %v mid-hand", cs)
+	}
+	var deck []engine.Card
+	h.on(func(tb *Table) { deck = slices.Clone(tb.eng.Deck) })
+	h.actTurn(protocol.ActFold, 0)
+
+	a, b := h.seat("a").Stack, h.seat("b").Stack
+	got := h.tb.RabbitHunt()
+	if !slices.Equal(got, deck[:5]) {
+		t.Fatalf("rabbit hunt %v, want the top of the leftover deck %v", got, deck[:5])
+	}
+	if evs := drainEvents(hunts); len(evs) != 1 || !slices.Equal(evs[0].Cards, got) {
+		t.Fatalf("RABBIT_HUNT events %+v", evs)
+	}
+	var left []engine.Card
+	h.on(func(tb *Table) { left = tb.eng.Deck })
+	if !slices.Equal(left, deck) || h.seat("a").Stack != a || h.seat("b").Stack != b {
+		t.Fatal("the rabbit hunt touched the deck or the stacks")
+	}
+
+	h = newHarness(t, testConfig())
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActFold, 0)
+	if cs := h.tb.RabbitHunt(); cs != nil {
+		t.Fatalf("hunted %v without AllowRabbitHunt", cs)
+	}
+}
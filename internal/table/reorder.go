@@ -0,0 +1,62 @@
+package table
+
+import (
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+// maxPending bounds the commits a follower holds while waiting for a gap to
+// fill; past it the gap is treated as a loss and a snapshot requested.
+const maxPending = 64
+
+// holdCommit keeps a commit that arrived ahead of t.seq+1 (a reordered
+// delivery) to apply once the gap fills. A gap that outlives cfg.GapTimeout,
+// or more than maxPending early commits, falls back to a snapshot.
+func (t *Table) holdCommit(a protocol.Action, seq uint64) {
+	if t.pending == nil {
+		t.pending = make(map[uint64]protocol.Action)
+	}
+	if len(t.pending) == 0 {
+		t.gapSince = time.Now()
+	}
+	t.pending[seq] = a
+	if len(t.pending) > maxPending {
+		t.requestResync()
+		return
+	}
+	t.checkGap()
+}
+
+// checkGap asks for a snapshot once the current gap has been open too long.
+// Run on every held commit and heartbeat.
+func (t *Table) checkGap() {
+	if len(t.pending) == 0 {
+		return
+	}
+	if time.Since(t.gapSince) >= maxDur(t.cfg.GapTimeout, time.Second) {
+		t.requestResync()
+	}
+}
+
+// drainPending applies held commits for as long as they follow on from t.seq,
+// then drops any a snapshot has overtaken.
+func (t *Table) drainPending() {
+	for len(t.pending) > 0 && !t.closing {
+		a, ok := t.pending[t.seq+1]
+		if !ok {
+			break
+		}
+		delete(t.pending, t.seq+1)
+		t.applyNext(a, t.seq+1)
+	}
+	for seq := range t.pending {
+		if seq <= t.seq {
+			delete(t.pending, seq)
+		}
+	}
+	if len(t.pending) > 0 {
+		// still waiting; the clock restarts for what is left
+		t.gapSince = time.Now()
+	}
+}
@@ -0,0 +1,56 @@
+package table
+
+import (
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+func TestReorderedCommitsApplyOnceTheGapFills(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	commits := h.sentOf(protocol.MsgCommit)
+	if len(commits) != 3 {
+		t.Fatalf("%d commits for three joins", len(commits))
+	}
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) { tb.authorityID = "me" })
+
+	f.recv(commits[0])
+	f.recv(commits[2]) // ahead of seq 2: held
+	var seq uint64
+	var held int
+	f.on(func(tb *Table) { seq, held = tb.seq, len(tb.pending) })
+	if seq != 1 || held != 1 {
+		t.Fatalf("with seq 2 missing: at seq %d holding %d, want 1 and 1", seq, held)
+	}
+	f.recv(commits[1])
+	f.on(func(tb *Table) { seq, held = tb.seq, len(tb.pending) })
+	if seq != 3 || held != 0 {
+		t.Fatalf("once seq 2 arrived: at seq %d holding %d, want 3 and 0", seq, held)
+	}
+	for _, p := range []string{"a", "b", "c"} {
+		if f.seat(p) == nil {
+			t.Fatalf("%s not seated on the follower", p)
+		}
+	}
+	if q := f.sentOf(protocol.MsgStateQuery); len(q) != 0 {
+		t.Fatalf("a reordered commit cost a snapshot: %d state queries", len(q))
+	}
+}
+
+func TestTooManyEarlyCommitsFallBackToASnapshot(t *testing.T) {
+	h := newHarness(t, testConfig())
+	for i := 0; i < maxPending+2; i++ {
+		h.must(protocol.ActJoin, string(rune('a'+i%26))+string(rune('a'+i/26)), 0)
+	}
+	commits := h.sentOf(protocol.MsgCommit)
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) { tb.authorityID = "me" })
+	for _, c := range commits[1:] { // seq 1 never arrives
+		f.recv(c)
+	}
+	if q := f.sentOf(protocol.MsgStateQuery); len(q) != 1 {
+		t.Fatalf("holding %d early commits: %d state queries, want 1", len(commits)-1, len(q))
+	}
+}
@@ -0,0 +1,96 @@
+package table
+
+import (
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+// playedHand seats a and b and plays one hand, which a or b folds.
+func playedHand(h *harness) {
+	h.t.Helper()
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActFold, 0)
+}
+
+func resetTable(h *harness, keep bool) error {
+	return h.act(protocol.Action{Type: protocol.ActReset, PlayerID: "me", Meta: map[string]any{"keep_seats": keep}})
+}
+
+func TestResetClearsTheSessionButNotTheTable(t *testing.T) {
+	h := newHarness(t, testConfig())
+	playedHand(h)
+	var seq uint64
+	h.on(func(tb *Table) {
+		tb.bans["x"] = struct{}{}
+		seq = tb.seq
+	})
+
+	if err := resetTable(h, false); err != nil {
+		t.Fatal(err)
+	}
+	h.on(func(tb *Table) {
+		if tb.id != "t1" || tb.epoch != 0 || tb.seq != seq+1 {
+			t.Errorf("identity lost: id %s epoch %d seq %d (was %d)", tb.id, tb.epoch, tb.seq, seq)
+		}
+		if len(tb.eng.Order) != 0 || tb.eng.TotalChips() != 0 || tb.chipsIn != 0 {
+			t.Errorf("stacks survived: %v, %d chips", tb.eng.Order, tb.eng.TotalChips())
+		}
+		if len(tb.bans) != 0 {
+			t.Errorf("bans survived: %v", tb.bans)
+		}
+		if len(tb.log) != 1 || tb.log[0].Type != protocol.ActReset || tb.logBase != seq {
+			t.Errorf("history survived: %d actions after %d", len(tb.log), tb.logBase)
+		}
+	})
+	if r := h.tb.Results(); len(r) != 0 {
+		t.Fatalf("results survived: %+v", r)
+	}
+	h.join("a") // the table plays on
+}
+
+func TestResetKeepingSeatsKeepsTheStacks(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinBuyin = 0 // a kept player must not come back with nothing
+	h := newHarness(t, cfg)
+	h.must(protocol.ActJoin, "a", 100)
+	h.must(protocol.ActJoin, "b", 100)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActFold, 0)
+	before := map[string]int64{"a": h.seat("a").Stack, "b": h.seat("b").Stack}
+	if before["a"] == 100 {
+		t.Fatalf("no chips changed hands: %v", before)
+	}
+
+	if err := resetTable(h, true); err != nil {
+		t.Fatal(err)
+	}
+	for p, stack := range before {
+		if st := h.seat(p); st == nil || st.Stack != stack {
+			t.Fatalf("%s after a keep-seats reset: %+v, want a stack of %d", p, st, stack)
+		}
+	}
+	for _, r := range h.tb.Results() {
+		if r.Net != 0 {
+			t.Fatalf("session results not restarted: %+v", r)
+		}
+	}
+	h.on(func(tb *Table) {
+		if err := tb.checkChips(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestResetIsRefusedMidHand(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	if err := resetTable(h, false); err == nil {
+		t.Fatal("reset allowed during a hand")
+	}
+	if h.seat("a") == nil {
+		t.Fatal("a refused reset still cleared the table")
+	}
+}
@@ -0,0 +1,34 @@
+package table
+
+import (
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestResultsCountRebuysAgainstTheStack(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	h.must(protocol.ActRebuy, "a", 50)
+	h.must(protocol.ActStartHand, "me", 0)
+	folder := h.actTurn(protocol.ActFold, 0) // heads up the button folds its small blind
+
+	want := map[string]engine.Result{
+		"a": {Player: "a", Buyin: 150, Stack: 151, Net: 1},
+		"b": {Player: "b", Buyin: 100, Stack: 99, Net: -1},
+	}
+	if folder == "a" {
+		want["a"] = engine.Result{Player: "a", Buyin: 150, Stack: 149, Net: -1}
+		want["b"] = engine.Result{Player: "b", Buyin: 100, Stack: 101, Net: 1}
+	}
+	got := h.tb.Results()
+	if len(got) != 2 {
+		t.Fatalf("results %+v, want a and b", got)
+	}
+	for _, r := range got {
+		if r != want[string(r.Player)] {
+			t.Errorf("%s: %+v, want %+v", r.Player, r, want[string(r.Player)])
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package table
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestMuckedLoserIsLeftOutOfTheShowdown(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	var logged lockedBuffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+	showdowns := h.tb.Subscribe(EvShowdown)
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActCall, 0)
+	for h.phase() != engine.PhaseRiver {
+		h.actTurn(protocol.ActCheck, 0)
+	}
+
+	// heads-up the big blind is first to show with no river bet, so the
+	// button mucks the losing hand
+	board, _ := engine.DecodeCards(strings.Fields("Kd Qs 9d 4c 2h"))
+	aces, _ := engine.DecodeCards([]string{"Ah", "Ac"})
+	junk, _ := engine.DecodeCards([]string{"7s", "6s"})
+	var button, bb string
+	h.on(func(tb *Table) {
+		button = tb.eng.Order[tb.eng.DealerIdx]
+		bb = tb.eng.Order[1-tb.eng.DealerIdx]
+		tb.eng.Board, tb.eng.Holes[bb], tb.eng.Holes[button] = board, aces, junk
+	})
+	h.must(protocol.ActMuck, button, 0)
+	h.actTurn(protocol.ActCheck, 0)
+	h.actTurn(protocol.ActCheck, 0)
+
+	evs := drainEvents(showdowns)
+	if len(evs) != 1 {
+		t.Fatalf("showdown events %+v, want one", evs)
+	}
+	sum := evs[0].Showdown
+	if !sum.Revealed[bb] || sum.Revealed[button] {
+		t.Fatalf("revealed %v; want %s shown and %s mucked", sum.Revealed, bb, button)
+	}
+	if _, ok := sum.Shown[button]; ok || len(sum.Shown[bb]) != 2 {
+		t.Fatalf("shown %v; want only %s's cards", sum.Shown, bb)
+	}
+	b, err := json.Marshal(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{"7s", "6s"} {
+		if bytes.Contains(b, []byte(`"`+c+`"`)) {
+			t.Fatalf("mucked %s in the published summary: %s", c, b)
+		}
+	}
+	if out := logged.String(); !strings.Contains(out, button+" mucks") || strings.Contains(out, "7s") {
+		t.Fatalf("hand log:\n%s\nwant %s's muck without its cards", out, button)
+	}
+}
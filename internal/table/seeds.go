@@ -0,0 +1,285 @@
+package table
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// With CommitReveal the deck is not the authority's to pick. Each round,
+// every player's node draws a secret seed and commits a SEED_COMMIT carrying
+// only its hash (engine.SeedCommitment). Once every player who will be dealt
+// in has committed and a hand could start, the nodes reveal their seeds with
+// SEED_REVEAL, and START_HAND shuffles from the XOR of the reveals
+// (engine.CombineSeeds), refusing to deal until every player dealt in has
+// revealed. No commitment is accepted after the first reveal, so nobody can
+// pick a seed knowing the others; a player who joins or sits in mid-reveal
+// restarts the round instead. A player who has not committed or revealed
+// SeedTimeout after a hand could have started forfeits the round, and the
+// others are dealt from the seeds already revealed. A player who reveals a
+// seed that does not match their commitment forfeits it too, but since the
+// honest seeds are public by then the round starts over: START_HAND waits
+// for the others to commit and reveal fresh seeds. Either way the player is
+// sat out and cannot sit back in (or rejoin) until the others have been
+// dealt, so neither withholding a reveal nor botching one stalls the table.
+// The round's commitments, reveals and forfeits are replicated (commits and
+// snapshots); each node's secret never leaves it until it is revealed.
+
+// reasonSeedForfeit tags the SIT_OUT the authority commits for a player who
+// ran out of time to commit or reveal; applying it bars them like a bad reveal.
+const reasonSeedForfeit = "seed forfeited"
+
+// defaultSeedTimeout is SeedTimeout when unset.
+const defaultSeedTimeout = 30 * time.Second
+
+// seedSecret is this node's own seed for one round.
+type seedSecret struct {
+	round     uint64 // the seedRound it was drawn for
+	seed      int64
+	committed bool // SEED_COMMIT proposed
+	revealed  bool // SEED_REVEAL proposed
+}
+
+// commitSeed applies a committed SEED_COMMIT.
+func (t *Table) commitSeed(a protocol.Action) error {
+	if !t.cfg.CommitReveal {
+		return fmt.Errorf("table %s does not shuffle from committed seeds", t.id)
+	}
+	if _, ok := t.eng.Seats[a.PlayerID]; !ok {
+		return engine.ErrUnknownPlayer
+	}
+	if len(t.seedReveals) > 0 {
+		return fmt.Errorf("seeds are already being revealed")
+	}
+	if _, ok := t.seedCommits[a.PlayerID]; ok {
+		return fmt.Errorf("%s already committed a seed", a.PlayerID)
+	}
+	hash, _ := a.Meta["hash"].(string)
+	if len(hash) != 64 {
+		return fmt.Errorf("bad seed commitment %q", hash)
+	}
+	if t.seedCommits == nil {
+		t.seedCommits = make(map[string]string)
+	}
+	t.seedCommits[a.PlayerID] = hash
+	return nil
+}
+
+// revealSeed applies a committed SEED_REVEAL. A seed that does not match its
+// commitment is still committed, so every replica sits the player out and
+// restarts the round together.
+func (t *Table) revealSeed(a protocol.Action) error {
+	hash, ok := t.seedCommits[a.PlayerID]
+	if !ok {
+		return fmt.Errorf("no seed commitment from %s", a.PlayerID)
+	}
+	if _, ok := t.seedReveals[a.PlayerID]; ok {
+		return fmt.Errorf("%s already revealed a seed", a.PlayerID)
+	}
+	v, _ := a.Meta["seed"].(string)
+	seed, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || engine.SeedCommitment(seed) != hash {
+		log.Printf("table %s: %s revealed a seed that does not match their commitment; sitting them out and restarting the seed round", t.id, a.PlayerID)
+		if err := t.eng.SetSittingOut(a.PlayerID, true); err != nil {
+			return err
+		}
+		t.forfeitSeed(a.PlayerID)
+		t.clearSeeds()
+		return nil
+	}
+	if t.seedReveals == nil {
+		t.seedReveals = make(map[string]int64)
+	}
+	t.seedReveals[a.PlayerID] = seed
+	return nil
+}
+
+// dealSeed is the seed START_HAND shuffles from: the XOR of every reveal,
+// once every player to be dealt in has revealed.
+func (t *Table) dealSeed() (int64, error) {
+	var missing []string
+	for _, pid := range t.eng.Order {
+		if st := t.eng.Seats[pid]; st.Stack > 0 && !st.SittingOut {
+			if _, ok := t.seedReveals[pid]; !ok {
+				missing = append(missing, pid)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("waiting for seed reveals from %v", missing)
+	}
+	seeds := make([]int64, 0, len(t.seedReveals))
+	for _, s := range t.seedReveals {
+		seeds = append(seeds, s)
+	}
+	return engine.CombineSeeds(seeds...), nil
+}
+
+// restartSeeds starts the round over when a player due to be dealt in has
+// no commitment but reveals have begun (a join, a sit-in, a rebuy), so the
+// newcomer gets to commit before anyone's seed is known. Runs after every
+// applied action.
+func (t *Table) restartSeeds() {
+	if !t.cfg.CommitReveal || len(t.seedReveals) == 0 {
+		return
+	}
+	for _, pid := range t.eng.Order {
+		if st := t.eng.Seats[pid]; st.Stack > 0 && !st.SittingOut {
+			if _, ok := t.seedCommits[pid]; !ok {
+				log.Printf("table %s: %s has no seed commitment; restarting the seed round", t.id, pid)
+				t.clearSeeds()
+				return
+			}
+		}
+	}
+}
+
+// forfeitSeed drops player from the round and bars them from it.
+func (t *Table) forfeitSeed(player string) {
+	delete(t.seedCommits, player)
+	delete(t.seedReveals, player)
+	t.barSeed(player)
+}
+
+func (t *Table) barSeed(player string) {
+	if t.seedBarred == nil {
+		t.seedBarred = make(map[string]struct{})
+	}
+	t.seedBarred[player] = struct{}{}
+}
+
+func (t *Table) barredList() []string {
+	out := make([]string, 0, len(t.seedBarred))
+	for pid := range t.seedBarred {
+		out = append(out, pid)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// seedBarErr refuses a sit-in or join from a player who forfeited this round.
+func (t *Table) seedBarErr(player string) error {
+	if _, ok := t.seedBarred[player]; ok {
+		return fmt.Errorf("%s forfeited this seed round; sit in after the next deal", player)
+	}
+	return nil
+}
+
+// seedLaggards lists the players due to be dealt in who hold up the round:
+// those without a commitment, or once everyone has committed, those who have
+// not revealed.
+func (t *Table) seedLaggards() []string {
+	var uncommitted, unrevealed []string
+	for _, pid := range t.eng.Order {
+		if st := t.eng.Seats[pid]; st.Stack == 0 || st.SittingOut {
+			continue
+		}
+		if _, ok := t.seedCommits[pid]; !ok {
+			uncommitted = append(uncommitted, pid)
+		} else if _, ok := t.seedReveals[pid]; !ok {
+			unrevealed = append(unrevealed, pid)
+		}
+	}
+	if len(uncommitted) > 0 {
+		return uncommitted
+	}
+	return unrevealed
+}
+
+// armSeedDeadline (authority) starts the SeedTimeout clock once a hand could
+// start but the round is still waiting on someone, and stops it otherwise.
+func (t *Table) armSeedDeadline() {
+	if !t.authority || !t.cfg.CommitReveal || t.closing || !t.eng.CanStart() || len(t.seedLaggards()) == 0 {
+		t.seedDeadline = time.Time{}
+		return
+	}
+	if t.seedDeadline.IsZero() {
+		timeout := t.cfg.SeedTimeout
+		if timeout <= 0 {
+			timeout = defaultSeedTimeout
+		}
+		t.seedDeadline = t.now().Add(timeout)
+	}
+}
+
+// onSeedTimeout (authority) sits out every player still holding up the round
+// when SeedTimeout runs out.
+func (t *Table) onSeedTimeout() {
+	if t.seedDeadline.IsZero() || t.now().Before(t.seedDeadline) {
+		return
+	}
+	t.seedDeadline = time.Time{}
+	for _, pid := range t.seedLaggards() {
+		log.Printf("table %s: %s did not commit or reveal a seed in time; sitting them out", t.id, pid)
+		a := t.localAction(protocol.ActSitOut, pid, 0)
+		a.Meta = map[string]any{"reason": reasonSeedForfeit}
+		t.commitAndBroadcast(a)
+	}
+}
+
+// clearSeeds ends the round, after a deal or a restart.
+func (t *Table) clearSeeds() {
+	t.seedRound++
+	t.seedCommits = nil
+	t.seedReveals = nil
+}
+
+// seedTurn proposes this node's commitment, or its reveal once every player
+// due to be dealt in has committed. It runs whenever the replicated state
+// moves on (a commit applied, a snapshot installed), never during Replay.
+func (t *Table) seedTurn() {
+	t.armSeedDeadline()
+	if !t.cfg.CommitReveal || t.closing {
+		return
+	}
+	self := string(t.self)
+	if st, ok := t.eng.Seats[self]; !ok || st.Stack == 0 || st.SittingOut {
+		return
+	}
+	if t.secret.round != t.seedRound || t.secret.seed == 0 {
+		t.secret = seedSecret{round: t.seedRound, seed: t.ids.Seed()}
+	}
+	sec := &t.secret
+	if _, ok := t.seedCommits[self]; !ok {
+		if sec.committed || len(t.seedReveals) > 0 {
+			return
+		}
+		sec.committed = true
+		a := t.localAction(protocol.ActSeedCommit, self, 0)
+		a.Meta = map[string]any{"hash": engine.SeedCommitment(sec.seed)}
+		t.propose(a)
+		return
+	}
+	// a commitment this node did not make here (it has restarted since) can
+	// never be revealed: the deal waits until the player leaves or sits out
+	if sec.revealed || !sec.committed || !t.eng.CanStart() {
+		return
+	}
+	for _, pid := range t.eng.Order {
+		if st := t.eng.Seats[pid]; st.Stack > 0 && !st.SittingOut {
+			if _, ok := t.seedCommits[pid]; !ok {
+				return
+			}
+		}
+	}
+	sec.revealed = true
+	a := t.localAction(protocol.ActSeedReveal, self, 0)
+	a.Meta = map[string]any{"seed": strconv.FormatInt(sec.seed, 10)}
+	t.propose(a)
+}
+
+func copySeeds[V any](m map[string]V) map[string]V {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
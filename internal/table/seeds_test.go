@@ -0,0 +1,218 @@
+package table
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+	"p2poker/pkg/types"
+)
+
+func commitRevealConfig() types.TableConfig {
+	cfg := testConfig()
+	cfg.CommitReveal = true
+	cfg.FollowerTO = time.Hour // no takeovers while a test runs
+	return cfg
+}
+
+func (h *harness) commitSeed(player string, seed int64) {
+	h.t.Helper()
+	err := h.act(protocol.Action{Type: protocol.ActSeedCommit, PlayerID: player,
+		Meta: map[string]any{"hash": engine.SeedCommitment(seed)}})
+	if err != nil {
+		h.t.Fatalf("%s commit: %v", player, err)
+	}
+}
+
+func (h *harness) revealSeed(player string, seed int64) {
+	h.t.Helper()
+	err := h.act(protocol.Action{Type: protocol.ActSeedReveal, PlayerID: player,
+		Meta: map[string]any{"seed": strconv.FormatInt(seed, 10)}})
+	if err != nil {
+		h.t.Fatalf("%s reveal: %v", player, err)
+	}
+}
+
+// holes copies every hole card dealt so far.
+func (h *harness) holes() map[string][]engine.Card {
+	out := map[string][]engine.Card{}
+	h.on(func(tb *Table) {
+		for pid, cs := range tb.eng.Holes {
+			out[pid] = append([]engine.Card{}, cs...)
+		}
+	})
+	return out
+}
+
+// seededHand seats a and b, runs a commit-reveal round with seeds 11 and 22
+// and deals.
+func seededHand(t *testing.T, h *harness) {
+	t.Helper()
+	h.join("a", "b")
+	h.commitSeed("a", 11)
+	h.commitSeed("b", 22)
+	if err := h.do(protocol.ActStartHand, "me", 0); err == nil {
+		t.Fatal("dealt before any seed was revealed")
+	}
+	h.revealSeed("a", 11)
+	h.revealSeed("b", 22)
+	h.must(protocol.ActStartHand, "me", 0)
+}
+
+func TestCommittedSeedsDealTheSameDeckOnEveryNode(t *testing.T) {
+	h := newHarness(t, commitRevealConfig())
+	f := newHarnessAs(t, commitRevealConfig(), "f", false)
+	seededHand(t, h)
+	h.relay(f)
+
+	want := h.holes()
+	if len(want["a"]) != 2 || len(want["b"]) != 2 {
+		t.Fatalf("holes %v", want)
+	}
+	if got := f.holes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("follower dealt %v, authority %v", got, want)
+	}
+
+	// another table, other action ids, same seeds: the same deal
+	other := newHarness(t, commitRevealConfig())
+	other.do(protocol.ActObserve, "x", 0) // moves the id counter on
+	seededHand(t, other)
+	if got := other.holes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("same seeds dealt %v, then %v", want, got)
+	}
+}
+
+func TestBadRevealSitsOutAndRestartsTheRound(t *testing.T) {
+	h := newHarness(t, commitRevealConfig())
+	h.join("a", "b", "c")
+	h.commitSeed("a", 11)
+	h.commitSeed("b", 22)
+	h.commitSeed("c", 33)
+	h.revealSeed("a", 11)
+	h.revealSeed("b", 22)
+	h.revealSeed("c", 34) // not what c committed to
+
+	if st := h.seat("c"); !st.SittingOut {
+		t.Fatal("c not sat out after a bad reveal")
+	}
+	var commits, reveals int
+	h.on(func(tb *Table) { commits, reveals = len(tb.seedCommits), len(tb.seedReveals) })
+	if commits != 0 || reveals != 0 {
+		t.Fatalf("round kept %d commitments and %d reveals after a bad reveal", commits, reveals)
+	}
+	if err := h.do(protocol.ActStartHand, "me", 0); err == nil {
+		t.Fatal("dealt from seeds revealed before the bad reveal")
+	}
+	if err := h.do(protocol.ActSitIn, "c", 0); err == nil {
+		t.Fatal("c sat back in before the deal")
+	}
+
+	// a fresh round among the honest players
+	h.commitSeed("a", 12)
+	h.commitSeed("b", 23)
+	h.revealSeed("a", 12)
+	h.revealSeed("b", 23)
+	h.must(protocol.ActStartHand, "me", 0)
+	var seed int64
+	h.on(func(tb *Table) { seed = tb.handSeed })
+	if want := engine.CombineSeeds(12, 23); seed != want {
+		t.Fatalf("dealt from %d, want the fresh round's %d", seed, want)
+	}
+	if hs := h.holes(); len(hs["c"]) != 0 {
+		t.Fatal("c dealt in")
+	}
+	h.must(protocol.ActSitIn, "c", 0) // the bar lifts with the deal
+}
+
+func TestWithheldRevealIsSatOutAfterTheSeedTimeout(t *testing.T) {
+	cfg := commitRevealConfig()
+	cfg.SeedTimeout = 10 * time.Second
+	h := newHarness(t, cfg)
+	clk := h.useFakeClock()
+	h.join("a", "b", "c")
+	h.commitSeed("a", 11)
+	h.commitSeed("b", 22)
+	h.commitSeed("c", 33)
+	h.revealSeed("a", 11)
+	h.revealSeed("b", 22)
+
+	clk.advance(9 * time.Second)
+	h.on(func(tb *Table) { tb.onSeedTimeout() })
+	if st := h.seat("c"); st.SittingOut {
+		t.Fatal("c sat out before the timeout")
+	}
+	clk.advance(time.Second)
+	h.on(func(tb *Table) { tb.onSeedTimeout() })
+	if st := h.seat("c"); !st.SittingOut {
+		t.Fatal("c still holding up the deal after the timeout")
+	}
+	if err := h.do(protocol.ActJoin, "c", 0); err != nil {
+		t.Fatal(err) // already seated: a no-op
+	}
+	if err := h.do(protocol.ActSitIn, "c", 0); err == nil {
+		t.Fatal("c sat back in before the deal")
+	}
+	h.must(protocol.ActStartHand, "me", 0)
+}
+
+func TestSeedTimeoutAlsoCoversMissingCommitments(t *testing.T) {
+	h := newHarness(t, commitRevealConfig())
+	clk := h.useFakeClock()
+	h.join("a", "b", "c")
+	h.commitSeed("a", 11)
+	h.commitSeed("b", 22)
+
+	var deadline time.Time
+	h.on(func(tb *Table) { deadline = tb.seedDeadline })
+	if want := clk.now().Add(defaultSeedTimeout); !deadline.Equal(want) {
+		t.Fatalf("deadline %v, want %v", deadline, want)
+	}
+	clk.advance(defaultSeedTimeout)
+	h.on(func(tb *Table) { tb.onSeedTimeout() })
+	if st := h.seat("c"); !st.SittingOut {
+		t.Fatal("c never committed but was not sat out")
+	}
+	h.revealSeed("a", 11)
+	h.revealSeed("b", 22)
+	h.must(protocol.ActStartHand, "me", 0)
+}
+
+func TestRevealedSeedReproducesTheDeck(t *testing.T) {
+	cfg := testConfig()
+	cfg.RevealSeed = true
+	h := newHarness(t, cfg)
+	h.join("a", "b")
+	reveals := h.tb.Subscribe(EvSeedRevealed)
+	h.must(protocol.ActStartHand, "me", 0)
+
+	var dealt, undealt []engine.Card
+	h.on(func(tb *Table) {
+		for _, p := range tb.eng.Order {
+			dealt = append(dealt, tb.eng.Holes[p]...)
+		}
+		undealt = append(undealt, tb.eng.Deck...)
+	})
+	if evs := drainEvents(reveals); len(evs) != 0 {
+		t.Fatalf("seed revealed mid-hand: %+v", evs)
+	}
+	h.actTurn(protocol.ActFold, 0)
+
+	evs := drainEvents(reveals)
+	if len(evs) != 1 {
+		t.Fatalf("SEED_REVEALED events %+v, want one as the hand ended", evs)
+	}
+	deck := engine.NewDeckFor(cfg.Variant, rand.New(rand.NewSource(evs[0].Seed)))
+	if !reflect.DeepEqual(undealt, deck[len(dealt):]) {
+		t.Fatal("the revealed seed does not shuffle the deck the hand was dealt from")
+	}
+	for _, c := range dealt {
+		if !slices.Contains(deck[:len(dealt)], c) {
+			t.Fatalf("hole card %s was not on top of the revealed deck", c)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package table
+
+import (
+	"errors"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// SimResult reports the outcome of SimulateHands.
+type SimResult struct {
+	Hands  int              // hands actually played
+	Stacks map[string]int64 // final stacks by player
+	Drift  error            // non-nil if chips were not conserved
+}
+
+// maxSimActions bounds a single simulated hand (guards against a stuck state).
+const maxSimActions = 1000
+
+// SimulateHands auto-plays up to n hands with engine.Bot players seeded with
+// seed, so a run is reproducible. Authority only. Every action is committed and
+// broadcast normally, so followers play along. Stops early when fewer than two
+// players have chips.
+func (t *Table) SimulateHands(n int, seed int64) (SimResult, error) {
+	var res SimResult
+	var err error
+	t.exec(func() { res, err = t.simulate(n, engine.Bot{Seed: seed}) })
+	return res, err
+}
+
+func (t *Table) simulate(n int, bot engine.Bot) (SimResult, error) {
+	if !t.authority {
+		return SimResult{}, errors.New("simulate: not the authority")
+	}
+	if t.eng.HandActive {
+		return SimResult{}, errors.New("simulate: a hand is already in progress")
+	}
+	res := SimResult{}
+	for res.Hands < n && !t.closing {
+		// with MinPlayers set the table deals the next hand by itself
+		if !t.eng.HandActive {
+			funded := 0
+			for _, st := range t.eng.Seats {
+				if st.Stack > 0 {
+					funded++
+				}
+			}
+			if funded < 2 {
+				break
+			}
+			t.commitAndBroadcast(t.localAction(protocol.ActStartHand, string(t.self), 0))
+			if !t.eng.HandActive {
+				break
+			}
+		}
+		res.Hands++
+		for i := 0; t.eng.HandActive && i < maxSimActions; i++ {
+			pid := t.eng.CurrentPlayer()
+			move, amt, ok := bot.Decide(&t.eng, pid)
+			if !ok {
+				// nobody can act (all-in): run the board out
+				t.commitAndBroadcast(t.localAction(protocol.ActAdvance, string(t.self), 0))
+				continue
+			}
+			t.commitAndBroadcast(botAction(t, pid, move, amt))
+		}
+	}
+	res.Stacks = make(map[string]int64, len(t.eng.Seats))
+	for pid, st := range t.eng.Seats {
+		res.Stacks[pid] = st.Stack
+	}
+	res.Drift = t.checkChips()
+	return res, nil
+}
+
+// botAction turns a bot decision into an action.
+func botAction(t *Table, pid string, move engine.Move, amt int64) protocol.Action {
+	switch move {
+	case engine.MoveFold:
+		return t.localAction(protocol.ActFold, pid, 0)
+	case engine.MoveCheck:
+		return t.localAction(protocol.ActCheck, pid, 0)
+	case engine.MoveCall:
+		return t.localAction(protocol.ActCall, pid, 0)
+	case engine.MoveBet:
+		return t.localAction(protocol.ActBet, pid, amt)
+	default:
+		return t.localAction(protocol.ActRaise, pid, amt)
+	}
+}
+
+func (t *Table) localAction(typ protocol.ActionType, pid string, amt int64) protocol.Action {
+	return protocol.Action{ID: t.ids.ActionID(), Type: typ, PlayerID: pid, Amount: amt}
+}
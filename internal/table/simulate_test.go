@@ -0,0 +1,53 @@
+package table
+
+import (
+	"maps"
+	"testing"
+
+	"p2poker/internal/protocol"
+)
+
+func simulated(t *testing.T, seed int64) (SimResult, []protocol.Action) {
+	t.Helper()
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	res, err := h.tb.SimulateHands(20, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Drift != nil {
+		t.Fatal(res.Drift)
+	}
+	return res, h.tb.Log().Actions
+}
+
+func TestSimulationConservesChips(t *testing.T) {
+	res, _ := simulated(t, 3)
+	var total int64
+	for _, s := range res.Stacks {
+		total += s
+	}
+	if res.Hands == 0 || len(res.Stacks) != 3 || total != 300 {
+		t.Fatalf("%d hands left %v (%d chips); want the 300 bought in", res.Hands, res.Stacks, total)
+	}
+}
+
+func TestSeededBotsPlayTheSameHandsTwice(t *testing.T) {
+	res1, log1 := simulated(t, 7)
+	res2, log2 := simulated(t, 7)
+	if res1.Hands == 0 || !maps.Equal(res1.Stacks, res2.Stacks) {
+		t.Fatalf("results differ: %+v vs %+v", res1, res2)
+	}
+	if at, ok := DiffLogs(log1, log2); !ok || len(log1) != len(log2) {
+		t.Fatalf("action sequences differ at seq %d (%d vs %d actions)", at, len(log1), len(log2))
+	}
+	postflop := 0
+	for _, a := range log1 {
+		if a.Type == protocol.ActBet {
+			postflop++ // preflop the blinds are the bet: only raises
+		}
+	}
+	if postflop == 0 {
+		t.Fatal("the bots never played past the flop")
+	}
+}
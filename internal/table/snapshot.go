@@ -2,6 +2,9 @@ package table
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
 
 	"p2poker/internal/engine"
 	"p2poker/internal/protocol"
@@ -10,60 +13,120 @@ import (
 // Public wrapper
 func (t *Table) Snapshot() protocol.TableSnapshot { return t.snapshot() }
 
+// SnapshotFor builds a snapshot that also carries target's own hole cards.
+// It must only ever be unicast to target; broadcasts use Snapshot.
+func (t *Table) SnapshotFor(target protocol.NodeID) protocol.TableSnapshot {
+	return t.buildSnapshot(t.eng.SnapshotFor(string(target)))
+}
+
 // Build a protocol-level snapshot that embeds the engine state as JSON.
+// This is the broadcast path: the engine snapshot never contains hole cards.
 func (t *Table) snapshot() protocol.TableSnapshot {
-	// 1) Capture engine snapshot (pure data struct)
-	es := t.eng.Snapshot() // engine.EngineSnapshot
+	return t.buildSnapshot(t.eng.Snapshot())
+}
 
-	// 2) Marshal to JSON so protocol stays leaf-only (no engine import)
+func (t *Table) buildSnapshot(es engine.EngineSnapshot) protocol.TableSnapshot {
+	// Marshal to JSON so protocol stays leaf-only (no engine import)
 	payload, _ := json.Marshal(es) // best-effort; err unlikely
 
+	bans := make([]string, 0, len(t.bans))
+	for pid := range t.bans {
+		bans = append(bans, pid)
+	}
+	sort.Strings(bans)
+
 	return protocol.TableSnapshot{
-		Cfg:        t.cfg,
-		Seq:        t.seq,
-		Epoch:      t.epoch,
-		Authority:  t.authorityID,
-		EngineJSON: payload, // << include engine state
+		Cfg:         t.cfg,
+		Seq:         t.seq,
+		Epoch:       t.epoch,
+		Authority:   t.authorityID,
+		Bans:        bans,
+		Waiting:     append([]string{}, t.waiting...),
+		Observers:   append([]string{}, t.observers...),
+		Level:       t.level,
+		SeedRound:   t.seedRound,
+		SeedCommits: copySeeds(t.seedCommits),
+		SeedReveals: copySeeds(t.seedReveals),
+		SeedBarred:  t.barredList(),
+		EngineJSON:  payload, // << include engine state
 	}
 }
 
-// Install a received snapshot into the local table/engine.
-func (t *Table) installSnapshot(ss protocol.TableSnapshot) {
+// Install a received snapshot into the local table/engine. The engine state is
+// validated first; an inconsistent snapshot is rejected wholesale (nothing is
+// adopted) so a buggy or hostile authority cannot corrupt this replica.
+func (t *Table) installSnapshot(ss protocol.TableSnapshot) error {
+	var es *engine.EngineSnapshot
+	if len(ss.EngineJSON) > 0 {
+		var dec engine.EngineSnapshot
+		if err := json.Unmarshal(ss.EngineJSON, &dec); err != nil {
+			return fmt.Errorf("undecodable engine state in snapshot (seq %d): %w", ss.Seq, err)
+		}
+		var probe engine.State
+		probe.RestoreFromSnapshot(dec)
+		if err := probe.Validate(); err != nil {
+			return fmt.Errorf("invalid snapshot (seq %d): %w", ss.Seq, err)
+		}
+		es = &dec
+	}
+
 	// Consensus/config bits
 	t.cfg = ss.Cfg
 	t.seq = ss.Seq
+	// the log restarts at the snapshot; everything before it is history
+	t.log = t.log[:0]
+	t.logBase = 0
+	t.compactTo(ss.Seq)
 	t.epoch = ss.Epoch
-	t.authorityID = ss.Authority
+	t.authorityIs(ss.Authority)
+	t.bans = make(map[string]struct{}, len(ss.Bans))
+	for _, pid := range ss.Bans {
+		t.bans[pid] = struct{}{}
+	}
+	t.waiting = append([]string{}, ss.Waiting...)
+	t.observers = append([]string{}, ss.Observers...)
+	t.level, t.levelSince = 0, time.Time{}
+	if n := len(t.cfg.BlindSchedule); n > 0 {
+		t.level = min(ss.Level, n-1)
+		t.levelSince = t.now() // time this level from here
+	}
+	t.seedRound = ss.SeedRound
+	t.seedCommits = copySeeds(ss.SeedCommits)
+	t.seedReveals = copySeeds(ss.SeedReveals)
+	t.seedBarred = nil
+	for _, pid := range ss.SeedBarred {
+		t.barSeed(pid)
+	}
 
 	// Engine state (if provided)
-	if len(ss.EngineJSON) > 0 {
-		var es engine.EngineSnapshot
-		if err := json.Unmarshal(ss.EngineJSON, &es); err == nil {
-			t.eng.RestoreFromSnapshot(es)
-		} else {
-			// Fallback: at least keep blinds aligned
-			t.eng.SmallBlind = t.cfg.SmallBlind
-			t.eng.BigBlind = t.cfg.BigBlind
-		}
+	if es != nil {
+		t.eng.RestoreFromSnapshot(*es)
+		// adopt the authority's chips as our accounting baseline
+		t.chipsIn = t.eng.TotalChips()
 	} else {
-		// Older peer without engine payload
+		// Older peer without an engine payload: at least keep blinds aligned
 		t.eng.SmallBlind = t.cfg.SmallBlind
 		t.eng.BigBlind = t.cfg.BigBlind
 	}
+	return nil
 }
 
-// Authority sends a snapshot (used by /discover and resync)
+// Authority sends a snapshot (used by /discover and resync). Sent to target
+// alone it carries target's hole cards; if the transport has to broadcast it
+// instead, it falls back to the hole-free snapshot.
 func (t *Table) sendSnapshotTo(target protocol.NodeID) {
 	if !t.authority {
 		return
 	}
-	ss := t.snapshot()
+	own, public := t.SnapshotFor(target), t.snapshot()
 	t.netOut <- protocol.NetMessage{
-		Table:   t.id,
-		From:    t.self,
-		Type:    protocol.MsgSnapshot,
-		Epoch:   t.epoch,
-		Lamport: t.clock.TickLocal(),
-		State:   &ss,
+		Table:    t.id,
+		From:     t.self,
+		Type:     protocol.MsgSnapshot,
+		Epoch:    t.epoch,
+		Lamport:  t.clock.TickLocal(),
+		State:    &own,
+		Fallback: &public,
+		To:       target, // only the requester needs it
 	}
 }
@@ -0,0 +1,228 @@
+package table
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func holesIn(t *testing.T, ss *protocol.TableSnapshot) map[engine.PlayerID][]engine.Card {
+	t.Helper()
+	var es engine.EngineSnapshot
+	if err := json.Unmarshal(ss.EngineJSON, &es); err != nil {
+		t.Fatal(err)
+	}
+	return es.Holes
+}
+
+func TestUnicastSnapshotCarriesTheRequestersHoleCards(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.recv(protocol.NetMessage{From: "a", Type: protocol.MsgStateQuery})
+
+	msgs := h.sentOf(protocol.MsgSnapshot)
+	if len(msgs) != 1 {
+		t.Fatalf("want one snapshot, got %d", len(msgs))
+	}
+	msg := msgs[0]
+	if msg.To != "a" {
+		t.Fatalf("snapshot addressed to %q, want a", msg.To)
+	}
+	if holes := holesIn(t, msg.State); len(holes) != 1 || len(holes["a"]) != 2 {
+		t.Fatalf("unicast snapshot holes = %v, want a's two cards only", holes)
+	}
+	if holes := holesIn(t, msg.Broadcastable().State); len(holes) != 0 {
+		t.Fatalf("broadcast fallback leaks hole cards: %v", holes)
+	}
+	if data, err := json.Marshal(msg); err != nil {
+		t.Fatal(err)
+	} else {
+		var back protocol.NetMessage
+		_ = json.Unmarshal(data, &back)
+		if back.Fallback != nil {
+			t.Fatal("fallback went over the wire")
+		}
+	}
+}
+
+func TestBroadcastSnapshotNeverCarriesHoleCards(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	h.must(protocol.ActStartHand, "me", 0)
+
+	var (
+		dealt    map[engine.PlayerID][]engine.Card
+		bcast    protocol.TableSnapshot
+		targeted protocol.TableSnapshot
+	)
+	h.on(func(tb *Table) {
+		dealt = tb.eng.Holes
+		bcast, targeted = tb.Snapshot(), tb.SnapshotFor("b")
+	})
+	if holes := holesIn(t, &bcast); len(holes) != 0 {
+		t.Fatalf("broadcast snapshot holds %v", holes)
+	}
+	// preflop there is no board: any card in the payload would be a hole card
+	for pid, cs := range dealt {
+		for _, c := range cs {
+			enc, err := json.Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(bcast.EngineJSON, enc) {
+				t.Errorf("broadcast snapshot mentions %s's %v", pid, c)
+			}
+		}
+	}
+
+	holes := holesIn(t, &targeted)
+	if len(holes) != 1 || !slices.Equal(holes["b"], dealt["b"]) {
+		t.Fatalf("snapshot for b holds %v, want b's %v only", holes, dealt["b"])
+	}
+}
+
+func TestFollowerResyncsWhenACommitWillNotApply(t *testing.T) {
+	h := newHarness(t, testConfig())
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) { tb.authorityID = "me" })
+	h.join("a", "b", "c")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.relay(f)
+
+	// f has lost track of whose turn it is, so the next commit is not legal there
+	f.on(func(tb *Table) { tb.eng.TurnIdx = (tb.eng.TurnIdx + 1) % len(tb.eng.Order) })
+	h.actTurn(protocol.ActCall, 0)
+	h.relay(f)
+
+	queries := f.sentOf(protocol.MsgStateQuery)
+	if len(queries) != 1 {
+		t.Fatalf("follower sent %d state queries, want 1", len(queries))
+	}
+	h.recv(queries[0])
+	snaps := h.sentOf(protocol.MsgSnapshot)
+	if len(snaps) != 1 {
+		t.Fatalf("authority answered with %d snapshots", len(snaps))
+	}
+	f.recv(snaps[0])
+
+	var want, got struct {
+		seq  uint64
+		hash uint64
+	}
+	h.on(func(tb *Table) { want.seq, want.hash = tb.seq, tb.eng.Hash() })
+	f.on(func(tb *Table) { got.seq, got.hash = tb.seq, tb.eng.Hash() })
+	if got != want {
+		t.Fatalf("follower at seq %d (hash %x) after the resync, authority at %d (%x)", got.seq, got.hash, want.seq, want.hash)
+	}
+}
+
+func TestStackDriftIsCaughtByTheStateHash(t *testing.T) {
+	h := newHarness(t, testConfig())
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) { tb.authorityID = "me" })
+	h.join("a", "b")
+	h.relay(f)
+	var hh, fh uint64
+	h.on(func(tb *Table) { hh = tb.eng.Hash() })
+	f.on(func(tb *Table) { fh = tb.eng.Hash() })
+	if hh != fh {
+		t.Fatalf("replicas of the same state hash %x and %x", hh, fh)
+	}
+	if q := f.sentOf(protocol.MsgStateQuery); len(q) != 0 {
+		t.Fatalf("%d state queries while in step", len(q))
+	}
+
+	// the follower's copy of a's stack goes wrong; the commit still applies
+	f.on(func(tb *Table) { tb.eng.Seats["a"].Stack += 7 })
+	h.join("c")
+	h.relay(f)
+	if f.seat("c") == nil {
+		t.Fatal("the JOIN did not apply on the follower")
+	}
+	if q := f.sentOf(protocol.MsgStateQuery); len(q) != 1 {
+		t.Fatalf("after the drift: %d state queries, want 1", len(q))
+	}
+}
+
+func TestFollowerRejectsAnInconsistentSnapshot(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+	var good protocol.TableSnapshot
+	h.on(func(tb *Table) { good = tb.Snapshot() })
+
+	rejected := func(t *testing.T, ss protocol.TableSnapshot) {
+		t.Helper()
+		f := newHarnessAs(t, testConfig(), "f", false)
+		f.on(func(tb *Table) { tb.authorityID = "me" })
+		f.recv(protocol.NetMessage{Type: protocol.MsgSnapshot, From: "me", Epoch: ss.Epoch, Seq: ss.Seq, State: &ss})
+
+		var seq uint64
+		var seated int
+		f.on(func(tb *Table) { seq, seated = tb.seq, len(tb.eng.Seats) })
+		if seq != 0 || seated != 0 {
+			t.Fatalf("follower adopted the snapshot: seq %d, %d seated", seq, seated)
+		}
+		if n := len(f.sentOf(protocol.MsgStateQuery)); n != 1 {
+			t.Fatalf("follower sent %d state queries after the bad snapshot, want 1", n)
+		}
+	}
+	for name, corrupt := range map[string]func(es *engine.EngineSnapshot){
+		"pot short of the blinds": func(es *engine.EngineSnapshot) { es.Pot = 1 },
+		"turn past the last seat": func(es *engine.EngineSnapshot) { es.TurnIdx = len(es.Order) },
+		"a seat nobody sits in":   func(es *engine.EngineSnapshot) { es.Order = append(es.Order, "ghost") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var es engine.EngineSnapshot
+			if err := json.Unmarshal(good.EngineJSON, &es); err != nil {
+				t.Fatal(err)
+			}
+			corrupt(&es)
+			bad, err := json.Marshal(es)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ss := good
+			ss.EngineJSON = bad
+			rejected(t, ss)
+		})
+	}
+	t.Run("engine state that does not decode", func(t *testing.T) {
+		ss := good
+		ss.EngineJSON = []byte(`{"Seats": 7}`)
+		rejected(t, ss)
+	})
+}
+
+func TestOpenCardsShowEveryHandToFollowers(t *testing.T) {
+	for _, open := range []bool{true, false} {
+		cfg := testConfig()
+		cfg.OpenCards = open
+		h := newHarness(t, cfg)
+		f := newHarnessAs(t, cfg, "f", false)
+		f.on(func(tb *Table) { tb.authorityID = "me" })
+		h.join("a", "b")
+		h.must(protocol.ActStartHand, "me", 0)
+		h.relay(f)
+
+		var v View
+		var ss protocol.TableSnapshot
+		f.on(func(tb *Table) { v = tb.ViewFor("a") })
+		h.on(func(tb *Table) { ss = tb.Snapshot() })
+		if open {
+			if len(v.AllHoles) != 2 || len(v.AllHoles["b"]) != 2 {
+				t.Errorf("open cards: a's view shows %v, want both hands", v.AllHoles)
+			}
+			if holes := holesIn(t, &ss); len(holes) != 2 {
+				t.Errorf("open cards: broadcast snapshot holds %v, want both hands", holes)
+			}
+		} else if v.AllHoles != nil || len(holesIn(t, &ss)) != 0 {
+			t.Errorf("closed cards leaked: view %v, snapshot %v", v.AllHoles, holesIn(t, &ss))
+		}
+	}
+}
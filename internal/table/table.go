@@ -1,6 +1,12 @@
 package table
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
 	"time"
 
 	"p2poker/internal/engine"
@@ -23,21 +29,76 @@ type Table struct {
 	authority bool
 	epoch     protocol.Epoch
 	clock     *protocol.Lamport
+	ids       *protocol.IDGen // source for locally generated action ids
 
 	in     <-chan protocol.NetMessage
 	netOut chan<- protocol.NetMessage
+	local  chan []protocol.Action // local proposals, drained by Run (single writer)
+	calls  chan func()            // work that must run on the table loop (see exec)
 
 	// consensus-ish bits
 	seq         uint64
 	log         []protocol.Action
-	dedup       map[string]struct{}
+	dedup       map[string]uint64 // action id -> seq it was committed at (see compact.go)
+	logBase     uint64            // seq just before t.log[0]; older ids are pruned from dedup
+	followups   []protocol.Action // authority: queued by apply, committed after (see followup)
+	paused      bool              // authority: auto-start is waiting for MinPlayers (see maybeAutoStart)
 	followers   map[protocol.NodeID]struct{}
 	authorityID protocol.NodeID
 
+	interceptors []ActionInterceptor        // authority: house rules applied to proposals (see intercept.go)
+	dropped      map[string]struct{}        // authority: players sat out because their connection dropped
+	handActions  int                        // actions applied in the current (or last) hand, for HandLogSummary
+	handPlayers  []string                   // dealt into the current (or last) hand, for HandLogSummary
+	handSeed     int64                      // the current (or last) hand's shuffle seed, for RevealSeed
+	wal          *os.File                   // cfg.LogPath, open while Run is (see wal.go)
+	pending      map[uint64]protocol.Action // follower: commits that arrived ahead of a gap (see reorder.go)
+	verdicts     map[string]chan error      // follower: ProposeWait callers by action id
+	gapSince     time.Time                  // when the oldest pending commit arrived
+	level        int                        // current index into cfg.BlindSchedule (see levels.go)
+	levelSince   time.Time                  // when it began; zero until the first hand
+	seedRound    uint64                     // commit-reveal round, bumped as each ends (see seeds.go)
+	seedCommits  map[string]string          // player -> seed commitment this round
+	seedReveals  map[string]int64           // player -> revealed seed this round
+	seedBarred   map[string]struct{}        // players who forfeited this round; sat out until the deal
+	seedDeadline time.Time                  // authority: when players owing a commitment or reveal are sat out
+	secret       seedSecret                 // local only: this node's own seed
+
+	// seating (replicated via commits and snapshots; see join.go)
+	bans      map[string]struct{} // kicked players may not rejoin
+	waiting   []string            // FIFO of players waiting for a free seat
+	observers []string            // spectators: following the table without a seat
+
 	eng engine.State
 
+	// accounting: chips brought to the table (buy-ins) minus chips taken away.
+	// There is no rake, so eng.TotalChips() must always equal this.
+	chipsIn int64
+
 	// timers
+	now           func() time.Time // the table's clock: time.Now, but for tests
+	levelTimer    deadlineTimer    // see levels.go
+	turnTimer     deadlineTimer    // see turn.go
+	seedTimer     deadlineTimer    // see seeds.go
 	lastHeartbeat time.Time
+	lastResync    time.Time  // rate-limits state queries (see requestResync)
+	jitter        *rand.Rand // spreads follower timeouts (see followerTimeout)
+	announce      bool       // authority: broadcast a snapshot on the next heartbeat tick
+	turnDeadline  time.Time  // see turn.go
+	turnOf        string
+	turnPhase     engine.Phase
+
+	events chan TableEvent
+	subMu  sync.Mutex
+	subs   []subscription // see Subscribe
+	ended  bool           // Run has exited; subscriptions are closed
+
+	// lifecycle
+	stop     chan struct{}
+	stopOnce sync.Once
+	closing  bool   // set when a CLOSE_TABLE commit has been applied
+	onClose  func() // owner teardown hook (see OnClose)
+	onBust   func(players []string)
 }
 
 type gameState struct {
@@ -54,22 +115,49 @@ func New(
 	authority bool,
 	epoch protocol.Epoch,
 	clock *protocol.Lamport,
+	ids *protocol.IDGen,
 	in <-chan protocol.NetMessage,
 	out chan<- protocol.NetMessage,
 ) *Table {
 	return &Table{
-		id: id, self: self, cfg: cfg, authority: authority, epoch: epoch, clock: clock,
-		in: in, netOut: out,
-		seq: 0, log: make([]protocol.Action, 0, 1024), dedup: make(map[string]struct{}), followers: make(map[protocol.NodeID]struct{}),
+		id: id, self: self, cfg: cfg, authority: authority, epoch: epoch, clock: clock, ids: ids,
+		in: in, netOut: out, local: make(chan []protocol.Action, 64), calls: make(chan func()),
+		seq: 0, log: make([]protocol.Action, 0, 1024), dedup: make(map[string]uint64), followers: make(map[protocol.NodeID]struct{}),
+		bans: make(map[string]struct{}), dropped: make(map[string]struct{}), verdicts: make(map[string]chan error),
 		authorityID: func() protocol.NodeID {
 			if authority {
 				return self
 			}
 			return ""
 		}(),
-		eng:           engine.NewState(cfg.SmallBlind, cfg.BigBlind),
+		eng:           newEngine(cfg),
+		now:           time.Now,
 		lastHeartbeat: time.Now(),
+		jitter:        rand.New(rand.NewSource(jitterSeed(ids, self))),
+		stop:          make(chan struct{}),
+		events:        make(chan TableEvent, 256),
+	}
+}
+
+func newEngine(cfg types.TableConfig) engine.State {
+	eng := engine.NewState(cfg.SmallBlind, cfg.BigBlind)
+	eng.Variant = cfg.Variant
+	eng.MinPlayers = cfg.MinPlayers
+	eng.RoundRobin = cfg.DealRoundRobin
+	eng.OpenCards = cfg.OpenCards
+	eng.BurnCards = cfg.BurnCards
+	eng.Straddles = cfg.Straddles
+	eng.ButtonStraddle = cfg.ButtonStraddle
+	eng.HiLo = cfg.HiLo
+	eng.SitOutBlinds = cfg.SitOutBlinds
+	eng.Structure = cfg.BettingStructure
+	eng.MaxSeats = seatLimit(cfg)
+	eng.MinBuyin = cfg.MinBuyin
+	eng.MaxBuyin = buyinCap(cfg)
+	if cfg.OpenCards {
+		log.Printf("table %s: OpenCards is on — hole cards are public at this table", cfg.Name)
 	}
+	return eng
 }
 
 func (t *Table) ID() protocol.TableID         { return t.id }
@@ -78,24 +166,80 @@ func (t *Table) Epoch() protocol.Epoch        { return t.epoch }
 func (t *Table) AuthorityID() protocol.NodeID { return t.authorityID }
 func (t *Table) Eng() *engine.State           { return &t.eng }
 
-// Run drives the event loop. When authority, it emits heartbeats.
+// OnClose registers a hook invoked once a committed CLOSE_TABLE has been
+// applied and broadcast. The owner (TableManager) uses it to tear the table down.
+func (t *Table) OnClose(fn func()) { t.onClose = fn }
+
+// OnBust registers fn to be called on the table loop after every hand that
+// leaves players without chips, with those players in finishing order (see
+// announceBusts). Every replica calls it as it applies the hand, so unlike
+// PLAYER_BUSTED events it is never dropped. It returns the players seated as
+// fn is registered, and those of them who already have no chips.
+func (t *Table) OnBust(fn func(players []string)) (seated, broke []string) {
+	t.exec(func() {
+		t.onBust = fn
+		for _, pid := range t.eng.Order {
+			seated = append(seated, pid)
+			if t.eng.Seats[pid].Stack == 0 && !t.eng.HandActive {
+				broke = append(broke, pid)
+			}
+		}
+	})
+	return seated, broke
+}
+
+// Stop terminates the Run loop. Safe to call more than once.
+func (t *Table) Stop() { t.stopOnce.Do(func() { close(t.stop) }) }
+
+// Run drives the event loop until Stop is called. When authority, it emits heartbeats.
 func (t *Table) Run() {
 	heartbeat := time.NewTicker(maxDur(t.cfg.AuthorityTick, 500*time.Millisecond))
 	defer heartbeat.Stop()
+	defer t.levelTimer.stop()
+	defer t.turnTimer.stop()
+	defer t.seedTimer.stop()
+	defer t.closeSubscriptions()
+	t.openWAL()
+	defer t.closeWAL()
 
 	for {
 		if t.authority {
 			select {
+			case <-t.stop:
+				return
+			case batch := <-t.local:
+				t.proposeBatch(batch)
+			case fn := <-t.calls:
+				fn()
 			case msg := <-t.in:
 				t.onNet(msg)
 			case <-heartbeat.C:
 				t.sendHeartbeat()
+				if t.announce {
+					t.announce = false
+					t.sendSnapshotTo("") // broadcast in real network layer
+				}
+			case <-t.turnTimer.wait(t.turnDeadline, t.now()):
+				t.turnTimer.fired()
+				t.onTurnTimeout()
+			case <-t.levelTimer.wait(t.levelDeadline(), t.now()):
+				t.levelTimer.fired()
+				t.onLevelUp()
+			case <-t.seedTimer.wait(t.seedDeadline, t.now()):
+				t.seedTimer.fired()
+				t.onSeedTimeout()
 			}
 		} else {
 			select {
+			case <-t.stop:
+				return
+			case batch := <-t.local:
+				t.proposeBatch(batch)
+			case fn := <-t.calls:
+				fn()
 			case msg := <-t.in:
 				t.onNet(msg)
-			case <-time.After(maxDur(t.cfg.FollowerTO, 3*time.Second)):
+			case <-time.After(t.followerTimeout()):
 				t.tryAuthorityTakeover()
 			}
 		}
@@ -114,91 +258,404 @@ func (t *Table) onNet(msg protocol.NetMessage) {
 		if msg.Action == nil {
 			return
 		}
-		// AUTH GUARD: only allow KICK if proposer is the current authority
-		if msg.Action.Type == protocol.ActKick && msg.From != t.authorityID {
-			// ignore unauthorized kick proposal
+		*msg.Action = fromPeer(*msg.Action)
+		// AUTH GUARD: only the authority itself may propose KICK, CLOSE_TABLE,
+		// RESET or BLIND_LEVEL (see authorityOnly)
+		if authorityOnly(msg.Action.Type) && msg.From != t.authorityID {
+			t.reject(msg.From, *msg.Action, reasonAuthorityOnly)
 			return
 		}
 
-		t.commitAndBroadcast(*msg.Action)
-	case protocol.MsgCommit:
-		if msg.Action == nil {
+		if t.collides(*msg.Action) {
+			t.reject(msg.From, *msg.Action, reasonCollision)
 			return
 		}
-		if msg.Epoch < t.epoch {
+		if t.duplicate(msg.Action.ID) {
+			return // a re-send of something already applied
+		}
+		// Seq is the proposer's view of the log: one older than our compacted
+		// history may be a re-send whose id dedup has forgotten.
+		if t.behind(msg.Seq) {
+			t.reject(msg.From, *msg.Action, reasonStale)
+			return
+		}
+		a, err := t.intercept(*msg.Action)
+		if err == nil {
+			err = t.commitAndBroadcast(a)
+		}
+		if err != nil {
+			t.reject(msg.From, a, err.Error())
+		}
+	case protocol.MsgCommit:
+		if msg.Action == nil {
 			return
 		}
-		// AUTH GUARD: only accept KICK commits if they came from the authority
-		if msg.Action.Type == protocol.ActKick && msg.From != t.authorityID {
+		// only the authority commits, so this also covers KICK/CLOSE_TABLE
+		if !t.fence(msg) {
 			return
 		}
 
+		t.turnDeadline = fromUnixMilli(msg.TurnDeadline)
 		t.applyCommit(*msg.Action, msg.Seq)
-		if msg.Epoch > t.epoch || t.authorityID == "" {
-			t.epoch = msg.Epoch
-			t.authorityID = msg.From
-		}
+		t.checkHash(msg)
 		t.lastHeartbeat = time.Now()
 	case protocol.MsgSnapshot:
 		if msg.State == nil {
 			return
 		}
-		if msg.Epoch < t.epoch {
+		if !t.fence(msg) {
 			return
 		}
-		t.installSnapshot(*msg.State)
+		if err := t.installSnapshot(*msg.State); err != nil {
+			log.Printf("table %s: rejecting snapshot from %s: %v", t.id, msg.From, err)
+			t.requestResync()
+			return
+		}
+		t.walSnapshot()
+		t.drainPending()
+		t.seedTurn()
 		t.lastHeartbeat = time.Now()
 	case protocol.MsgHeartbeat:
-		if msg.Epoch < t.epoch {
+		if !t.fence(msg) {
 			return
 		}
-		t.epoch = msg.Epoch
-		t.authorityID = msg.From
+		t.checkGap()
+		t.checkHash(msg)
+		t.turnDeadline = fromUnixMilli(msg.TurnDeadline)
 		t.lastHeartbeat = time.Now()
 	case protocol.MsgStateQuery:
 		if t.authority {
 			t.sendSnapshotTo(msg.From)
 		}
+	case protocol.MsgReject:
+		if msg.Action == nil || msg.To != t.self || msg.From != t.authorityID {
+			return
+		}
+		if msg.Reason == reasonStale {
+			// it may or may not have applied before; catch up and let the
+			// player decide whether to propose it again
+			log.Printf("table %s: %s %s rejected: %s; resyncing", t.id, msg.Action.Type, msg.Action.ID, msg.Reason)
+			t.settle(msg.Action.ID, &RejectedError{Action: *msg.Action, Reason: msg.Reason})
+			t.requestResync()
+			return
+		}
+		if msg.Reason != reasonCollision {
+			log.Printf("table %s: %s %s rejected: %s", t.id, msg.Action.Type, msg.Action.ID, msg.Reason)
+			t.settle(msg.Action.ID, &RejectedError{Action: *msg.Action, Reason: msg.Reason})
+			return
+		}
+		// An id collision: propose again under a fresh id.
+		a := *msg.Action
+		a.ID = t.ids.ActionID()
+		log.Printf("table %s: %s %s rejected (%s); re-proposing as %s", t.id, msg.Action.Type, msg.Action.ID, msg.Reason, a.ID)
+		if ch, ok := t.verdicts[msg.Action.ID]; ok {
+			delete(t.verdicts, msg.Action.ID)
+			t.verdicts[a.ID] = ch
+		}
+		t.propose(a)
+	}
+}
+
+// reasonCollision is the reject reason for a proposal whose id was already
+// committed to a different action; the proposer retries under a fresh id.
+// Any other reason is final: the engine refused the action, or (reasonStale)
+// the proposer was too far behind to tell a re-send from a new action.
+const reasonCollision = "action id already committed"
+
+// reasonAuthorityOnly is the reject reason for a peer's proposal of an action
+// only the authority may make (see authorityOnly).
+const reasonAuthorityOnly = "only the authority may propose this"
+
+// RejectedError is the authority's refusal of an action this node proposed.
+type RejectedError struct {
+	Action protocol.Action
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s rejected by the authority: %s", e.Action.Type, e.Reason)
+}
+
+// ErrNoVerdict is ProposeWait giving up on hearing whether its action
+// was committed.
+var ErrNoVerdict = errors.New("no word from the authority on the action")
+
+// reasonStale is the reject reason for a proposal made against a seq older
+// than the authority's compacted log (see behind).
+const reasonStale = "proposed against compacted history; catch up and propose again"
+
+// reject NACKs a proposal back to the node that sent it.
+func (t *Table) reject(to protocol.NodeID, a protocol.Action, reason string) {
+	log.Printf("table %s: rejecting %s %s from %s: %s", t.id, a.Type, a.ID, to, reason)
+	t.netOut <- protocol.NetMessage{
+		Table: t.id, From: t.self, Type: protocol.MsgReject, Epoch: t.epoch,
+		Lamport: t.clock.TickLocal(), Seq: t.seq, Action: &a, To: to, Reason: reason,
 	}
 }
 
 // ProposeLocal submits an action originating from this node.
-func (t *Table) ProposeLocal(a protocol.Action) {
-	if t.authority {
-		t.commitAndBroadcast(a)
+// It only enqueues; the Run loop commits (authority) or forwards (follower) it.
+func (t *Table) ProposeLocal(a protocol.Action) { t.ProposeBatch([]protocol.Action{a}) }
+
+// ProposeSync is ProposeLocal, but returns only once the loop has handled a:
+// committed it (authority) or sent it on to the authority (follower). On the
+// authority it returns the engine's error if a was refused; a follower learns
+// of a refusal later, from the authority's REJECT.
+func (t *Table) ProposeSync(a protocol.Action) error {
+	var err error
+	t.exec(func() { err = t.propose(a) })
+	return err
+}
+
+// ProposeWait is ProposeSync that, on a follower, also waits for the
+// authority's verdict on a: nil once a is committed, a *RejectedError if the
+// authority refuses it, or ErrNoVerdict if neither arrives within timeout.
+// Scripts use it so a refused action fails wherever the authority runs.
+func (t *Table) ProposeWait(a protocol.Action, timeout time.Duration) error {
+	var verdict chan error
+	var err error
+	t.exec(func() {
+		if !t.authority {
+			verdict = make(chan error, 1)
+			t.verdicts[a.ID] = verdict
+		}
+		if err = t.propose(a); err != nil || t.authority {
+			delete(t.verdicts, a.ID)
+			verdict = nil
+		}
+	})
+	if verdict == nil {
+		return err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err = <-verdict:
+		return err
+	case <-timer.C:
+	case <-t.stop:
+	}
+	t.exec(func() { delete(t.verdicts, a.ID) })
+	return ErrNoVerdict
+}
+
+// settle hands a ProposeWait caller waiting on id its verdict.
+func (t *Table) settle(id string, err error) {
+	if ch, ok := t.verdicts[id]; ok {
+		delete(t.verdicts, id)
+		ch <- err
+	}
+}
+
+// ProposeBatch submits several actions that are handled back-to-back, in order,
+// by the Run loop — the same single-writer path as ProposeLocal.
+func (t *Table) ProposeBatch(as []protocol.Action) {
+	if len(as) == 0 {
 		return
 	}
+	batch := append([]protocol.Action(nil), as...)
+	select {
+	case t.local <- batch:
+	case <-t.stop:
+	}
+}
+
+// exec runs fn on the table loop and waits for it to finish.
+// It returns without running fn if the table is stopped.
+func (t *Table) exec(fn func()) {
+	done := make(chan struct{})
+	select {
+	case t.calls <- func() { fn(); close(done) }:
+		<-done
+	case <-t.stop:
+	}
+}
+
+func (t *Table) proposeBatch(batch []protocol.Action) {
+	for _, a := range batch {
+		if t.closing {
+			return
+		}
+		t.propose(a)
+	}
+}
+
+// propose runs on the table loop only.
+func (t *Table) propose(a protocol.Action) error {
+	if t.authority {
+		if t.collides(a) {
+			id := t.ids.ActionID()
+			log.Printf("table %s: %s id %s already committed; using %s", t.id, a.Type, a.ID, id)
+			a.ID = id
+		}
+		a, err := t.intercept(a)
+		if err != nil {
+			return err
+		}
+		return t.commitAndBroadcast(a)
+	}
 	t.netOut <- protocol.NetMessage{
 		Table: t.id, From: t.self, Type: protocol.MsgPropose, Epoch: t.epoch,
-		Lamport: t.clock.TickLocal(), Action: &a,
+		Lamport: t.clock.TickLocal(), Seq: t.seq, Action: &a,
+	}
+	return nil
+}
+
+// commitAndBroadcast commits a, then any follow-up actions apply queued
+// (auto-advance, showdown, auto-check), each with its own seq, in order.
+// An action the engine refuses is not committed at all: seq stays put, nothing
+// is logged or broadcast, and the engine's error is returned.
+func (t *Table) commitAndBroadcast(a protocol.Action) error {
+	if err := t.commitOne(a); err != nil {
+		return err
 	}
+	for len(t.followups) > 0 && !t.closing {
+		next := t.followups[0]
+		t.followups = t.followups[1:]
+		t.commitOne(next)
+	}
+	t.followups = t.followups[:0]
+	t.maybeClose()
+	t.seedTurn()
+	return nil
 }
 
-func (t *Table) commitAndBroadcast(a protocol.Action) {
+func (t *Table) commitOne(a protocol.Action) error {
 	if _, seen := t.dedup[a.ID]; seen {
-		return
+		return nil
 	}
-	t.seq++
-	t.apply(a)
-	t.log = append(t.log, a)
-	t.dedup[a.ID] = struct{}{}
+	a = t.stampSeed(a)
+	t.seq++ // apply runs at the seq it would commit at (events carry it)
+	if err := t.apply(a); err != nil {
+		t.seq--
+		return err
+	}
+	t.record(a)
 
 	t.netOut <- protocol.NetMessage{
 		Table: t.id, From: t.self, Type: protocol.MsgCommit, Epoch: t.epoch, Lamport: t.clock.TickLocal(), Seq: t.seq, Action: &a,
+		TurnDeadline: unixMilli(t.turnDeadline), StateHash: t.eng.Hash(),
 	}
+	return nil
+}
+
+// followup queues an authority-generated action to be committed right after
+// the one currently being applied. Never commit from inside apply directly:
+// that would broadcast the follow-up ahead of its cause.
+func (t *Table) followup(typ protocol.ActionType, player string) {
+	t.followups = append(t.followups, protocol.Action{ID: t.ids.ActionID(), Type: typ, PlayerID: player})
 }
 
 func (t *Table) applyCommit(a protocol.Action, seq uint64) {
-	if _, seen := t.dedup[a.ID]; seen {
+	if _, seen := t.dedup[a.ID]; seen || seq <= t.seq {
+		// already applied, or covered by a snapshot we installed
 		return
 	}
 	if seq != t.seq+1 {
-		// gap: request snapshot
-		t.netOut <- protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgStateQuery, Epoch: t.epoch, Lamport: t.clock.TickLocal()}
+		// gap: hold it until the missing commits arrive (see reorder.go)
+		t.holdCommit(a, seq)
 		return
 	}
+	t.applyNext(a, seq)
+	t.drainPending()
+}
+
+// applyNext applies the commit at seq == t.seq+1.
+func (t *Table) applyNext(a protocol.Action, seq uint64) {
 	t.seq = seq
-	t.apply(a)
-	t.log = append(t.log, a)
-	t.dedup[a.ID] = struct{}{}
+	err := t.apply(a)
+	t.record(a)
+	if err != nil && !t.authority {
+		// A commit is authoritative: if we can't apply it we have diverged.
+		log.Printf("table %s: cannot apply commit seq=%d (%v); requesting resync", t.id, seq, err)
+		t.requestResync()
+	}
+	t.maybeClose()
+	t.seedTurn()
+}
+
+// checkHash compares the authority's state hash for msg.Seq with ours once we
+// are at that seq: a mismatch means this replica has diverged, so it asks for
+// a fresh snapshot.
+func (t *Table) checkHash(msg protocol.NetMessage) {
+	if t.authority || msg.StateHash == 0 || msg.Seq != t.seq {
+		return
+	}
+	if h := t.eng.Hash(); h != msg.StateHash {
+		log.Printf("table %s: state diverged from authority at seq %d (hash %x, authority %x); requesting resync", t.id, t.seq, h, msg.StateHash)
+		t.requestResync()
+	}
+}
+
+// resyncInterval is the minimum gap between state queries from one follower.
+const resyncInterval = time.Second
+
+// requestResync asks the authority for a full snapshot.
+func (t *Table) requestResync() {
+	// A bad authority answers with the same bad snapshot; don't spin on it.
+	if time.Since(t.lastResync) < resyncInterval {
+		return
+	}
+	t.lastResync = time.Now()
+	t.netOut <- protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgStateQuery, Epoch: t.epoch, Lamport: t.clock.TickLocal()}
+}
+
+// maybeClose runs the teardown hook once a CLOSE_TABLE has been committed.
+func (t *Table) maybeClose() {
+	if !t.closing {
+		return
+	}
+	if t.onClose != nil {
+		t.onClose()
+	}
+	t.Stop()
+}
+
+// reset returns the table to a fresh session between hands: new engine,
+// no bans or waiting list, no history. Seq, epoch and authority carry on so
+// consensus is undisturbed. Kept players keep their seats and stacks; only
+// what the chips were won from is forgotten.
+func (t *Table) reset(keepSeats bool) error {
+	if t.eng.HandActive {
+		return errors.New("reset: a hand is in progress")
+	}
+	type kept struct {
+		pid   string
+		stack int64
+	}
+	var seated []kept
+	for _, pid := range t.eng.Order {
+		seated = append(seated, kept{pid, t.eng.Seats[pid].Stack})
+	}
+	t.eng = newEngine(t.cfg)
+	t.chipsIn = 0
+	t.bans = make(map[string]struct{})
+	t.dropped = make(map[string]struct{})
+	t.waiting = nil
+	t.observers = nil
+	t.paused = false
+	t.level = 0
+	t.levelSince = time.Time{}
+	t.clearSeeds()
+	t.seedBarred = nil
+	if keepSeats {
+		for _, s := range seated {
+			if err := t.eng.SitStack(s.pid, s.stack); err == nil {
+				t.chipsIn += s.stack
+			}
+		}
+	}
+	// history restarts at the reset itself (seq was already bumped for it)
+	t.log = t.log[:0]
+	t.compactTo(t.seq - 1)
+	return nil
+}
+
+// authorityOnly reports whether an action may only be proposed/committed by the authority.
+func authorityOnly(typ protocol.ActionType) bool {
+	switch typ {
+	case protocol.ActKick, protocol.ActCloseTable, protocol.ActReset, protocol.ActBlindLevel:
+		return true
+	}
+	return false
 }
@@ -1,12 +1,32 @@
 package table
 
 import (
+	"hash/fnv"
 	"log"
 	"time"
 
 	"p2poker/internal/protocol"
 )
 
+// followerTimeout is how long a follower waits on a quiet authority before
+// trying to take over: FollowerTO (at least 3s) plus up to half again, drawn
+// afresh each time. The spread lets the first follower to wake claim the
+// table and be heard before the rest wake, rather than all of them claiming
+// it (and querying it) in the same instant.
+func (t *Table) followerTimeout() time.Duration {
+	base := maxDur(t.cfg.FollowerTO, 3*time.Second)
+	return base + time.Duration(t.jitter.Int63n(int64(base)/2))
+}
+
+// jitterSeed seeds a table's followerTimeout spread. Nodes started with the
+// same -seed draw the same ids, so self is mixed in: otherwise every follower
+// would draw the same timeouts and wake together after all.
+func jitterSeed(ids *protocol.IDGen, self protocol.NodeID) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(self))
+	return ids.Seed() ^ int64(h.Sum64())
+}
+
 func (t *Table) tryAuthorityTakeover() {
 	if t.authority {
 		return
@@ -17,20 +37,90 @@ func (t *Table) tryAuthorityTakeover() {
 	if !t.isSmallestNodeID() {
 		return
 	}
-	// Takeover
+	// A heartbeat may be queued behind the timeout that got us here: handle
+	// what has already arrived and look again before claiming the table.
+	for queued := true; queued; {
+		select {
+		case msg := <-t.in:
+			t.onNet(msg)
+		default:
+			queued = false
+		}
+	}
+	if t.authority || time.Since(t.lastHeartbeat) < maxDur(t.cfg.FollowerTO, 3*time.Second) {
+		return
+	}
+	t.assumeAuthority()
+}
+
+// assumeAuthority makes this node the authority in a new epoch and tells
+// the mesh, which fences off anyone still committing in the old one. The
+// heartbeat goes out now, to quiet the other followers' timers; the snapshot
+// waits for the next heartbeat tick, so a takeover is not one burst.
+func (t *Table) assumeAuthority() {
 	t.authority = true
 	t.epoch++
-	t.authorityID = t.self
+	t.authorityIs(t.self)
 	log.Printf("table %s: %s assumes authority, epoch=%d", t.id, t.self, t.epoch)
 	t.sendHeartbeat()
-	t.sendSnapshotTo("") // broadcast in real network layer
+	t.announce = true
+}
+
+// fence reports whether msg (a commit, snapshot or heartbeat) comes from the
+// authority, adopting msg.From as authority only for a strictly higher epoch.
+// An epoch has one authority: another node claiming ours is refused, so two
+// followers that take over at once can't both be obeyed (see rival).
+func (t *Table) fence(msg protocol.NetMessage) bool {
+	switch {
+	case msg.Epoch < t.epoch:
+		return false
+	case msg.Epoch > t.epoch:
+		if t.authority && msg.From != t.self {
+			log.Printf("table %s: %s holds epoch %d; stepping down", t.id, msg.From, msg.Epoch)
+			t.authority = false
+		}
+		t.epoch = msg.Epoch
+		t.authorityIs(msg.From)
+		return true
+	case t.authorityID == "":
+		t.authorityIs(msg.From)
+		return true
+	case msg.From == t.authorityID:
+		return true
+	}
+	t.rival(msg.From)
+	return false
+}
+
+// authorityIs records id as the table's authority, announcing a change.
+func (t *Table) authorityIs(id protocol.NodeID) {
+	if id == t.authorityID {
+		return
+	}
+	t.authorityID = id
+	t.emit(TableEvent{Kind: EvAuthorityChanged, Player: string(id)})
+}
+
+// rival settles two authorities in one epoch: the smaller node id moves to
+// a fresh epoch, which every node (the other claimant included) adopts.
+// Followers just wait for that.
+func (t *Table) rival(from protocol.NodeID) {
+	if !t.authority {
+		log.Printf("table %s: ignoring %s, not the authority for epoch %d", t.id, from, t.epoch)
+		return
+	}
+	if string(from) < string(t.self) {
+		return
+	}
+	log.Printf("table %s: %s also claims epoch %d", t.id, from, t.epoch)
+	t.assumeAuthority()
 }
 
 func (t *Table) sendHeartbeat() {
 	if !t.authority {
 		return
 	}
-	t.netOut <- protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgHeartbeat, Epoch: t.epoch, Lamport: t.clock.TickLocal(), Seq: t.seq}
+	t.netOut <- protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgHeartbeat, Epoch: t.epoch, Lamport: t.clock.TickLocal(), Seq: t.seq, TurnDeadline: unixMilli(t.turnDeadline), StateHash: t.eng.Hash()}
 }
 
 func (t *Table) isSmallestNodeID() bool {
@@ -0,0 +1,111 @@
+package table
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+// followers starts followers of a table whose authority, "z", has gone
+// quiet: each of them is already due to try a takeover.
+func followers(t *testing.T, ids ...protocol.NodeID) []*harness {
+	t.Helper()
+	var hs []*harness
+	for _, id := range ids {
+		h := newHarnessAs(t, testConfig(), id, false)
+		h.on(func(tb *Table) {
+			tb.authorityID = "z"
+			tb.lastHeartbeat = time.Time{}
+		})
+		hs = append(hs, h)
+	}
+	return hs
+}
+
+// heartbeats hands every heartbeat h has sent since the last call to the
+// others.
+func (h *harness) heartbeats(others ...*harness) {
+	for _, m := range h.sentOf(protocol.MsgHeartbeat) {
+		for _, o := range others {
+			o.recv(m)
+		}
+	}
+	h.sent = nil
+}
+
+func authorities(hs []*harness) []protocol.NodeID {
+	var out []protocol.NodeID
+	for _, h := range hs {
+		h.on(func(tb *Table) {
+			if tb.authority {
+				out = append(out, tb.self)
+			}
+		})
+	}
+	return out
+}
+
+func TestFollowersOnOneSeedDrawTheirOwnTimeouts(t *testing.T) {
+	hs := followers(t, "f1", "f2")
+	var draws [2][]time.Duration
+	for i, h := range hs {
+		h.on(func(tb *Table) {
+			for range 8 {
+				d := tb.followerTimeout()
+				if d < 3*time.Second || d >= 4500*time.Millisecond {
+					t.Errorf("timeout %v outside [3s, 4.5s)", d)
+				}
+				draws[i] = append(draws[i], d)
+			}
+		})
+	}
+	if slices.Equal(draws[0], draws[1]) {
+		t.Fatalf("two nodes on one -seed draw the same timeouts: %v", draws[0])
+	}
+}
+
+func TestTheFirstFollowerToWakeQuietsTheRest(t *testing.T) {
+	hs := followers(t, "f1", "f2", "f3")
+	hs[1].on(func(tb *Table) { tb.tryAuthorityTakeover() })
+	hs[1].heartbeats(hs[0], hs[2])
+	for _, h := range []*harness{hs[0], hs[2]} {
+		h.on(func(tb *Table) { tb.tryAuthorityTakeover() })
+	}
+	if got := authorities(hs); !slices.Equal(got, []protocol.NodeID{"f2"}) {
+		t.Fatalf("authorities %v, want just f2", got)
+	}
+}
+
+func TestRivalTakeoversConvergeOnOneAuthority(t *testing.T) {
+	hs := followers(t, "f1", "f2", "f3")
+	// f1 and f2 wake together, each claiming the same new epoch
+	hs[0].on(func(tb *Table) { tb.tryAuthorityTakeover() })
+	hs[1].on(func(tb *Table) { tb.tryAuthorityTakeover() })
+	if got := authorities(hs); len(got) != 2 {
+		t.Fatalf("authorities %v, want both claimants", got)
+	}
+	for range 4 {
+		for i, h := range hs {
+			h.heartbeats(slices.Delete(slices.Clone(hs), i, i+1)...)
+		}
+	}
+
+	if got := authorities(hs); len(got) != 1 {
+		t.Fatalf("authorities %v after the claimants heard each other, want one", got)
+	}
+	var epochs []protocol.Epoch
+	var views []protocol.NodeID
+	for _, h := range hs {
+		h.on(func(tb *Table) {
+			epochs = append(epochs, tb.epoch)
+			views = append(views, tb.authorityID)
+		})
+	}
+	for i := range hs {
+		if epochs[i] < 2 || epochs[i] != epochs[0] || views[i] != views[0] {
+			t.Fatalf("the mesh disagrees: epochs %v, authorities %v", epochs, views)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// Turn deadlines are kept by the authority and shipped to followers on every
+// commit and heartbeat (NetMessage.TurnDeadline), so all clients count down
+// against the authority's clock.
+
+// refreshDeadline (authority only) restarts the decision clock whenever a new
+// decision begins: a new hand, a new street, or a different player to act.
+func (t *Table) refreshDeadline(restart bool) {
+	if t.cfg.TurnTimeout <= 0 || !t.eng.HandActive {
+		t.turnDeadline = time.Time{}
+		t.turnOf = ""
+		return
+	}
+	cur := t.eng.CurrentPlayer()
+	if !restart && !t.turnDeadline.IsZero() && cur == t.turnOf && t.eng.Phase == t.turnPhase {
+		return
+	}
+	t.turnOf, t.turnPhase = cur, t.eng.Phase
+	t.turnDeadline = t.now().Add(t.cfg.TurnTimeout)
+}
+
+// onTurnTimeout (authority) acts for a player whose clock ran out: a check
+// when that is legal, otherwise a fold. It is committed like any other action,
+// tagged Meta["timeout"], so followers apply the same move.
+func (t *Table) onTurnTimeout() {
+	if !t.eng.HandActive || t.turnDeadline.IsZero() || t.now().Before(t.turnDeadline) {
+		return
+	}
+	player := t.eng.CurrentPlayer()
+	typ := protocol.ActFold
+	for _, la := range t.eng.LegalActions(player) {
+		if la.Move == engine.MoveCheck {
+			typ = protocol.ActCheck
+		}
+	}
+	// a fresh clock starts once the move applies
+	t.turnDeadline = time.Time{}
+	a := t.localAction(typ, player, 0)
+	a.Meta = map[string]any{"timeout": true}
+	t.logf("table %s: %s timed out; %s", t.id, player, typ)
+	t.commitAndBroadcast(a)
+}
+
+// TurnDeadline returns when the current actor must act (zero if no timer is running).
+func (t *Table) TurnDeadline() (deadline time.Time) {
+	t.exec(func() { deadline = t.turnDeadline })
+	return deadline
+}
+
+func unixMilli(ts time.Time) int64 {
+	if ts.IsZero() {
+		return 0
+	}
+	return ts.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// ForceAction commits a FOLD or CHECK on behalf of an away player to unstick
+// the table (operator tool). Authority only; player must be the one to act,
+// and a forced check is only allowed when they face no bet. The commit is
+// tagged Meta["forced"] and goes through the normal commit path.
+func (t *Table) ForceAction(player string, typ protocol.ActionType) error {
+	var err error
+	t.exec(func() { err = t.force(player, typ) })
+	return err
+}
+
+func (t *Table) force(player string, typ protocol.ActionType) error {
+	if !t.authority {
+		return errors.New("force: not the authority")
+	}
+	var move engine.Move
+	switch typ {
+	case protocol.ActFold:
+		move = engine.MoveFold
+	case protocol.ActCheck:
+		move = engine.MoveCheck
+	default:
+		return fmt.Errorf("force: can only fold or check, not %s", typ)
+	}
+	if !t.eng.HandActive || t.eng.CurrentPlayer() != player {
+		return fmt.Errorf("force: it is not %s's turn", player)
+	}
+	legal := false
+	for _, la := range t.eng.LegalActions(player) {
+		legal = legal || la.Move == move
+	}
+	if !legal {
+		return fmt.Errorf("force: %s is not legal for %s now", move, player)
+	}
+	a := t.localAction(typ, player, 0)
+	a.Meta = map[string]any{"forced": true}
+	log.Printf("table %s: forcing %s for %s", t.id, typ, player)
+	return t.commitAndBroadcast(a)
+}
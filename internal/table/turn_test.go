@@ -0,0 +1,146 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+func TestTurnClockActsForThePlayerWhenItRunsOut(t *testing.T) {
+	cfg := testConfig()
+	cfg.TurnTimeout = 30 * time.Second
+	h := newHarness(t, cfg)
+	clk := h.useFakeClock()
+	h.join("a", "b")
+	h.must(protocol.ActStartHand, "me", 0)
+
+	var first string
+	var deadline time.Time
+	h.on(func(tb *Table) { first, deadline = tb.eng.CurrentPlayer(), tb.turnDeadline })
+	if want := clk.now().Add(30 * time.Second); !deadline.Equal(want) {
+		t.Fatalf("deadline %v, want %v", deadline, want)
+	}
+
+	clk.advance(29 * time.Second)
+	h.on(func(tb *Table) { tb.onTurnTimeout() })
+	if st := h.seat(first); st.Folded {
+		t.Fatal("folded before the clock ran out")
+	}
+
+	clk.advance(time.Second)
+	h.on(func(tb *Table) { tb.onTurnTimeout() })
+	// preflop the small blind faces the big blind: no check, so a fold
+	if st := h.seat(first); !st.Folded {
+		t.Fatalf("%s not folded when their clock ran out", first)
+	}
+	commits := h.sentOf(protocol.MsgCommit)
+	last := commits[len(commits)-1]
+	for _, m := range commits {
+		if m.Action.Type == protocol.ActFold {
+			last = m
+		}
+	}
+	if last.Action.PlayerID != first || last.Action.Meta["timeout"] != true {
+		t.Fatalf("want a FOLD for %s tagged timeout, got %+v", first, last.Action)
+	}
+}
+
+func TestDeadlineTimerReusesOneTimer(t *testing.T) {
+	var d deadlineTimer
+	now := time.Now()
+	at := now.Add(time.Hour)
+	c1 := d.wait(at, now)
+	c2 := d.wait(at, now)
+	if c1 != c2 || d.timer == nil {
+		t.Fatal("same deadline re-armed a new timer")
+	}
+	timer := d.timer
+	d.wait(now.Add(time.Millisecond), now)
+	if d.timer != timer {
+		t.Fatal("moving the deadline replaced the timer")
+	}
+	select {
+	case <-d.timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("re-armed timer never fired")
+	}
+	if d.wait(time.Time{}, now) != nil {
+		t.Fatal("zero deadline should never fire")
+	}
+}
+
+func TestTurnEventsAndViewsCarryTheDeadline(t *testing.T) {
+	cfg := testConfig()
+	cfg.TurnTimeout = 30 * time.Second
+	h := newHarness(t, cfg)
+	f := newHarnessAs(t, cfg, "f", false)
+	clk := h.useFakeClock()
+	h.join("a", "b")
+	turns := h.tb.Subscribe(EvTurnChanged)
+	h.must(protocol.ActStartHand, "me", 0)
+
+	clk.advance(5 * time.Second)
+	began := clk.now()
+	caller := h.actTurn(protocol.ActCall, 0)
+	want := began.Add(30 * time.Second)
+
+	evs := drainEvents(turns)
+	if len(evs) == 0 {
+		t.Fatal("no TURN_CHANGED events")
+	}
+	if ev := evs[len(evs)-1]; ev.Turn == caller || !ev.Deadline.Equal(want) {
+		t.Fatalf("TURN_CHANGED to %s with deadline %v, want the next player's turn until %v", ev.Turn, ev.Deadline, want)
+	}
+	if v := h.tb.ViewFor("a"); !v.TurnDeadline.Equal(want) || v.TurnRemaining != 30*time.Second {
+		t.Fatalf("view: deadline %v, %v left; want %v, 30s", v.TurnDeadline, v.TurnRemaining, want)
+	}
+	if d := h.tb.TurnDeadline(); !d.Equal(want) {
+		t.Fatalf("TurnDeadline() = %v, want %v", d, want)
+	}
+
+	// followers count down to the authority's deadline, not their own
+	h.relay(f)
+	if v := f.tb.ViewFor("a"); !v.TurnDeadline.Equal(want.Truncate(time.Millisecond)) {
+		t.Fatalf("follower's view: deadline %v, want %v", v.TurnDeadline, want)
+	}
+}
+
+func TestForceFoldUnsticksAStalledPlayer(t *testing.T) {
+	h := newHarness(t, testConfig())
+	h.join("a", "b", "c")
+	h.must(protocol.ActStartHand, "me", 0)
+	stalled := h.current()
+	var other string
+	for _, p := range []string{"a", "b", "c"} {
+		if p != stalled {
+			other = p
+		}
+	}
+
+	if err := h.tb.ForceAction(other, protocol.ActFold); err == nil {
+		t.Fatalf("forced a fold for %s out of turn", other)
+	}
+	if err := h.tb.ForceAction(stalled, protocol.ActCheck); err == nil {
+		t.Fatal("forced a check facing the big blind")
+	}
+	if err := h.tb.ForceAction(stalled, protocol.ActFold); err != nil {
+		t.Fatal(err)
+	}
+	if st := h.seat(stalled); !st.Folded {
+		t.Fatalf("%s not folded", stalled)
+	}
+	if next := h.current(); next == stalled || next == "" {
+		t.Fatalf("turn is %q after the forced fold", next)
+	}
+	var last protocol.Action
+	h.on(func(tb *Table) { last = tb.log[len(tb.log)-1] })
+	if last.Type != protocol.ActFold || last.PlayerID != stalled || last.Meta["forced"] != true {
+		t.Fatalf("last commit %+v, want %s's forced FOLD", last, stalled)
+	}
+
+	f := newHarnessAs(t, testConfig(), "f", false)
+	if err := f.tb.ForceAction(stalled, protocol.ActFold); err == nil {
+		t.Fatal("a follower forced an action")
+	}
+}
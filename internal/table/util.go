@@ -1,16 +1,37 @@
 package table
 
 import (
+	"fmt"
 	"hash/fnv"
+	"strconv"
 	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
 )
 
+// seedFromActionID is the shuffle seed of a START_HAND committed without a
+// "seed" in Meta (older authorities).
 func seedFromActionID(id string) int64 {
 	h := fnv.New64a()
 	_, _ = h.Write([]byte(id))
 	return int64(h.Sum64())
 }
 
+// metaCards decodes and validates the card list stored under key in a.Meta.
+// All card payloads on actions must pass through here before touching the engine.
+func metaCards(a protocol.Action, key string) ([]engine.Card, error) {
+	v, ok := a.Meta[key]
+	if !ok {
+		return nil, fmt.Errorf("action %s: missing %q", a.Type, key)
+	}
+	cards, err := engine.DecodeCards(v)
+	if err != nil {
+		return nil, fmt.Errorf("action %s: %q: %w", a.Type, key, err)
+	}
+	return cards, nil
+}
+
 func contains(ss []string, x string) bool {
 	for _, s := range ss {
 		if s == x {
@@ -36,3 +57,34 @@ func maxDur(a, b time.Duration) time.Duration {
 	}
 	return b
 }
+
+// handSeed is the shuffle seed a START_HAND carries, stamped by the authority
+// at commit (see stampSeed). A string, so it survives JSON intact.
+func handSeed(a protocol.Action) int64 {
+	if v, ok := a.Meta["seed"].(string); ok {
+		if seed, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return seed
+		}
+	}
+	return seedFromActionID(a.ID)
+}
+
+// stampSeed gives a START_HAND its shuffle seed as the authority commits it;
+// followers replay the same deck from the committed action, so every node
+// replicating the table can work out the deck as soon as it is dealt. The seed
+// is not a secret from the nodes, only from whoever follows the table through
+// its events (see RevealSeed). Any seed the proposer supplied is replaced, so
+// nobody but the authority picks the deck. CommitReveal tables deal from the
+// players' seeds instead (see seeds.go).
+func (t *Table) stampSeed(a protocol.Action) protocol.Action {
+	if a.Type != protocol.ActStartHand || t.cfg.CommitReveal {
+		return a
+	}
+	meta := make(map[string]any, len(a.Meta)+1)
+	for k, v := range a.Meta {
+		meta[k] = v
+	}
+	meta["seed"] = strconv.FormatInt(t.ids.Seed(), 10)
+	a.Meta = meta
+	return a
+}
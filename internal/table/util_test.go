@@ -0,0 +1,38 @@
+package table
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestMetaCardsValidatesTheActionPayload(t *testing.T) {
+	// a SHOW as a peer's proposal arrives: Meta after a JSON round-trip
+	var a protocol.Action
+	if err := json.Unmarshal([]byte(`{"type":"SHOW","meta":{"cards":["As","10h"]}}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	got, err := metaCards(a, "cards")
+	if err != nil || len(got) != 2 || got[0] != (engine.Card{Rank: engine.RankAce, Suit: engine.SuitSpades}) ||
+		got[1] != (engine.Card{Rank: engine.RankTen, Suit: engine.SuitHearts}) {
+		t.Fatalf("metaCards = %v, %v; want As Th", got, err)
+	}
+
+	for _, tc := range []struct {
+		meta map[string]any
+		want string
+	}{
+		{nil, "missing"},
+		{map[string]any{"cards": []any{"As", "Zz"}}, "invalid"},
+		{map[string]any{"cards": []any{"As", "As"}}, "duplicate"},
+		{map[string]any{"cards": "As"}, "decode"},
+	} {
+		a := protocol.Action{Type: protocol.ActShow, Meta: tc.meta}
+		if got, err := metaCards(a, "cards"); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Meta %v: %v, %v; want a %q error", tc.meta, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package table
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"p2poker/internal/protocol"
+)
+
+// proposeWaitFrom starts f.tb.ProposeWait(a) and returns its verdict channel
+// once a is on its way to the authority.
+func proposeWaitFrom(t *testing.T, f *harness, a protocol.Action, timeout time.Duration) (<-chan error, protocol.NetMessage) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- f.tb.ProposeWait(a, timeout) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, m := range f.sentOf(protocol.MsgPropose) {
+			if m.Action.ID == a.ID {
+				return done, m
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%s %s never proposed", a.Type, a.ID)
+	return nil, protocol.NetMessage{}
+}
+
+func verdictOf(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("ProposeWait still waiting")
+		return nil
+	}
+}
+
+func TestProposeWaitReportsTheAuthoritysVerdict(t *testing.T) {
+	h := newHarness(t, testConfig())
+	f := newHarnessAs(t, testConfig(), "f", false)
+	f.on(func(tb *Table) { tb.authorityID = "me" })
+
+	join := protocol.Action{ID: "f-1", Type: protocol.ActJoin, PlayerID: "f"}
+	done, msg := proposeWaitFrom(t, f, join, time.Minute)
+	h.recv(msg)
+	h.relay(f)
+	if err := verdictOf(t, done); err != nil {
+		t.Fatalf("committed JOIN: %v", err)
+	}
+
+	kick := protocol.Action{ID: "f-2", Type: protocol.ActKick, PlayerID: "f", Meta: map[string]any{"target": "me"}}
+	done, msg = proposeWaitFrom(t, f, kick, time.Minute)
+	h.recv(msg)
+	for _, r := range h.sentOf(protocol.MsgReject) {
+		f.recv(r)
+	}
+	var rej *RejectedError
+	if err := verdictOf(t, done); !errors.As(err, &rej) || rej.Reason != reasonAuthorityOnly {
+		t.Fatalf("follower's KICK: want a RejectedError (%s), got %v", reasonAuthorityOnly, err)
+	}
+
+	check := protocol.Action{ID: "f-3", Type: protocol.ActCheck, PlayerID: "f"}
+	done, _ = proposeWaitFrom(t, f, check, 10*time.Millisecond)
+	if err := verdictOf(t, done); !errors.Is(err, ErrNoVerdict) {
+		t.Fatalf("unanswered CHECK: want ErrNoVerdict, got %v", err)
+	}
+	f.on(func(tb *Table) {
+		if len(tb.verdicts) != 0 {
+			t.Errorf("waiters left behind: %v", tb.verdicts)
+		}
+	})
+}
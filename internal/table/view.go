@@ -0,0 +1,89 @@
+package table
+
+import (
+	"time"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+// View is what one participant may see of the table: public state plus only
+// their own hole cards (everyone's at an OpenCards training table).
+type View struct {
+	Table     protocol.TableID
+	Seq       uint64
+	Epoch     protocol.Epoch
+	Authority protocol.NodeID
+
+	engine.Summary
+	Board   []engine.Card
+	Upcards map[engine.PlayerID][]engine.Card `json:",omitempty"`
+	Holes   []engine.Card                     `json:",omitempty"` // viewer's own cards only
+
+	// AllHoles is every player's hole cards; only set at OpenCards tables.
+	AllHoles map[engine.PlayerID][]engine.Card `json:",omitempty"`
+
+	TurnDeadline  time.Time     // zero when no turn timer is running
+	TurnRemaining time.Duration // convenience: time left until TurnDeadline
+
+	// Display amounts per TableConfig.FormatChips (currency when ChipValue is set).
+	PotDisplay   string
+	StackDisplay map[engine.PlayerID]string
+
+	Joinable   bool   // whether the viewer could join now (see JoinStatus)
+	JoinReason string // why not, when !Joinable
+}
+
+// ViewFor builds the view for viewer.
+func (t *Table) ViewFor(viewer protocol.NodeID) View {
+	v := View{
+		Table:        t.id,
+		Seq:          t.seq,
+		Epoch:        t.epoch,
+		Authority:    t.authorityID,
+		Summary:      t.eng.Summary(),
+		Board:        append([]engine.Card{}, t.eng.Board...),
+		TurnDeadline: t.turnDeadline,
+	}
+	if len(t.eng.Upcards) > 0 {
+		v.Upcards = make(map[engine.PlayerID][]engine.Card, len(t.eng.Upcards))
+		for pid, cs := range t.eng.Upcards {
+			v.Upcards[pid] = append([]engine.Card{}, cs...)
+		}
+	}
+	if hc, ok := t.eng.Holes[string(viewer)]; ok {
+		v.Holes = append([]engine.Card{}, hc...)
+	}
+	if t.cfg.OpenCards {
+		v.AllHoles = t.openHoles()
+	}
+	v.PotDisplay = t.cfg.FormatChips(v.Pot)
+	v.StackDisplay = make(map[engine.PlayerID]string, len(v.Seats))
+	for _, sv := range v.Seats {
+		v.StackDisplay[sv.Player] = t.cfg.FormatChips(sv.Stack)
+	}
+	v.Joinable, v.JoinReason = t.JoinStatus(viewer)
+	if !t.turnDeadline.IsZero() {
+		if rem := t.turnDeadline.Sub(t.now()); rem > 0 {
+			v.TurnRemaining = rem
+		}
+	}
+	return v
+}
+
+// openHoles copies every player's hole cards, for OpenCards tables only.
+func (t *Table) openHoles() map[engine.PlayerID][]engine.Card {
+	out := make(map[engine.PlayerID][]engine.Card, len(t.eng.Holes))
+	for pid, cs := range t.eng.Holes {
+		out[pid] = append([]engine.Card{}, cs...)
+	}
+	return out
+}
+
+// Results reports each seated player's session result (stack less everything
+// they bought in).
+func (t *Table) Results() []engine.Result {
+	var out []engine.Result
+	t.exec(func() { out = t.eng.Results() })
+	return out
+}
@@ -0,0 +1,120 @@
+package table
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"p2poker/internal/netx"
+	"p2poker/internal/protocol"
+)
+
+// The write-ahead log (cfg.LogPath) is the table's committed history on disk,
+// in the wire codec's frames: a SNAPSHOT of the table as the log was started,
+// then a COMMIT per action in seq order, and a fresh SNAPSHOT whenever a
+// follower resyncs. Replay rebuilds the table from it. Give every table its
+// own path and start the log with the table, so replay begins between hands
+// (snapshots carry hole cards but not the undealt deck).
+
+// openWAL opens cfg.LogPath for appending as Run starts, writing the opening
+// snapshot if the file is new. A log that cannot be opened is reported and
+// skipped: the table plays on without one.
+func (t *Table) openWAL() {
+	if t.cfg.LogPath == "" || t.wal != nil {
+		return
+	}
+	f, err := os.OpenFile(t.cfg.LogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		log.Printf("table %s: action log disabled: %v", t.id, err)
+		return
+	}
+	t.wal = f
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		t.walSnapshot()
+	}
+}
+
+func (t *Table) closeWAL() {
+	if t.wal != nil {
+		_ = t.wal.Close()
+		t.wal = nil
+	}
+}
+
+// walCommit appends the action just committed at t.seq.
+func (t *Table) walCommit(a protocol.Action) {
+	t.walWrite(protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgCommit, Epoch: t.epoch, Seq: t.seq, Action: &a})
+}
+
+// walSnapshot appends the table's full state (hole cards included: the log
+// is local and never sent to peers).
+func (t *Table) walSnapshot() {
+	ss := t.buildSnapshot(t.eng.FullSnapshot())
+	t.walWrite(protocol.NetMessage{Table: t.id, From: t.self, Type: protocol.MsgSnapshot, Epoch: t.epoch, Seq: t.seq, State: &ss})
+}
+
+func (t *Table) walWrite(msg protocol.NetMessage) {
+	if t.wal == nil {
+		return
+	}
+	frame, err := netx.Encode(msg)
+	if err == nil {
+		_, err = t.wal.Write(frame)
+	}
+	if err != nil {
+		log.Printf("table %s: action log disabled: %v", t.id, err)
+		t.closeWAL()
+	}
+}
+
+// Replay rebuilds the table from a log written at cfg.LogPath, installing
+// each snapshot and re-applying each commit in order, exactly as it first
+// ran: follow-ups and timers are not regenerated, since the log holds every
+// commit they produced. Call it on a table built with New, before Run. A log
+// cut short by a crash replays up to its last whole frame.
+func (t *Table) Replay(r io.Reader) error {
+	authority := t.authority
+	t.authority = false
+	defer func() { t.authority = authority }()
+
+	br := bufio.NewReader(r)
+	for {
+		msg, err := netx.Decode(br)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("replay: seq %d: %w", t.seq+1, err)
+		}
+		if msg.Table != t.id {
+			return fmt.Errorf("replay: log entry for table %s", msg.Table)
+		}
+		switch msg.Type {
+		case protocol.MsgSnapshot:
+			if msg.State == nil {
+				return fmt.Errorf("replay: empty snapshot at seq %d", msg.Seq)
+			}
+			path := t.cfg.LogPath // the log may have moved since it was written
+			if err := t.installSnapshot(*msg.State); err != nil {
+				return fmt.Errorf("replay: %w", err)
+			}
+			t.cfg.LogPath = path
+		case protocol.MsgCommit:
+			if msg.Action == nil || msg.Seq != t.seq+1 {
+				return fmt.Errorf("replay: expected seq %d, log has %d", t.seq+1, msg.Seq)
+			}
+			t.epoch = msg.Epoch
+			t.seq = msg.Seq
+			_ = t.apply(*msg.Action) // older logs hold refused actions too; they replay the same way
+			t.record(*msg.Action)
+		}
+	}
+	t.followups = t.followups[:0]
+	if authority {
+		t.authorityID = t.self
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package table
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"p2poker/internal/engine"
+	"p2poker/internal/protocol"
+)
+
+func TestReplayingTheLogRebuildsTheTable(t *testing.T) {
+	cfg := testConfig()
+	cfg.LogPath = filepath.Join(t.TempDir(), "t1.log")
+	h := newHarness(t, cfg)
+	h.join("a", "b", "c")
+	h.must(protocol.ActStartHand, "me", 0)
+	h.actTurn(protocol.ActRaise, 6)
+	h.actTurn(protocol.ActCall, 0)
+	h.actTurn(protocol.ActFold, 0)
+	for active := true; active; h.on(func(tb *Table) { active = tb.eng.HandActive }) {
+		h.actTurn(protocol.ActCheck, 0)
+	}
+	h.must(protocol.ActStartHand, "me", 0) // and stop mid-hand
+	h.actTurn(protocol.ActCall, 0)
+
+	var seq uint64
+	var sum engine.Summary
+	var deck []engine.Card
+	h.on(func(tb *Table) { seq, sum, deck = tb.seq, tb.eng.Summary(), slices.Clone(tb.eng.Deck) })
+	h.tb.Stop()
+	h.tb.exec(func() {})
+
+	f, err := os.Open(cfg.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	in := make(chan protocol.NetMessage, 1)
+	out := make(chan protocol.NetMessage, 256)
+	r := New("t1", "me", cfg, true, 0, &protocol.Lamport{}, protocol.NewIDGen(rand.NewSource(2)), in, out)
+	if err := r.Replay(f); err != nil {
+		t.Fatal(err)
+	}
+	if r.seq != seq || !reflect.DeepEqual(r.eng.Summary(), sum) || !slices.Equal(r.eng.Deck, deck) {
+		t.Fatalf("replayed to seq %d, %+v\nwant seq %d, %+v", r.seq, r.eng.Summary(), seq, sum)
+	}
+
+	// the rebuilt table carries on as the authority
+	go r.Run()
+	defer r.Stop()
+	var p string
+	r.exec(func() { p = r.eng.CurrentPlayer() })
+	if err := r.ProposeSync(protocol.Action{ID: "after-replay", Type: protocol.ActCall, PlayerID: p}); err != nil {
+		t.Fatalf("%s's call after the replay: %v", p, err)
+	}
+}
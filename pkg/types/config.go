@@ -1,14 +1,62 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// BlindLevel is one step of a tournament blind schedule.
+type BlindLevel struct {
+	Level        int // the number shown to players
+	SB, BB, Ante int64
+	Duration     time.Duration // how long the level lasts; the last level lasts for good
+}
 
 // TableConfig holds per-table runtime configuration that can be serialized
 // and shared via snapshots. Keep this struct stable and backward-compatible.
 type TableConfig struct {
-	Name          string
-	MinBuyin      int64
-	SmallBlind    int64
-	BigBlind      int64
-	AuthorityTick time.Duration
-	FollowerTO    time.Duration
+	Name             string
+	MinBuyin         int64
+	MaxBuyin         int64 // largest buy-in a join may choose, and the most a rebuy tops a stack up to; 0 = 100 big blinds (at least MinBuyin)
+	SmallBlind       int64
+	BigBlind         int64
+	AuthorityTick    time.Duration
+	FollowerTO       time.Duration
+	GapTimeout       time.Duration // follower: how long a gap in the commit stream may stay open before asking for a snapshot (0 = 1s)
+	Variant          string        // "holdem" (default when empty), "omaha", "shortdeck" or "stud"
+	BettingStructure string        // "nolimit" (default when empty), "potlimit" or "fixedlimit" (small bet = big blind, big bet from the turn)
+	TurnTimeout      time.Duration // per-decision clock, then the authority checks (or folds) for the player; 0 disables
+	MaxSeats         int           // 0 = 9
+	JoinPolicy       string        // "open" (default), "between-hands" or "closed"
+	MinPlayers       int           // players needed to deal; when set, hands start automatically (0 = 2, manual start)
+	DealRoundRobin   bool          // hold'em: deal hole cards one per player per pass, like a physical deal
+	AllowRabbitHunt  bool          // let the authority reveal the undealt board after a hand (display only)
+	OpenCards        bool          // training: every hole card is public (snapshots, events, views)
+	BurnCards        bool          // burn a card before each street, as a live dealer does
+	Straddles        int           // hold'em: straddles posted from UTG outward, each double the last
+	ButtonStraddle   bool          // hold'em: the button straddles too (double again) and acts last preflop
+	HiLo             bool          // split pots: half to the best high, half to the best 8-or-better low (high scoops without one)
+	SitOutBlinds     bool          // hold'em: sitting-out players post the blinds when they reach them (then fold) instead of being skipped
+	ChipValue        int64         // chips per currency unit for display (e.g. 100 = one cent a chip); 0 shows plain chips
+	HandLog          string        // "verbose" (default): log every action; "summary": one line per hand
+	RevealSeed       bool          // emit each hand's shuffle seed as an event once it ends, so event consumers can verify the deal
+	CommitReveal     bool          // shuffle from seeds every player commits to and then reveals, instead of one the authority picks
+	SeedTimeout      time.Duration // CommitReveal: how long the players due a commitment or reveal have before the authority sits them out (0 = 30s)
+	LogPath          string        // append every committed action here (see table.Replay); empty = no log
+	BlindSchedule    []BlindLevel  // tournament: blinds and ante rise level by level, replacing SmallBlind/BigBlind once play starts
+}
+
+// FormatChips renders a chip count for display: as dollars when ChipValue is
+// set (e.g. ChipValue=100 shows 1250 chips as "$12.50"), otherwise as a plain
+// count. The engine itself only ever deals in integer chips.
+func (c TableConfig) FormatChips(n int64) string {
+	if c.ChipValue <= 0 {
+		return fmt.Sprint(n)
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	cents := n * 100 / c.ChipValue
+	return fmt.Sprintf("%s$%d.%02d", sign, cents/100, cents%100)
 }
@@ -0,0 +1,25 @@
+package types
+
+import "testing"
+
+func TestFormatChips(t *testing.T) {
+	for _, tc := range []struct {
+		value, n int64
+		want     string
+	}{
+		{0, 1250, "1250"},     // no chip value: plain chips
+		{100, 1250, "$12.50"}, // a cent a chip
+		{100, 5, "$0.05"},
+		{1, 7, "$7.00"},       // a dollar a chip
+		{4, 3, "$0.75"},       // four chips to the dollar
+		{3, 1, "$0.33"},       // fractions of a cent are dropped
+		{100, -250, "-$2.50"}, // a loss
+		{0, -250, "-250"},
+		{100, 0, "$0.00"},
+	} {
+		cfg := TableConfig{ChipValue: tc.value}
+		if got := cfg.FormatChips(tc.n); got != tc.want {
+			t.Errorf("%d chips at %d a unit: %q, want %q", tc.n, tc.value, got, tc.want)
+		}
+	}
+}